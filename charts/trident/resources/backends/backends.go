@@ -0,0 +1,90 @@
+package backends
+
+import (
+	"bytes"
+	_ "embed"
+	"text/template"
+)
+
+//go:embed backends.yaml.tpl
+var backendsTemplate string
+
+// Backends are the TridentBackendConfigs and StorageClasses of a shoot, all backends of the SVM of the project share
+// the management LIF and the credentials secret.
+type Backends struct {
+	ManagementLif string
+	SecretName    string
+	// StoragePrefix is the prefix of the volume names of all backends, Trident uses its default if empty
+	StoragePrefix  string
+	Backends       []Backend
+	StorageClasses []StorageClass
+}
+
+type Backend struct {
+	// ConfigName is the name of the TridentBackendConfig
+	ConfigName        string
+	Name              string
+	StorageDriverName string
+	// SANType is only set for the ontap-san driver
+	SANType string
+	Pools   []Pool
+	// ExportPolicy is only set for the ontap-nas driver, the policy is managed by the extension and limits the exports
+	// to the nodes of the shoot
+	ExportPolicy string
+	// SnapshotPolicy and SnapshotReserve are the defaults of all pools, Trident defaults to no snapshots without reserve
+	SnapshotPolicy  string
+	SnapshotReserve string
+	// ManagementLif and SecretName override the management LIF and the credentials secret of the backends for the
+	// backends of another SVM
+	ManagementLif string
+	SecretName    string
+	// SVM is the name of the SVM of the backend, Trident derives it from the management LIF if empty
+	SVM string
+}
+
+// Pool is a virtual storage pool of a backend, StorageClasses select it by its label and provisioning type.
+type Pool struct {
+	Label string
+	Value string
+	// LUKS is only rendered for backends with a SANType
+	LUKS bool
+	// SpaceReserve is volume for pools of thick provisioned volumes, Trident defaults to none
+	SpaceReserve string
+	// QoSTier is the value of the qos label, it is only set if StorageClasses of the backend use QoS tiers
+	QoSTier string
+	// QoSPolicy and AdaptiveQoSPolicy are the QoS policy group the volumes of the pool are assigned to, at most one is set
+	QoSPolicy         string
+	AdaptiveQoSPolicy string
+	// SnapshotPolicy overrides the snapshot policy of the backend for the pool of a QoS tier
+	SnapshotPolicy string
+}
+
+type StorageClass struct {
+	Name string
+	// NoCleanup keeps the StorageClass if the shoot is deleted
+	NoCleanup bool
+	// Default marks the StorageClass as the default StorageClass of the shoot
+	Default          bool
+	BackendType      string
+	ProvisioningType string
+	// Selector selects the virtual storage pools of the backend
+	Selector string
+	FSType   string
+	// Encrypted passes the LUKS passphrase of the namespace of the claim to the node
+	Encrypted bool
+	// ReclaimPolicy and VolumeBindingMode are left to the Kubernetes defaults if empty
+	ReclaimPolicy     string
+	VolumeBindingMode string
+	MountOptions      []string
+}
+
+func Parse(backends Backends) (string, error) {
+	tmpl := template.Must(template.New("backends").Parse(string(backendsTemplate)))
+	var result bytes.Buffer
+
+	err := tmpl.Execute(&result, backends)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,113 @@
+{{- range .Backends }}
+---
+apiVersion: trident.netapp.io/v1
+kind: TridentBackendConfig
+metadata:
+  name: {{ .ConfigName }}
+  namespace: kube-system
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+spec:
+  version: 1
+  backendName: {{ .Name }}
+  storageDriverName: {{ .StorageDriverName }}
+  {{- if .SANType }}
+  sanType: {{ .SANType }}
+  {{- end }}
+  managementLIF: {{ or .ManagementLif $.ManagementLif }}
+  {{- if .SVM }}
+  svm: {{ .SVM }}
+  {{- end }}
+  credentials:
+    name: {{ or .SecretName $.SecretName }}
+  {{- if $.StoragePrefix }}
+  storagePrefix: {{ $.StoragePrefix }}
+  {{- end }}
+  {{- if .ExportPolicy }}
+  exportPolicy: {{ .ExportPolicy }}
+  {{- end }}
+  {{- if or .SnapshotPolicy .SnapshotReserve }}
+  defaults:
+    {{- if .SnapshotPolicy }}
+    snapshotPolicy: {{ .SnapshotPolicy }}
+    {{- end }}
+    {{- if .SnapshotReserve }}
+    snapshotReserve: "{{ .SnapshotReserve }}"
+    {{- end }}
+  {{- end }}
+  {{- $san := .SANType }}
+  {{- if .Pools }}
+  storage:
+  {{- range .Pools }}
+  - defaults:
+      {{- if $san }}
+      luksEncryption: "{{ .LUKS }}"
+      {{- end }}
+      {{- if .SpaceReserve }}
+      spaceReserve: {{ .SpaceReserve }}
+      {{- end }}
+      {{- if .QoSPolicy }}
+      qosPolicy: {{ .QoSPolicy }}
+      {{- end }}
+      {{- if .AdaptiveQoSPolicy }}
+      adaptiveQosPolicy: {{ .AdaptiveQoSPolicy }}
+      {{- end }}
+      {{- if .SnapshotPolicy }}
+      snapshotPolicy: {{ .SnapshotPolicy }}
+      {{- end }}
+    {{- if or .Label .QoSTier }}
+    labels:
+      {{- if .Label }}
+      {{ .Label }}: "{{ .Value }}"
+      {{- end }}
+      {{- if .QoSTier }}
+      qos: "{{ .QoSTier }}"
+      {{- end }}
+    {{- end }}
+  {{- end }}
+  {{- end }}
+{{- end }}
+{{- range .StorageClasses }}
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: {{ .Name }}
+  {{- if .NoCleanup }}
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+  {{- end }}
+  {{- if .Default }}
+  annotations:
+    storageclass.kubernetes.io/is-default-class: "true"
+  {{- end }}
+provisioner: csi.trident.netapp.io
+parameters:
+  backendType: "{{ .BackendType }}"
+  provisioningType: "{{ .ProvisioningType }}"
+  {{- if .Selector }}
+  selector: "{{ .Selector }}"
+  {{- end }}
+  {{- if .FSType }}
+  fsType: "{{ .FSType }}"
+  {{- end }}
+  {{- if .Encrypted }}
+  csi.storage.k8s.io/node-expand-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-expand-secret-namespace: ${pvc.namespace}
+  csi.storage.k8s.io/node-stage-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-stage-secret-namespace: ${pvc.namespace}
+  {{- end }}
+allowVolumeExpansion: true
+{{- if .ReclaimPolicy }}
+reclaimPolicy: {{ .ReclaimPolicy }}
+{{- end }}
+{{- if .VolumeBindingMode }}
+volumeBindingMode: {{ .VolumeBindingMode }}
+{{- end }}
+{{- if .MountOptions }}
+mountOptions:
+{{- range .MountOptions }}
+- "{{ . }}"
+{{- end }}
+{{- end }}
+{{- end }}
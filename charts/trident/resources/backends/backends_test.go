@@ -0,0 +1,241 @@
+package backends_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	"go.yaml.in/yaml/v3"
+)
+
+var expected = `---
+apiVersion: trident.netapp.io/v1
+kind: TridentBackendConfig
+metadata:
+  name: ontap-p1-backend
+  namespace: kube-system
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+spec:
+  version: 1
+  backendName: ontap-p1
+  storageDriverName: ontap-san
+  sanType: nvme
+  managementLIF: 192.168.0.1
+  credentials:
+    name: p1-credentials
+  storagePrefix: p1_myshoot_
+  defaults:
+    snapshotPolicy: shoot--p1--a-snapshots
+    snapshotReserve: "10"
+  storage:
+  - labels:
+      luks: "true"
+    defaults:
+      luksEncryption: "true"
+  - labels:
+      luks: "false"
+    defaults:
+      luksEncryption: "false"
+  - labels:
+      luks: "true"
+      qos: "gold"
+    defaults:
+      luksEncryption: "true"
+      spaceReserve: volume
+      qosPolicy: p1-gold
+      snapshotPolicy: shoot--p1--a-snapshots-gold
+---
+apiVersion: trident.netapp.io/v1
+kind: TridentBackendConfig
+metadata:
+  name: ontap-p1-backend-nfs
+  namespace: kube-system
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+spec:
+  version: 1
+  backendName: ontap-p1-nfs
+  storageDriverName: ontap-nas
+  managementLIF: 192.168.0.1
+  svm: p1-mc
+  credentials:
+    name: p1-credentials
+  storagePrefix: p1_myshoot_
+  exportPolicy: shoot--p1--a
+  storage:
+  - defaults:
+      spaceReserve: none
+  - defaults:
+      spaceReserve: volume
+      adaptiveQosPolicy: p1-silver
+    labels:
+      qos: "silver"
+---
+apiVersion: trident.netapp.io/v1
+kind: TridentBackendConfig
+metadata:
+  name: ontap-p1-backend-replica
+  namespace: kube-system
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+spec:
+  version: 1
+  backendName: ontap-p1-replica
+  storageDriverName: ontap-san
+  sanType: nvme
+  managementLIF: 192.168.1.1
+  credentials:
+    name: p1-replica-credentials
+  storagePrefix: p1_myshoot_
+  storage:
+  - labels:
+      replica: "nvme"
+    defaults:
+      luksEncryption: "false"
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: ontap-gold
+  labels:
+    shoot.gardener.cloud/no-cleanup: "true"
+provisioner: csi.trident.netapp.io
+parameters:
+  backendType: "ontap-san"
+  provisioningType: "thin"
+  selector: "luks=false"
+  fsType: "ext4"
+allowVolumeExpansion: true
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: ontap-encrypted
+provisioner: csi.trident.netapp.io
+parameters:
+  backendType: "ontap-san"
+  provisioningType: "thin"
+  selector: "luks=true"
+  fsType: "ext4"
+  csi.storage.k8s.io/node-expand-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-expand-secret-namespace: ${pvc.namespace}
+  csi.storage.k8s.io/node-stage-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-stage-secret-namespace: ${pvc.namespace}
+allowVolumeExpansion: true
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: ontap-nfs
+provisioner: csi.trident.netapp.io
+parameters:
+  backendType: "ontap-nas"
+  provisioningType: "thin"
+allowVolumeExpansion: true
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: ontap-xfs-thick
+  annotations:
+    storageclass.kubernetes.io/is-default-class: "true"
+provisioner: csi.trident.netapp.io
+parameters:
+  backendType: "ontap-san"
+  provisioningType: "thick"
+  selector: "luks=true"
+  fsType: "xfs"
+  csi.storage.k8s.io/node-expand-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-expand-secret-namespace: ${pvc.namespace}
+  csi.storage.k8s.io/node-stage-secret-name: storage-encryption-key
+  csi.storage.k8s.io/node-stage-secret-namespace: ${pvc.namespace}
+allowVolumeExpansion: true
+reclaimPolicy: Retain
+volumeBindingMode: WaitForFirstConsumer
+mountOptions:
+- "discard"
+`
+
+func TestParse(t *testing.T) {
+	got, err := backends.Parse(backends.Backends{
+		ManagementLif: "192.168.0.1",
+		SecretName:    "p1-credentials",
+		StoragePrefix: "p1_myshoot_",
+		Backends: []backends.Backend{
+			{
+				ConfigName:        "ontap-p1-backend",
+				Name:              "ontap-p1",
+				StorageDriverName: "ontap-san",
+				SANType:           "nvme",
+				SnapshotPolicy:    "shoot--p1--a-snapshots",
+				SnapshotReserve:   "10",
+				Pools: []backends.Pool{
+					{Label: "luks", Value: "true", LUKS: true},
+					{Label: "luks", Value: "false", LUKS: false},
+					{Label: "luks", Value: "true", LUKS: true, SpaceReserve: "volume", QoSTier: "gold", QoSPolicy: "p1-gold", SnapshotPolicy: "shoot--p1--a-snapshots-gold"},
+				},
+			},
+			{
+				ConfigName:        "ontap-p1-backend-nfs",
+				Name:              "ontap-p1-nfs",
+				StorageDriverName: "ontap-nas",
+				SVM:               "p1-mc",
+				ExportPolicy:      "shoot--p1--a",
+				Pools:             []backends.Pool{{SpaceReserve: "none"}, {SpaceReserve: "volume", QoSTier: "silver", AdaptiveQoSPolicy: "p1-silver"}},
+			},
+			{
+				ConfigName:        "ontap-p1-backend-replica",
+				Name:              "ontap-p1-replica",
+				StorageDriverName: "ontap-san",
+				SANType:           "nvme",
+				ManagementLif:     "192.168.1.1",
+				SecretName:        "p1-replica-credentials",
+				Pools:             []backends.Pool{{Label: "replica", Value: "nvme"}},
+			},
+		},
+		StorageClasses: []backends.StorageClass{
+			{Name: "ontap-gold", NoCleanup: true, BackendType: "ontap-san", ProvisioningType: "thin", Selector: "luks=false", FSType: "ext4"},
+			{Name: "ontap-encrypted", BackendType: "ontap-san", ProvisioningType: "thin", Selector: "luks=true", FSType: "ext4", Encrypted: true},
+			{Name: "ontap-nfs", BackendType: "ontap-nas", ProvisioningType: "thin"},
+			{
+				Name:              "ontap-xfs-thick",
+				Default:           true,
+				BackendType:       "ontap-san",
+				ProvisioningType:  "thick",
+				Selector:          "luks=true",
+				FSType:            "xfs",
+				Encrypted:         true,
+				ReclaimPolicy:     "Retain",
+				VolumeBindingMode: "WaitForFirstConsumer",
+				MountOptions:      []string{"discard"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if diff := cmp.Diff(decodeAll(t, expected), decodeAll(t, got)); diff != "" {
+		t.Errorf("Parse() diff %s", diff)
+	}
+}
+
+func decodeAll(t *testing.T, docs string) []map[string]any {
+	var result []map[string]any
+	dec := yaml.NewDecoder(bytes.NewBufferString(docs))
+	for {
+		doc := map[string]any{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatalf("unable to unmarshal:%v", err)
+		}
+		result = append(result, doc)
+	}
+}
@@ -7,6 +7,9 @@ spec:
   egress:
   - to:
     - cidr: "{{ .ManagementLif }}/32"
+    {{- if .ReplicaManagementLif }}
+    - cidr: "{{ .ReplicaManagementLif }}/32"
+    {{- end }}
     ports:
     - protocol: TCP
       port: 443
@@ -15,5 +18,7 @@ spec:
     - cidr: "{{ . }}/32"
     {{ end -}}
     ports:
+    {{- range .DataPorts }}
     - protocol: TCP
-      port: 4420
+      port: {{ . }}
+    {{- end }}
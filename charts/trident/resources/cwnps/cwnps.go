@@ -9,15 +9,27 @@ import (
 //go:embed cwnp.yaml.tpl
 var cwnpTemplate string
 
+// defaultDataPorts allow NVMe/TCP to the data LIFs.
+var defaultDataPorts = []int{4420}
+
 type CWNP struct {
 	ManagementLif string
-	DataLifs      []string
+	// ReplicaManagementLif is the management LIF of the secondary SVM volumes are mirrored to, its data LIFs are
+	// part of the DataLifs
+	ReplicaManagementLif string
+	DataLifs             []string
+	// DataPorts are the TCP ports of the protocols served by the data LIFs, defaults to NVMe/TCP
+	DataPorts []int
 }
 
 func ParseCWNP(cwnp CWNP) (string, error) {
 	tmpl := template.Must(template.New("cwnp").Parse(string(cwnpTemplate)))
 	var result bytes.Buffer
 
+	if len(cwnp.DataPorts) == 0 {
+		cwnp.DataPorts = defaultDataPorts
+	}
+
 	err := tmpl.Execute(&result, cwnp)
 	if err != nil {
 		return "", err
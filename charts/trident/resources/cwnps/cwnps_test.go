@@ -30,6 +30,49 @@ spec:
       port: 4420
 `
 
+var expectedPorts = `apiVersion: metal-stack.io/v1
+kind: ClusterwideNetworkPolicy
+metadata:
+  namespace: firewall
+  name: allow-to-ontap
+spec:
+  egress:
+  - to:
+    - cidr: "192.168.0.1/32"
+    ports:
+    - protocol: TCP
+      port: 443
+  - to:
+    - cidr: "192.168.0.2/32"
+    - cidr: "192.168.0.3/32"
+    ports:
+    - protocol: TCP
+      port: 4420
+    - protocol: TCP
+      port: 3260
+`
+
+var expectedReplica = `apiVersion: metal-stack.io/v1
+kind: ClusterwideNetworkPolicy
+metadata:
+  namespace: firewall
+  name: allow-to-ontap
+spec:
+  egress:
+  - to:
+    - cidr: "192.168.0.1/32"
+    - cidr: "192.168.1.1/32"
+    ports:
+    - protocol: TCP
+      port: 443
+  - to:
+    - cidr: "192.168.0.2/32"
+    - cidr: "192.168.1.2/32"
+    ports:
+    - protocol: TCP
+      port: 4420
+`
+
 func TestParseCWNP(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -43,6 +86,18 @@ func TestParseCWNP(t *testing.T) {
 			want:    expected,
 			wantErr: false,
 		},
+		{
+			name:    "cwnp with data ports",
+			cwnp:    cwnps.CWNP{ManagementLif: "192.168.0.1", DataLifs: []string{"192.168.0.2", "192.168.0.3"}, DataPorts: []int{4420, 3260}},
+			want:    expectedPorts,
+			wantErr: false,
+		},
+		{
+			name:    "cwnp with replica",
+			cwnp:    cwnps.CWNP{ManagementLif: "192.168.0.1", ReplicaManagementLif: "192.168.1.1", DataLifs: []string{"192.168.0.2", "192.168.1.2"}},
+			want:    expectedReplica,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -15,6 +15,9 @@ type Secrets struct {
 	Project   string
 	Username  string
 	Password  string
+	// ClientCertificate and ClientPrivateKey are base64 encoded, the password is omitted if they are set
+	ClientCertificate string
+	ClientPrivateKey  string
 }
 
 func Parse(secrets Secrets) (string, error) {
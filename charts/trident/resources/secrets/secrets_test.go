@@ -25,6 +25,22 @@ stringData:
   password: a-password
 `
 
+var expectedCertificate = `apiVersion: v1
+kind: Secret
+metadata:
+  name: a-secret
+  namespace: kube-system
+  labels:
+    app.kubernetes.io/part-of: gardener-extension-ontap
+    app.kubernetes.io/managed-by: gardener
+    ontap.metal-stack.io/project-id: project-a
+type: Opaque
+stringData:
+  username: a-user
+  clientCertificate: Y2VydA==
+  clientPrivateKey: a2V5
+`
+
 func TestParse(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -44,6 +60,19 @@ func TestParse(t *testing.T) {
 			want:    expected,
 			wantErr: false,
 		},
+		{
+			name: "client certificate",
+			secret: secrets.Secrets{
+				Name:              "a-secret",
+				Namespace:         "kube-system",
+				Project:           "project-a",
+				Username:          "a-user",
+				ClientCertificate: "Y2VydA==",
+				ClientPrivateKey:  "a2V5",
+			},
+			want:    expectedCertificate,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
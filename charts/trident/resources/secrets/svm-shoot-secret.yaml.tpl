@@ -10,4 +10,9 @@ metadata:
 type: Opaque
 stringData:
   username: "{{ .Username }}"
+{{- if .ClientCertificate }}
+  clientCertificate: "{{ .ClientCertificate }}"
+  clientPrivateKey: "{{ .ClientPrivateKey }}"
+{{- else }}
   password: "{{ .Password }}"
+{{- end }}
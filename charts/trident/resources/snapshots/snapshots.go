@@ -0,0 +1,35 @@
+package snapshots
+
+import (
+	"bytes"
+	_ "embed"
+	"text/template"
+)
+
+//go:embed snapshots.yaml.tpl
+var snapshotsTemplate string
+
+// Snapshots are the default snapshot classes of Trident in the shoot and the roles which allow namespace admins and
+// editors to use the optional snapshot features.
+type Snapshots struct {
+	VolumeSnapshotClass string
+	// DeletionPolicy is Delete or Retain
+	DeletionPolicy string
+	// VolumeGroupSnapshotClass is only rendered if group snapshots are enabled, GroupSnapshotAPIVersion is the version
+	// of the groupsnapshot API served by the shoot
+	VolumeGroupSnapshotClass string
+	GroupSnapshotAPIVersion  string
+	// InPlaceRestore allows to create TridentActionSnapshotRestores
+	InPlaceRestore bool
+}
+
+func Parse(snapshots Snapshots) (string, error) {
+	tmpl := template.Must(template.New("snapshots").Parse(string(snapshotsTemplate)))
+	var result bytes.Buffer
+
+	err := tmpl.Execute(&result, snapshots)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,59 @@
+apiVersion: snapshot.storage.k8s.io/v1
+kind: VolumeSnapshotClass
+metadata:
+  name: {{ .VolumeSnapshotClass }}
+  annotations:
+    snapshot.storage.kubernetes.io/is-default-class: "true"
+driver: csi.trident.netapp.io
+deletionPolicy: {{ .DeletionPolicy }}
+{{- if .VolumeGroupSnapshotClass }}
+---
+apiVersion: {{ .GroupSnapshotAPIVersion }}
+kind: VolumeGroupSnapshotClass
+metadata:
+  name: {{ .VolumeGroupSnapshotClass }}
+  annotations:
+    groupsnapshot.storage.kubernetes.io/is-default-class: "true"
+driver: csi.trident.netapp.io
+deletionPolicy: {{ .DeletionPolicy }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: trident-group-snapshots
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-admin: "true"
+    rbac.authorization.k8s.io/aggregate-to-edit: "true"
+rules:
+- apiGroups:
+  - groupsnapshot.storage.k8s.io
+  resources:
+  - volumegroupsnapshots
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
+{{- end }}
+{{- if .InPlaceRestore }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: trident-snapshot-restore
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-admin: "true"
+    rbac.authorization.k8s.io/aggregate-to-edit: "true"
+rules:
+- apiGroups:
+  - trident.netapp.io
+  resources:
+  - tridentactionsnapshotrestores
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
+{{- end }}
@@ -0,0 +1,124 @@
+package snapshots_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/snapshots"
+	"go.yaml.in/yaml/v3"
+)
+
+var expected = `apiVersion: snapshot.storage.k8s.io/v1
+kind: VolumeSnapshotClass
+metadata:
+  name: ontap-snapshots
+  annotations:
+    snapshot.storage.kubernetes.io/is-default-class: "true"
+driver: csi.trident.netapp.io
+deletionPolicy: Retain
+`
+
+var expectedGroupSnapshots = expected + `---
+apiVersion: groupsnapshot.storage.k8s.io/v1beta1
+kind: VolumeGroupSnapshotClass
+metadata:
+  name: ontap-group-snapshots
+  annotations:
+    groupsnapshot.storage.kubernetes.io/is-default-class: "true"
+driver: csi.trident.netapp.io
+deletionPolicy: Retain
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: trident-group-snapshots
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-admin: "true"
+    rbac.authorization.k8s.io/aggregate-to-edit: "true"
+rules:
+- apiGroups:
+  - groupsnapshot.storage.k8s.io
+  resources:
+  - volumegroupsnapshots
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: trident-snapshot-restore
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-admin: "true"
+    rbac.authorization.k8s.io/aggregate-to-edit: "true"
+rules:
+- apiGroups:
+  - trident.netapp.io
+  resources:
+  - tridentactionsnapshotrestores
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots snapshots.Snapshots
+		want      string
+	}{
+		{
+			name:      "volume snapshot class",
+			snapshots: snapshots.Snapshots{VolumeSnapshotClass: "ontap-snapshots", DeletionPolicy: "Retain"},
+			want:      expected,
+		},
+		{
+			name: "group snapshots and in-place restore",
+			snapshots: snapshots.Snapshots{
+				VolumeSnapshotClass:      "ontap-snapshots",
+				DeletionPolicy:           "Retain",
+				VolumeGroupSnapshotClass: "ontap-group-snapshots",
+				GroupSnapshotAPIVersion:  "groupsnapshot.storage.k8s.io/v1beta1",
+				InPlaceRestore:           true,
+			},
+			want: expectedGroupSnapshots,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := snapshots.Parse(tt.snapshots)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if diff := cmp.Diff(decodeAll(t, tt.want), decodeAll(t, got)); diff != "" {
+				t.Errorf("Parse() diff %s", diff)
+			}
+		})
+	}
+}
+
+func decodeAll(t *testing.T, docs string) []map[string]any {
+	var result []map[string]any
+	dec := yaml.NewDecoder(bytes.NewBufferString(docs))
+	for {
+		doc := map[string]any{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatalf("unable to unmarshal:%v", err)
+		}
+		result = append(result, doc)
+	}
+}
@@ -15,7 +15,10 @@ import (
 	heartbeatcmd "github.com/gardener/gardener/extensions/pkg/controller/heartbeat/cmd"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	ontapcmd "github.com/metal-stack/gardener-extension-ontap/pkg/cmd"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/drift"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/gc"
 	controller "github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
 
 	controllercmd "github.com/gardener/gardener/extensions/pkg/controller/cmd"
 	genericactuator "github.com/gardener/gardener/extensions/pkg/controller/controlplane/genericactuator"
@@ -149,6 +152,19 @@ func (options *Options) run(ctx context.Context) error {
 
 	ctrlConfig := options.ontapOptions.Completed()
 	ctrlConfig.Apply(&controller.DefaultAddOptions.Config)
+	ctrlConfig.Apply(&drift.DefaultAddOptions.Config)
+	ctrlConfig.Apply(&gc.DefaultAddOptions.Config)
+
+	shutdownTracing, err := tracing.Setup(ctx, controller.DefaultAddOptions.Config.Tracing)
+	if err != nil {
+		return fmt.Errorf("could not setup tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error(err, "could not shutdown tracing")
+		}
+	}()
+	log.Info("setup tracing", "enabled", controller.DefaultAddOptions.Config.Tracing != nil)
 
 	options.controllerOptions.Completed().Apply(&controller.DefaultAddOptions.ControllerOptions)
 	options.reconcileOptions.Completed().Apply(&controller.DefaultAddOptions.IgnoreOperationAnnotation, pointer.Pointer(extensionsv1alpha1.ExtensionClassShoot))
@@ -8,10 +8,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"github.com/metal-stack/gardener-extension-ontap/cmd/gardener-extension-ontap/app"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/redact"
 )
 
 func main() {
-	runtimelog.SetLogger(logger.MustNewZapLogger(logger.InfoLevel, logger.FormatJSON))
+	runtimelog.SetLogger(redact.Logger(logger.MustNewZapLogger(logger.InfoLevel, logger.FormatJSON)))
 	cmd := app.NewControllerManagerCommand(signals.SetupSignalHandler())
 
 	if err := cmd.Execute(); err != nil {
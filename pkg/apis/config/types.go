@@ -3,10 +3,15 @@ package config
 import (
 	"fmt"
 	"net/netip"
+	"slices"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	healthcheckconfig "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -20,6 +25,193 @@ type ControllerConfiguration struct {
 
 	// HealthCheckConfig is the config for the health check controller
 	HealthCheckConfig *healthcheckconfig.HealthCheckConfig
+
+	// SeedName is the name of the seed the extension runs in, it is recorded as owner of the ONTAP objects
+	SeedName string
+
+	// ShootEvents enables mirroring of lifecycle events into the kube-system namespace of the shoot
+	ShootEvents bool
+
+	// Tracing configures the export of OpenTelemetry traces, tracing is disabled if nil
+	Tracing *TracingConfig
+
+	// DriftDetection configures the periodic drift detection of SVMs, drift detection is disabled if nil
+	DriftDetection *DriftDetectionConfig
+
+	// GarbageCollection configures the garbage collection of orphaned SVMs and accounts, garbage collection is disabled if nil
+	GarbageCollection *GarbageCollectionConfig
+
+	// CredentialsRotation configures the scheduled rotation of the SVM account passwords, only rotations requested
+	// with the shoot annotation are executed if nil
+	CredentialsRotation *CredentialsRotationConfig
+
+	// PasswordPolicy configures the generated passwords of the SVM accounts
+	PasswordPolicy *PasswordPolicyConfig
+
+	// CertificateAuthentication switches the SVM accounts to client certificate authentication,
+	// the accounts authenticate with passwords if nil
+	CertificateAuthentication *CertificateAuthenticationConfig
+
+	// AccountRole is the ONTAP role of the SVM accounts of the shoots
+	AccountRole AccountRole
+
+	// Naming configures the names of the objects created by the extension, the names of earlier versions are used if nil
+	Naming *NamingConfig
+
+	// StorageClasses are the StorageClasses of shoots which do not declare any, the built-in StorageClasses are used if empty
+	StorageClasses []ontapv1alpha1.StorageClass
+
+	// QoSTiers are the QoS tiers StorageClasses can reference
+	QoSTiers []QoSTier
+
+	// SVMLimits are the default limits of the SVMs of the projects, the SVMs are unlimited if nil
+	SVMLimits *ontapv1alpha1.SVMLimits
+
+	// SVMDR enables SVM disaster recovery for sites without MetroCluster, disaster recovery is disabled if nil
+	SVMDR *SVMDRConfig
+}
+
+// QoSTier defines the performance limits of each volume of the StorageClasses which reference it.
+type QoSTier struct {
+	// Name is the name StorageClasses reference the tier with
+	Name string
+	// Fixed limits the volumes to fixed IOPS and throughput
+	Fixed *FixedQoS
+	// Adaptive scales the IOPS limits with the size of the volumes
+	Adaptive *AdaptiveQoS
+}
+
+// FixedQoS are fixed IOPS and throughput limits, zero values are unlimited.
+type FixedQoS struct {
+	// MaxIOPS is the maximum IOPS of a volume
+	MaxIOPS int64
+	// MinIOPS are the IOPS guaranteed to a volume
+	MinIOPS int64
+	// MaxThroughputMBps is the maximum throughput of a volume in MB/s
+	MaxThroughputMBps int64
+	// MinThroughputMBps is the throughput guaranteed to a volume in MB/s
+	MinThroughputMBps int64
+}
+
+// AdaptiveQoS are IOPS limits per TB of a volume.
+type AdaptiveQoS struct {
+	// ExpectedIOPSPerTB are the IOPS per TB of allocated space guaranteed to a volume
+	ExpectedIOPSPerTB int64
+	// PeakIOPSPerTB is the maximum IOPS per TB of used space of a volume
+	PeakIOPSPerTB int64
+	// AbsoluteMinIOPS are the IOPS guaranteed to small volumes
+	AbsoluteMinIOPS int64
+}
+
+// DriftPolicy defines how detected drift is handled.
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport only reports detected drift with metrics and events.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyRepair reports detected drift and repairs it.
+	DriftPolicyRepair DriftPolicy = "Repair"
+)
+
+// AccountRole defines the ONTAP role of the SVM accounts.
+type AccountRole string
+
+const (
+	// AccountRoleRestricted is a custom SVM-scoped REST role which only allows the API paths Trident needs.
+	AccountRoleRestricted AccountRole = "Restricted"
+	// AccountRoleVsadmin is the built-in vsadmin role with full administrative control over the SVM,
+	// it is only meant for troubleshooting.
+	AccountRoleVsadmin AccountRole = "Vsadmin"
+)
+
+// DriftDetectionConfig configures the periodic drift detection of SVMs.
+type DriftDetectionConfig struct {
+	// Interval is the duration between two drift detection runs
+	Interval metav1.Duration
+	// Policy defines how detected drift is handled
+	Policy DriftPolicy
+}
+
+// GarbageCollectionConfig configures the garbage collection of orphaned SVMs and accounts.
+type GarbageCollectionConfig struct {
+	// Interval is the duration between two garbage collection runs
+	Interval metav1.Duration
+	// GracePeriod is the duration an object must be orphaned before it is removed
+	GracePeriod metav1.Duration
+	// Remove enables the removal of orphaned accounts, LIFs and empty SVMs, otherwise orphans are only reported
+	Remove bool
+	// DryRun only logs the removals instead of executing them
+	DryRun bool
+}
+
+// CredentialsRotationConfig configures the rotation of the SVM account passwords.
+type CredentialsRotationConfig struct {
+	// Interval is the duration after which the password is rotated in the next maintenance window of the shoot
+	Interval metav1.Duration
+	// BackendTimeout is the duration to wait for the Trident backend to be online again after a rotation
+	BackendTimeout metav1.Duration
+}
+
+// PasswordPolicyConfig configures the generated passwords of the SVM accounts.
+// The remaining characters after the minimums of all character classes are drawn from all classes.
+type PasswordPolicyConfig struct {
+	// Length is the number of characters of a password
+	Length int
+	// Digits is the minimum number of digits
+	Digits int
+	// Symbols is the minimum number of symbols
+	Symbols int
+	// Uppercase is the minimum number of upper case letters
+	Uppercase int
+	// Lowercase is the minimum number of lower case letters
+	Lowercase int
+	// SymbolSet are the symbols passwords are generated with, symbols are not used if empty
+	SymbolSet string
+}
+
+// CertificateAuthenticationConfig configures the client certificates of the SVM accounts.
+type CertificateAuthenticationConfig struct {
+	// CASecretNamespace is the namespace in the seed of the secret of the CA the client certificates are issued from
+	CASecretNamespace string
+	// CAValidity is the duration the generated CA is valid
+	CAValidity metav1.Duration
+	// Validity is the duration a client certificate is valid
+	Validity metav1.Duration
+	// RenewBefore is the duration before the expiration at which a client certificate is renewed
+	RenewBefore metav1.Duration
+}
+
+// NamingConfig contains Go templates for the names of the objects created by the extension, empty templates keep
+// the names of earlier versions. Objects with the names of earlier versions are still found after a template changed.
+type NamingConfig struct {
+	// SVM is the template of the SVM name of a project, it is rendered with .ProjectID, the project id without dashes
+	SVM string
+	// DataLIF is the template of the data LIF names of an SVM, it is rendered with .SVMName and .Index
+	DataLIF string
+	// ManagementLIF is the template of the management LIF name of an SVM, it is rendered with .SVMName
+	ManagementLIF string
+	// Backend is the template of the Trident backend name of a shoot, it is rendered with .SVMName
+	Backend string
+	// Secret is the template of the credentials secret name of a shoot, it is rendered with .SVMName and .ShootNamespace
+	Secret string
+}
+
+// TracingConfig configures the export of OpenTelemetry traces.
+type TracingConfig struct {
+	// Endpoint is the host:port of the OTLP gRPC collector
+	Endpoint string
+	// Insecure disables TLS for the connection to the collector
+	Insecure bool
+}
+
+// SVMDRConfig configures the SnapMirror SVM-DR relationships of the SVMs to the DR cluster.
+type SVMDRConfig struct {
+	// Cluster is the name of the cluster of Clusters the DR SVMs are created on, no SVMs of projects are placed on it
+	Cluster string
+	// Policy is the SnapMirror policy of the relationships
+	Policy string
+	// Schedule is the name of the schedule on the DR cluster which updates the relationships, it is optional
+	Schedule string
 }
 
 type Cluster struct {
@@ -48,5 +240,136 @@ func (c *ControllerConfiguration) Validate() error {
 		}
 	}
 
+	if c.Tracing != nil && c.Tracing.Endpoint == "" {
+		return fmt.Errorf("tracing endpoint must be provided if tracing is configured")
+	}
+
+	switch c.AccountRole {
+	case AccountRoleRestricted, AccountRoleVsadmin:
+	default:
+		return fmt.Errorf("unsupported account role %q, must be one of %s, %s", c.AccountRole, AccountRoleRestricted, AccountRoleVsadmin)
+	}
+
+	if c.DriftDetection != nil {
+		if c.DriftDetection.Interval.Duration <= 0 {
+			return fmt.Errorf("drift detection interval must be positive")
+		}
+		switch c.DriftDetection.Policy {
+		case DriftPolicyReport, DriftPolicyRepair:
+		default:
+			return fmt.Errorf("unsupported drift detection policy %q, must be one of %s, %s", c.DriftDetection.Policy, DriftPolicyReport, DriftPolicyRepair)
+		}
+	}
+
+	if c.GarbageCollection != nil {
+		if c.GarbageCollection.Interval.Duration <= 0 {
+			return fmt.Errorf("garbage collection interval must be positive")
+		}
+		if c.GarbageCollection.GracePeriod.Duration < 0 {
+			return fmt.Errorf("garbage collection grace period must not be negative")
+		}
+	}
+
+	if c.CredentialsRotation != nil {
+		if c.CredentialsRotation.Interval.Duration <= 0 {
+			return fmt.Errorf("credentials rotation interval must be positive")
+		}
+		if c.CredentialsRotation.BackendTimeout.Duration <= 0 {
+			return fmt.Errorf("credentials rotation backend timeout must be positive")
+		}
+	}
+
+	if p := c.PasswordPolicy; p != nil {
+		if p.Length <= 0 {
+			return fmt.Errorf("password length must be positive")
+		}
+		if p.Digits < 0 || p.Symbols < 0 || p.Uppercase < 0 || p.Lowercase < 0 {
+			return fmt.Errorf("password character class minimums must not be negative")
+		}
+		if p.Digits+p.Symbols+p.Uppercase+p.Lowercase > p.Length {
+			return fmt.Errorf("password length %d is shorter than the sum of the character class minimums", p.Length)
+		}
+		if p.Symbols > 0 && p.SymbolSet == "" {
+			return fmt.Errorf("password symbol set must not be empty if symbols are required")
+		}
+	}
+
+	if a := c.CertificateAuthentication; a != nil {
+		if a.CASecretNamespace == "" {
+			return fmt.Errorf("certificate authentication CA secret namespace must be provided")
+		}
+		if a.Validity.Duration <= 0 || a.CAValidity.Duration < a.Validity.Duration {
+			return fmt.Errorf("certificate authentication validity must be positive and not exceed the CA validity")
+		}
+		if a.RenewBefore.Duration <= 0 || a.RenewBefore.Duration >= a.Validity.Duration {
+			return fmt.Errorf("certificate authentication renewal must be positive and shorter than the validity")
+		}
+	}
+
+	if err := ontapv1alpha1.ValidateStorageClasses(c.StorageClasses, ""); err != nil {
+		return fmt.Errorf("invalid storage classes: %w", err)
+	}
+
+	if err := c.SVMLimits.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateQoSTiers(); err != nil {
+		return fmt.Errorf("invalid qos tiers: %w", err)
+	}
+
+	if dr := c.SVMDR; dr != nil {
+		if !slices.ContainsFunc(c.Clusters, func(cluster Cluster) bool { return cluster.Name == dr.Cluster }) {
+			return fmt.Errorf("svm-dr cluster %q is not configured", dr.Cluster)
+		}
+		if len(c.Clusters) < 2 {
+			return fmt.Errorf("svm-dr requires another cluster besides the DR cluster %q", dr.Cluster)
+		}
+		if dr.Policy == "" {
+			return fmt.Errorf("svm-dr policy must be provided")
+		}
+	}
+
+	return nil
+}
+
+func (c *ControllerConfiguration) validateQoSTiers() error {
+	tiers := map[string]bool{}
+	for i, t := range c.QoSTiers {
+		if msgs := validation.IsDNS1123Label(t.Name); len(msgs) > 0 {
+			return fmt.Errorf("tier at index %d has an invalid name %q: %s", i, t.Name, strings.Join(msgs, ", "))
+		}
+		if t.Name == ontapv1alpha1.NoQoSTier {
+			return fmt.Errorf("tier name %q is reserved", t.Name)
+		}
+		if tiers[t.Name] {
+			return fmt.Errorf("tier %q is given more than once", t.Name)
+		}
+		tiers[t.Name] = true
+
+		switch {
+		case (t.Fixed == nil) == (t.Adaptive == nil):
+			return fmt.Errorf("tier %q must set exactly one of fixed and adaptive", t.Name)
+		case t.Fixed != nil:
+			f := t.Fixed
+			if f.MaxIOPS < 0 || f.MinIOPS < 0 || f.MaxThroughputMBps < 0 || f.MinThroughputMBps < 0 {
+				return fmt.Errorf("tier %q must not have negative limits", t.Name)
+			}
+			if (f.MaxIOPS > 0 && f.MinIOPS > f.MaxIOPS) || (f.MaxThroughputMBps > 0 && f.MinThroughputMBps > f.MaxThroughputMBps) {
+				return fmt.Errorf("tier %q must not guarantee more than its maximum", t.Name)
+			}
+		case t.Adaptive != nil:
+			a := t.Adaptive
+			if a.ExpectedIOPSPerTB <= 0 || a.PeakIOPSPerTB < a.ExpectedIOPSPerTB || a.AbsoluteMinIOPS < 0 {
+				return fmt.Errorf("tier %q must have positive expected IOPS which do not exceed the peak IOPS", t.Name)
+			}
+		}
+	}
+
+	for _, sc := range c.StorageClasses {
+		if sc.QoSTier != "" && !tiers[sc.QoSTier] {
+			return fmt.Errorf("storage class %q references unknown tier %q", sc.Name, sc.QoSTier)
+		}
+	}
 	return nil
 }
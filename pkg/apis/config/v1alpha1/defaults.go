@@ -1,9 +1,85 @@
 package v1alpha1
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
 	return RegisterDefaults(scheme)
 }
+
+// SetDefaults_DriftDetectionConfig sets the defaults of the drift detection.
+func SetDefaults_DriftDetectionConfig(obj *DriftDetectionConfig) {
+	if obj.Interval.Duration == 0 {
+		obj.Interval = metav1.Duration{Duration: 10 * time.Minute}
+	}
+	if obj.Policy == "" {
+		obj.Policy = DriftPolicyReport
+	}
+}
+
+// SetDefaults_GarbageCollectionConfig sets the defaults of the garbage collection.
+func SetDefaults_GarbageCollectionConfig(obj *GarbageCollectionConfig) {
+	if obj.Interval.Duration == 0 {
+		obj.Interval = metav1.Duration{Duration: time.Hour}
+	}
+	if obj.GracePeriod.Duration == 0 {
+		obj.GracePeriod = metav1.Duration{Duration: 24 * time.Hour}
+	}
+}
+
+// SetDefaults_CredentialsRotationConfig sets the defaults of the credentials rotation.
+func SetDefaults_CredentialsRotationConfig(obj *CredentialsRotationConfig) {
+	if obj.Interval.Duration == 0 {
+		obj.Interval = metav1.Duration{Duration: 30 * 24 * time.Hour}
+	}
+	if obj.BackendTimeout.Duration == 0 {
+		obj.BackendTimeout = metav1.Duration{Duration: 5 * time.Minute}
+	}
+}
+
+// SetDefaults_SVMDRConfig sets the defaults of the SVM-DR relationships.
+func SetDefaults_SVMDRConfig(obj *SVMDRConfig) {
+	if obj.Policy == "" {
+		obj.Policy = "MirrorAllSnapshots"
+	}
+}
+
+// SetDefaults_ControllerConfiguration sets the defaults of the controller configuration.
+func SetDefaults_ControllerConfiguration(obj *ControllerConfiguration) {
+	if obj.AccountRole == "" {
+		obj.AccountRole = AccountRoleRestricted
+	}
+	if obj.PasswordPolicy == nil {
+		obj.PasswordPolicy = &PasswordPolicyConfig{
+			Digits:    2,
+			Symbols:   2,
+			Uppercase: 2,
+			Lowercase: 2,
+			SymbolSet: "!#%+-.:=@^_",
+		}
+	}
+}
+
+// SetDefaults_PasswordPolicyConfig sets the defaults of the password policy.
+func SetDefaults_PasswordPolicyConfig(obj *PasswordPolicyConfig) {
+	if obj.Length == 0 {
+		obj.Length = 24
+	}
+}
+
+// SetDefaults_CertificateAuthenticationConfig sets the defaults of the certificate authentication.
+func SetDefaults_CertificateAuthenticationConfig(obj *CertificateAuthenticationConfig) {
+	if obj.CAValidity.Duration == 0 {
+		obj.CAValidity = metav1.Duration{Duration: 10 * 365 * 24 * time.Hour}
+	}
+	if obj.Validity.Duration == 0 {
+		obj.Validity = metav1.Duration{Duration: 90 * 24 * time.Hour}
+	}
+	if obj.RenewBefore.Duration == 0 {
+		obj.RenewBefore = metav1.Duration{Duration: 30 * 24 * time.Hour}
+	}
+}
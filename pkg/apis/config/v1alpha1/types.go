@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	healthcheckconfigv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -16,6 +18,252 @@ type ControllerConfiguration struct {
 	// HealthCheckConfig is the config for the health check controller
 	// +optional
 	HealthCheckConfig *healthcheckconfigv1alpha1.HealthCheckConfig `json:"healthCheckConfig,omitempty"`
+
+	// SeedName is the name of the seed the extension runs in, it is recorded as owner of the ONTAP objects
+	// +optional
+	SeedName string `json:"seedName,omitempty"`
+
+	// ShootEvents enables mirroring of lifecycle events into the kube-system namespace of the shoot
+	// +optional
+	ShootEvents bool `json:"shootEvents,omitempty"`
+
+	// Tracing configures the export of OpenTelemetry traces, tracing is disabled if not set
+	// +optional
+	Tracing *TracingConfig `json:"tracing,omitempty"`
+
+	// DriftDetection configures the periodic drift detection of SVMs, drift detection is disabled if not set
+	// +optional
+	DriftDetection *DriftDetectionConfig `json:"driftDetection,omitempty"`
+
+	// GarbageCollection configures the garbage collection of orphaned SVMs and accounts, garbage collection is disabled if not set
+	// +optional
+	GarbageCollection *GarbageCollectionConfig `json:"garbageCollection,omitempty"`
+
+	// CredentialsRotation configures the scheduled rotation of the SVM account passwords, only rotations requested
+	// with the shoot annotation are executed if not set
+	// +optional
+	CredentialsRotation *CredentialsRotationConfig `json:"credentialsRotation,omitempty"`
+
+	// PasswordPolicy configures the generated passwords of the SVM accounts, defaults to 24 characters with
+	// at least 2 digits, symbols, upper and lower case letters
+	// +optional
+	PasswordPolicy *PasswordPolicyConfig `json:"passwordPolicy,omitempty"`
+
+	// CertificateAuthentication switches the SVM accounts to client certificate authentication,
+	// the accounts authenticate with passwords if not set
+	// +optional
+	CertificateAuthentication *CertificateAuthenticationConfig `json:"certificateAuthentication,omitempty"`
+
+	// AccountRole is the ONTAP role of the SVM accounts of the shoots, defaults to Restricted
+	// +optional
+	AccountRole AccountRole `json:"accountRole,omitempty"`
+
+	// Naming configures the names of the objects created by the extension, the names of earlier versions are used if not set
+	// +optional
+	Naming *NamingConfig `json:"naming,omitempty"`
+
+	// StorageClasses are the StorageClasses of shoots which do not declare any, classes of protocols a shoot does not
+	// enable are skipped. The built-in StorageClasses are used if not set.
+	// +optional
+	StorageClasses []ontapv1alpha1.StorageClass `json:"storageClasses,omitempty"`
+
+	// QoSTiers are the QoS tiers StorageClasses can reference, a QoS policy group is created for each referenced tier
+	// on the SVM of the shoot
+	// +optional
+	QoSTiers []QoSTier `json:"qosTiers,omitempty"`
+
+	// SVMLimits are the default limits of the SVMs of the projects, shoots can override them. The SVMs are unlimited
+	// if not set.
+	// +optional
+	SVMLimits *ontapv1alpha1.SVMLimits `json:"svmLimits,omitempty"`
+
+	// SVMDR enables SVM disaster recovery for sites without MetroCluster, the SVMs of shoots which configure disaster
+	// recovery are replicated with SnapMirror SVM-DR to the DR cluster. Disaster recovery is disabled if not set.
+	// +optional
+	SVMDR *SVMDRConfig `json:"svmDR,omitempty"`
+}
+
+// QoSTier defines the performance limits of each volume of the StorageClasses which reference it, exactly one of
+// fixed and adaptive must be set.
+type QoSTier struct {
+	// Name is the name StorageClasses reference the tier with
+	Name string `json:"name"`
+	// Fixed limits the volumes to fixed IOPS and throughput
+	// +optional
+	Fixed *FixedQoS `json:"fixed,omitempty"`
+	// Adaptive scales the IOPS limits with the size of the volumes
+	// +optional
+	Adaptive *AdaptiveQoS `json:"adaptive,omitempty"`
+}
+
+// FixedQoS are fixed IOPS and throughput limits, zero values are unlimited.
+type FixedQoS struct {
+	// MaxIOPS is the maximum IOPS of a volume
+	// +optional
+	MaxIOPS int64 `json:"maxIOPS,omitempty"`
+	// MinIOPS are the IOPS guaranteed to a volume
+	// +optional
+	MinIOPS int64 `json:"minIOPS,omitempty"`
+	// MaxThroughputMBps is the maximum throughput of a volume in MB/s
+	// +optional
+	MaxThroughputMBps int64 `json:"maxThroughputMBps,omitempty"`
+	// MinThroughputMBps is the throughput guaranteed to a volume in MB/s
+	// +optional
+	MinThroughputMBps int64 `json:"minThroughputMBps,omitempty"`
+}
+
+// AdaptiveQoS are IOPS limits per TB of a volume.
+type AdaptiveQoS struct {
+	// ExpectedIOPSPerTB are the IOPS per TB of allocated space guaranteed to a volume
+	ExpectedIOPSPerTB int64 `json:"expectedIOPSPerTB"`
+	// PeakIOPSPerTB is the maximum IOPS per TB of used space of a volume
+	PeakIOPSPerTB int64 `json:"peakIOPSPerTB"`
+	// AbsoluteMinIOPS are the IOPS guaranteed to small volumes
+	// +optional
+	AbsoluteMinIOPS int64 `json:"absoluteMinIOPS,omitempty"`
+}
+
+// DriftPolicy defines how detected drift is handled.
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport only reports detected drift with metrics and events.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyRepair reports detected drift and repairs it.
+	DriftPolicyRepair DriftPolicy = "Repair"
+)
+
+// AccountRole defines the ONTAP role of the SVM accounts.
+type AccountRole string
+
+const (
+	// AccountRoleRestricted is a custom SVM-scoped REST role which only allows the API paths Trident needs.
+	AccountRoleRestricted AccountRole = "Restricted"
+	// AccountRoleVsadmin is the built-in vsadmin role with full administrative control over the SVM,
+	// it is only meant for troubleshooting.
+	AccountRoleVsadmin AccountRole = "Vsadmin"
+)
+
+// DriftDetectionConfig configures the periodic drift detection of SVMs.
+type DriftDetectionConfig struct {
+	// Interval is the duration between two drift detection runs, defaults to 10m
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Policy defines how detected drift is handled, defaults to Report
+	// +optional
+	Policy DriftPolicy `json:"policy,omitempty"`
+}
+
+// GarbageCollectionConfig configures the garbage collection of orphaned SVMs and accounts.
+type GarbageCollectionConfig struct {
+	// Interval is the duration between two garbage collection runs, defaults to 1h
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// GracePeriod is the duration an object must be orphaned before it is removed, defaults to 24h
+	// +optional
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+	// Remove enables the removal of orphaned accounts, LIFs and empty SVMs, otherwise orphans are only reported
+	// +optional
+	Remove bool `json:"remove,omitempty"`
+	// DryRun only logs the removals instead of executing them
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// CredentialsRotationConfig configures the rotation of the SVM account passwords.
+type CredentialsRotationConfig struct {
+	// Interval is the duration after which the password is rotated in the next maintenance window of the shoot, defaults to 720h
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// BackendTimeout is the duration to wait for the Trident backend to be online again after a rotation, defaults to 5m
+	// +optional
+	BackendTimeout metav1.Duration `json:"backendTimeout,omitempty"`
+}
+
+// PasswordPolicyConfig configures the generated passwords of the SVM accounts.
+// The remaining characters after the minimums of all character classes are drawn from all classes.
+type PasswordPolicyConfig struct {
+	// Length is the number of characters of a password, defaults to 24
+	// +optional
+	Length int `json:"length,omitempty"`
+	// Digits is the minimum number of digits
+	// +optional
+	Digits int `json:"digits,omitempty"`
+	// Symbols is the minimum number of symbols
+	// +optional
+	Symbols int `json:"symbols,omitempty"`
+	// Uppercase is the minimum number of upper case letters
+	// +optional
+	Uppercase int `json:"uppercase,omitempty"`
+	// Lowercase is the minimum number of lower case letters
+	// +optional
+	Lowercase int `json:"lowercase,omitempty"`
+	// SymbolSet are the symbols passwords are generated with, symbols are not used if empty
+	// +optional
+	SymbolSet string `json:"symbolSet,omitempty"`
+}
+
+// CertificateAuthenticationConfig configures the client certificates of the SVM accounts.
+type CertificateAuthenticationConfig struct {
+	// CASecretNamespace is the namespace in the seed of the secret of the CA the client certificates are issued from
+	CASecretNamespace string `json:"caSecretNamespace"`
+	// CAValidity is the duration the generated CA is valid, defaults to 87600h
+	// +optional
+	CAValidity metav1.Duration `json:"caValidity,omitempty"`
+	// Validity is the duration a client certificate is valid, defaults to 2160h
+	// +optional
+	Validity metav1.Duration `json:"validity,omitempty"`
+	// RenewBefore is the duration before the expiration at which a client certificate is renewed, defaults to 720h
+	// +optional
+	RenewBefore metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// NamingConfig contains Go templates for the names of the objects created by the extension, empty templates keep
+// the names of earlier versions. Objects with the names of earlier versions are still found after a template changed.
+type NamingConfig struct {
+	// SVM is the template of the SVM name of a project, it is rendered with .ProjectID, the project id without dashes.
+	// Defaults to "p{{ .ProjectID }}".
+	// +optional
+	SVM string `json:"svm,omitempty"`
+	// DataLIF is the template of the data LIF names of an SVM, it is rendered with .SVMName and .Index.
+	// Defaults to "datalif+{{ .Index }}".
+	// +optional
+	DataLIF string `json:"dataLIF,omitempty"`
+	// ManagementLIF is the template of the management LIF name of an SVM, it is rendered with .SVMName.
+	// Defaults to "managementlif".
+	// +optional
+	ManagementLIF string `json:"managementLIF,omitempty"`
+	// Backend is the template of the Trident backend name of a shoot, it is rendered with .SVMName.
+	// Defaults to "ontap-{{ .SVMName }}".
+	// +optional
+	Backend string `json:"backend,omitempty"`
+	// Secret is the template of the credentials secret name of a shoot, it is rendered with .SVMName and .ShootNamespace.
+	// Defaults to "{{ .SVMName }}-{{ trimPrefix \"shoot--\" .ShootNamespace }}-credentials".
+	// +optional
+	Secret string `json:"secret,omitempty"`
+}
+
+// TracingConfig configures the export of OpenTelemetry traces.
+type TracingConfig struct {
+	// Endpoint is the host:port of the OTLP gRPC collector
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS for the connection to the collector
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// SVMDRConfig configures the SnapMirror SVM-DR relationships of the SVMs to the DR cluster.
+type SVMDRConfig struct {
+	// Cluster is the name of the cluster of the clusters the DR SVMs are created on, no SVMs of projects are placed
+	// on it
+	Cluster string `json:"cluster"`
+	// Policy is the SnapMirror policy of the relationships, defaults to MirrorAllSnapshots
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// Schedule is the name of the schedule on the DR cluster which updates the relationships, the relationships are
+	// only updated by the schedule of the policy if not set
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
 }
 
 type Cluster struct {
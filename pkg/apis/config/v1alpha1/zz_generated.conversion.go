@@ -14,6 +14,7 @@ import (
 
 	configv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
 	config "github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -25,6 +26,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AdaptiveQoS)(nil), (*config.AdaptiveQoS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AdaptiveQoS_To_config_AdaptiveQoS(a.(*AdaptiveQoS), b.(*config.AdaptiveQoS), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AdaptiveQoS)(nil), (*AdaptiveQoS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AdaptiveQoS_To_v1alpha1_AdaptiveQoS(a.(*config.AdaptiveQoS), b.(*AdaptiveQoS), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CertificateAuthenticationConfig)(nil), (*config.CertificateAuthenticationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CertificateAuthenticationConfig_To_config_CertificateAuthenticationConfig(a.(*CertificateAuthenticationConfig), b.(*config.CertificateAuthenticationConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CertificateAuthenticationConfig)(nil), (*CertificateAuthenticationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CertificateAuthenticationConfig_To_v1alpha1_CertificateAuthenticationConfig(a.(*config.CertificateAuthenticationConfig), b.(*CertificateAuthenticationConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Cluster)(nil), (*config.Cluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Cluster_To_config_Cluster(a.(*Cluster), b.(*config.Cluster), scope)
 	}); err != nil {
@@ -45,9 +66,149 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CredentialsRotationConfig)(nil), (*config.CredentialsRotationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CredentialsRotationConfig_To_config_CredentialsRotationConfig(a.(*CredentialsRotationConfig), b.(*config.CredentialsRotationConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CredentialsRotationConfig)(nil), (*CredentialsRotationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CredentialsRotationConfig_To_v1alpha1_CredentialsRotationConfig(a.(*config.CredentialsRotationConfig), b.(*CredentialsRotationConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DriftDetectionConfig)(nil), (*config.DriftDetectionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DriftDetectionConfig_To_config_DriftDetectionConfig(a.(*DriftDetectionConfig), b.(*config.DriftDetectionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DriftDetectionConfig)(nil), (*DriftDetectionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DriftDetectionConfig_To_v1alpha1_DriftDetectionConfig(a.(*config.DriftDetectionConfig), b.(*DriftDetectionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FixedQoS)(nil), (*config.FixedQoS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FixedQoS_To_config_FixedQoS(a.(*FixedQoS), b.(*config.FixedQoS), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FixedQoS)(nil), (*FixedQoS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FixedQoS_To_v1alpha1_FixedQoS(a.(*config.FixedQoS), b.(*FixedQoS), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GarbageCollectionConfig)(nil), (*config.GarbageCollectionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GarbageCollectionConfig_To_config_GarbageCollectionConfig(a.(*GarbageCollectionConfig), b.(*config.GarbageCollectionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GarbageCollectionConfig)(nil), (*GarbageCollectionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GarbageCollectionConfig_To_v1alpha1_GarbageCollectionConfig(a.(*config.GarbageCollectionConfig), b.(*GarbageCollectionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NamingConfig)(nil), (*config.NamingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NamingConfig_To_config_NamingConfig(a.(*NamingConfig), b.(*config.NamingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NamingConfig)(nil), (*NamingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NamingConfig_To_v1alpha1_NamingConfig(a.(*config.NamingConfig), b.(*NamingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PasswordPolicyConfig)(nil), (*config.PasswordPolicyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PasswordPolicyConfig_To_config_PasswordPolicyConfig(a.(*PasswordPolicyConfig), b.(*config.PasswordPolicyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PasswordPolicyConfig)(nil), (*PasswordPolicyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PasswordPolicyConfig_To_v1alpha1_PasswordPolicyConfig(a.(*config.PasswordPolicyConfig), b.(*PasswordPolicyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*QoSTier)(nil), (*config.QoSTier)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_QoSTier_To_config_QoSTier(a.(*QoSTier), b.(*config.QoSTier), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.QoSTier)(nil), (*QoSTier)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_QoSTier_To_v1alpha1_QoSTier(a.(*config.QoSTier), b.(*QoSTier), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SVMDRConfig)(nil), (*config.SVMDRConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SVMDRConfig_To_config_SVMDRConfig(a.(*SVMDRConfig), b.(*config.SVMDRConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SVMDRConfig)(nil), (*SVMDRConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SVMDRConfig_To_v1alpha1_SVMDRConfig(a.(*config.SVMDRConfig), b.(*SVMDRConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TracingConfig)(nil), (*config.TracingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TracingConfig_To_config_TracingConfig(a.(*TracingConfig), b.(*config.TracingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TracingConfig)(nil), (*TracingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TracingConfig_To_v1alpha1_TracingConfig(a.(*config.TracingConfig), b.(*TracingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_AdaptiveQoS_To_config_AdaptiveQoS(in *AdaptiveQoS, out *config.AdaptiveQoS, s conversion.Scope) error {
+	out.ExpectedIOPSPerTB = in.ExpectedIOPSPerTB
+	out.PeakIOPSPerTB = in.PeakIOPSPerTB
+	out.AbsoluteMinIOPS = in.AbsoluteMinIOPS
+	return nil
+}
+
+// Convert_v1alpha1_AdaptiveQoS_To_config_AdaptiveQoS is an autogenerated conversion function.
+func Convert_v1alpha1_AdaptiveQoS_To_config_AdaptiveQoS(in *AdaptiveQoS, out *config.AdaptiveQoS, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AdaptiveQoS_To_config_AdaptiveQoS(in, out, s)
+}
+
+func autoConvert_config_AdaptiveQoS_To_v1alpha1_AdaptiveQoS(in *config.AdaptiveQoS, out *AdaptiveQoS, s conversion.Scope) error {
+	out.ExpectedIOPSPerTB = in.ExpectedIOPSPerTB
+	out.PeakIOPSPerTB = in.PeakIOPSPerTB
+	out.AbsoluteMinIOPS = in.AbsoluteMinIOPS
+	return nil
+}
+
+// Convert_config_AdaptiveQoS_To_v1alpha1_AdaptiveQoS is an autogenerated conversion function.
+func Convert_config_AdaptiveQoS_To_v1alpha1_AdaptiveQoS(in *config.AdaptiveQoS, out *AdaptiveQoS, s conversion.Scope) error {
+	return autoConvert_config_AdaptiveQoS_To_v1alpha1_AdaptiveQoS(in, out, s)
+}
+
+func autoConvert_v1alpha1_CertificateAuthenticationConfig_To_config_CertificateAuthenticationConfig(in *CertificateAuthenticationConfig, out *config.CertificateAuthenticationConfig, s conversion.Scope) error {
+	out.CASecretNamespace = in.CASecretNamespace
+	out.CAValidity = in.CAValidity
+	out.Validity = in.Validity
+	out.RenewBefore = in.RenewBefore
+	return nil
+}
+
+// Convert_v1alpha1_CertificateAuthenticationConfig_To_config_CertificateAuthenticationConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CertificateAuthenticationConfig_To_config_CertificateAuthenticationConfig(in *CertificateAuthenticationConfig, out *config.CertificateAuthenticationConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CertificateAuthenticationConfig_To_config_CertificateAuthenticationConfig(in, out, s)
+}
+
+func autoConvert_config_CertificateAuthenticationConfig_To_v1alpha1_CertificateAuthenticationConfig(in *config.CertificateAuthenticationConfig, out *CertificateAuthenticationConfig, s conversion.Scope) error {
+	out.CASecretNamespace = in.CASecretNamespace
+	out.CAValidity = in.CAValidity
+	out.Validity = in.Validity
+	out.RenewBefore = in.RenewBefore
 	return nil
 }
 
+// Convert_config_CertificateAuthenticationConfig_To_v1alpha1_CertificateAuthenticationConfig is an autogenerated conversion function.
+func Convert_config_CertificateAuthenticationConfig_To_v1alpha1_CertificateAuthenticationConfig(in *config.CertificateAuthenticationConfig, out *CertificateAuthenticationConfig, s conversion.Scope) error {
+	return autoConvert_config_CertificateAuthenticationConfig_To_v1alpha1_CertificateAuthenticationConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_Cluster_To_config_Cluster(in *Cluster, out *config.Cluster, s conversion.Scope) error {
 	out.Name = in.Name
 	out.IPAddress = in.IPAddress
@@ -77,6 +238,20 @@ func Convert_config_Cluster_To_v1alpha1_Cluster(in *config.Cluster, out *Cluster
 func autoConvert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(in *ControllerConfiguration, out *config.ControllerConfiguration, s conversion.Scope) error {
 	out.Clusters = *(*[]config.Cluster)(unsafe.Pointer(&in.Clusters))
 	out.HealthCheckConfig = (*configv1alpha1.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
+	out.SeedName = in.SeedName
+	out.ShootEvents = in.ShootEvents
+	out.Tracing = (*config.TracingConfig)(unsafe.Pointer(in.Tracing))
+	out.DriftDetection = (*config.DriftDetectionConfig)(unsafe.Pointer(in.DriftDetection))
+	out.GarbageCollection = (*config.GarbageCollectionConfig)(unsafe.Pointer(in.GarbageCollection))
+	out.CredentialsRotation = (*config.CredentialsRotationConfig)(unsafe.Pointer(in.CredentialsRotation))
+	out.PasswordPolicy = (*config.PasswordPolicyConfig)(unsafe.Pointer(in.PasswordPolicy))
+	out.CertificateAuthentication = (*config.CertificateAuthenticationConfig)(unsafe.Pointer(in.CertificateAuthentication))
+	out.AccountRole = config.AccountRole(in.AccountRole)
+	out.Naming = (*config.NamingConfig)(unsafe.Pointer(in.Naming))
+	out.StorageClasses = *(*[]ontapv1alpha1.StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.QoSTiers = *(*[]config.QoSTier)(unsafe.Pointer(&in.QoSTiers))
+	out.SVMLimits = (*ontapv1alpha1.SVMLimits)(unsafe.Pointer(in.SVMLimits))
+	out.SVMDR = (*config.SVMDRConfig)(unsafe.Pointer(in.SVMDR))
 	return nil
 }
 
@@ -88,6 +263,20 @@ func Convert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(
 func autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfiguration(in *config.ControllerConfiguration, out *ControllerConfiguration, s conversion.Scope) error {
 	out.Clusters = *(*[]Cluster)(unsafe.Pointer(&in.Clusters))
 	out.HealthCheckConfig = (*configv1alpha1.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
+	out.SeedName = in.SeedName
+	out.ShootEvents = in.ShootEvents
+	out.Tracing = (*TracingConfig)(unsafe.Pointer(in.Tracing))
+	out.DriftDetection = (*DriftDetectionConfig)(unsafe.Pointer(in.DriftDetection))
+	out.GarbageCollection = (*GarbageCollectionConfig)(unsafe.Pointer(in.GarbageCollection))
+	out.CredentialsRotation = (*CredentialsRotationConfig)(unsafe.Pointer(in.CredentialsRotation))
+	out.PasswordPolicy = (*PasswordPolicyConfig)(unsafe.Pointer(in.PasswordPolicy))
+	out.CertificateAuthentication = (*CertificateAuthenticationConfig)(unsafe.Pointer(in.CertificateAuthentication))
+	out.AccountRole = AccountRole(in.AccountRole)
+	out.Naming = (*NamingConfig)(unsafe.Pointer(in.Naming))
+	out.StorageClasses = *(*[]ontapv1alpha1.StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.QoSTiers = *(*[]QoSTier)(unsafe.Pointer(&in.QoSTiers))
+	out.SVMLimits = (*ontapv1alpha1.SVMLimits)(unsafe.Pointer(in.SVMLimits))
+	out.SVMDR = (*SVMDRConfig)(unsafe.Pointer(in.SVMDR))
 	return nil
 }
 
@@ -95,3 +284,227 @@ func autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfigurat
 func Convert_config_ControllerConfiguration_To_v1alpha1_ControllerConfiguration(in *config.ControllerConfiguration, out *ControllerConfiguration, s conversion.Scope) error {
 	return autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfiguration(in, out, s)
 }
+
+func autoConvert_v1alpha1_CredentialsRotationConfig_To_config_CredentialsRotationConfig(in *CredentialsRotationConfig, out *config.CredentialsRotationConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.BackendTimeout = in.BackendTimeout
+	return nil
+}
+
+// Convert_v1alpha1_CredentialsRotationConfig_To_config_CredentialsRotationConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CredentialsRotationConfig_To_config_CredentialsRotationConfig(in *CredentialsRotationConfig, out *config.CredentialsRotationConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CredentialsRotationConfig_To_config_CredentialsRotationConfig(in, out, s)
+}
+
+func autoConvert_config_CredentialsRotationConfig_To_v1alpha1_CredentialsRotationConfig(in *config.CredentialsRotationConfig, out *CredentialsRotationConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.BackendTimeout = in.BackendTimeout
+	return nil
+}
+
+// Convert_config_CredentialsRotationConfig_To_v1alpha1_CredentialsRotationConfig is an autogenerated conversion function.
+func Convert_config_CredentialsRotationConfig_To_v1alpha1_CredentialsRotationConfig(in *config.CredentialsRotationConfig, out *CredentialsRotationConfig, s conversion.Scope) error {
+	return autoConvert_config_CredentialsRotationConfig_To_v1alpha1_CredentialsRotationConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_DriftDetectionConfig_To_config_DriftDetectionConfig(in *DriftDetectionConfig, out *config.DriftDetectionConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Policy = config.DriftPolicy(in.Policy)
+	return nil
+}
+
+// Convert_v1alpha1_DriftDetectionConfig_To_config_DriftDetectionConfig is an autogenerated conversion function.
+func Convert_v1alpha1_DriftDetectionConfig_To_config_DriftDetectionConfig(in *DriftDetectionConfig, out *config.DriftDetectionConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DriftDetectionConfig_To_config_DriftDetectionConfig(in, out, s)
+}
+
+func autoConvert_config_DriftDetectionConfig_To_v1alpha1_DriftDetectionConfig(in *config.DriftDetectionConfig, out *DriftDetectionConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Policy = DriftPolicy(in.Policy)
+	return nil
+}
+
+// Convert_config_DriftDetectionConfig_To_v1alpha1_DriftDetectionConfig is an autogenerated conversion function.
+func Convert_config_DriftDetectionConfig_To_v1alpha1_DriftDetectionConfig(in *config.DriftDetectionConfig, out *DriftDetectionConfig, s conversion.Scope) error {
+	return autoConvert_config_DriftDetectionConfig_To_v1alpha1_DriftDetectionConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_FixedQoS_To_config_FixedQoS(in *FixedQoS, out *config.FixedQoS, s conversion.Scope) error {
+	out.MaxIOPS = in.MaxIOPS
+	out.MinIOPS = in.MinIOPS
+	out.MaxThroughputMBps = in.MaxThroughputMBps
+	out.MinThroughputMBps = in.MinThroughputMBps
+	return nil
+}
+
+// Convert_v1alpha1_FixedQoS_To_config_FixedQoS is an autogenerated conversion function.
+func Convert_v1alpha1_FixedQoS_To_config_FixedQoS(in *FixedQoS, out *config.FixedQoS, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FixedQoS_To_config_FixedQoS(in, out, s)
+}
+
+func autoConvert_config_FixedQoS_To_v1alpha1_FixedQoS(in *config.FixedQoS, out *FixedQoS, s conversion.Scope) error {
+	out.MaxIOPS = in.MaxIOPS
+	out.MinIOPS = in.MinIOPS
+	out.MaxThroughputMBps = in.MaxThroughputMBps
+	out.MinThroughputMBps = in.MinThroughputMBps
+	return nil
+}
+
+// Convert_config_FixedQoS_To_v1alpha1_FixedQoS is an autogenerated conversion function.
+func Convert_config_FixedQoS_To_v1alpha1_FixedQoS(in *config.FixedQoS, out *FixedQoS, s conversion.Scope) error {
+	return autoConvert_config_FixedQoS_To_v1alpha1_FixedQoS(in, out, s)
+}
+
+func autoConvert_v1alpha1_GarbageCollectionConfig_To_config_GarbageCollectionConfig(in *GarbageCollectionConfig, out *config.GarbageCollectionConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+	out.Remove = in.Remove
+	out.DryRun = in.DryRun
+	return nil
+}
+
+// Convert_v1alpha1_GarbageCollectionConfig_To_config_GarbageCollectionConfig is an autogenerated conversion function.
+func Convert_v1alpha1_GarbageCollectionConfig_To_config_GarbageCollectionConfig(in *GarbageCollectionConfig, out *config.GarbageCollectionConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GarbageCollectionConfig_To_config_GarbageCollectionConfig(in, out, s)
+}
+
+func autoConvert_config_GarbageCollectionConfig_To_v1alpha1_GarbageCollectionConfig(in *config.GarbageCollectionConfig, out *GarbageCollectionConfig, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+	out.Remove = in.Remove
+	out.DryRun = in.DryRun
+	return nil
+}
+
+// Convert_config_GarbageCollectionConfig_To_v1alpha1_GarbageCollectionConfig is an autogenerated conversion function.
+func Convert_config_GarbageCollectionConfig_To_v1alpha1_GarbageCollectionConfig(in *config.GarbageCollectionConfig, out *GarbageCollectionConfig, s conversion.Scope) error {
+	return autoConvert_config_GarbageCollectionConfig_To_v1alpha1_GarbageCollectionConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_NamingConfig_To_config_NamingConfig(in *NamingConfig, out *config.NamingConfig, s conversion.Scope) error {
+	out.SVM = in.SVM
+	out.DataLIF = in.DataLIF
+	out.ManagementLIF = in.ManagementLIF
+	out.Backend = in.Backend
+	out.Secret = in.Secret
+	return nil
+}
+
+// Convert_v1alpha1_NamingConfig_To_config_NamingConfig is an autogenerated conversion function.
+func Convert_v1alpha1_NamingConfig_To_config_NamingConfig(in *NamingConfig, out *config.NamingConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NamingConfig_To_config_NamingConfig(in, out, s)
+}
+
+func autoConvert_config_NamingConfig_To_v1alpha1_NamingConfig(in *config.NamingConfig, out *NamingConfig, s conversion.Scope) error {
+	out.SVM = in.SVM
+	out.DataLIF = in.DataLIF
+	out.ManagementLIF = in.ManagementLIF
+	out.Backend = in.Backend
+	out.Secret = in.Secret
+	return nil
+}
+
+// Convert_config_NamingConfig_To_v1alpha1_NamingConfig is an autogenerated conversion function.
+func Convert_config_NamingConfig_To_v1alpha1_NamingConfig(in *config.NamingConfig, out *NamingConfig, s conversion.Scope) error {
+	return autoConvert_config_NamingConfig_To_v1alpha1_NamingConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_PasswordPolicyConfig_To_config_PasswordPolicyConfig(in *PasswordPolicyConfig, out *config.PasswordPolicyConfig, s conversion.Scope) error {
+	out.Length = in.Length
+	out.Digits = in.Digits
+	out.Symbols = in.Symbols
+	out.Uppercase = in.Uppercase
+	out.Lowercase = in.Lowercase
+	out.SymbolSet = in.SymbolSet
+	return nil
+}
+
+// Convert_v1alpha1_PasswordPolicyConfig_To_config_PasswordPolicyConfig is an autogenerated conversion function.
+func Convert_v1alpha1_PasswordPolicyConfig_To_config_PasswordPolicyConfig(in *PasswordPolicyConfig, out *config.PasswordPolicyConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PasswordPolicyConfig_To_config_PasswordPolicyConfig(in, out, s)
+}
+
+func autoConvert_config_PasswordPolicyConfig_To_v1alpha1_PasswordPolicyConfig(in *config.PasswordPolicyConfig, out *PasswordPolicyConfig, s conversion.Scope) error {
+	out.Length = in.Length
+	out.Digits = in.Digits
+	out.Symbols = in.Symbols
+	out.Uppercase = in.Uppercase
+	out.Lowercase = in.Lowercase
+	out.SymbolSet = in.SymbolSet
+	return nil
+}
+
+// Convert_config_PasswordPolicyConfig_To_v1alpha1_PasswordPolicyConfig is an autogenerated conversion function.
+func Convert_config_PasswordPolicyConfig_To_v1alpha1_PasswordPolicyConfig(in *config.PasswordPolicyConfig, out *PasswordPolicyConfig, s conversion.Scope) error {
+	return autoConvert_config_PasswordPolicyConfig_To_v1alpha1_PasswordPolicyConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_QoSTier_To_config_QoSTier(in *QoSTier, out *config.QoSTier, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Fixed = (*config.FixedQoS)(unsafe.Pointer(in.Fixed))
+	out.Adaptive = (*config.AdaptiveQoS)(unsafe.Pointer(in.Adaptive))
+	return nil
+}
+
+// Convert_v1alpha1_QoSTier_To_config_QoSTier is an autogenerated conversion function.
+func Convert_v1alpha1_QoSTier_To_config_QoSTier(in *QoSTier, out *config.QoSTier, s conversion.Scope) error {
+	return autoConvert_v1alpha1_QoSTier_To_config_QoSTier(in, out, s)
+}
+
+func autoConvert_config_QoSTier_To_v1alpha1_QoSTier(in *config.QoSTier, out *QoSTier, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Fixed = (*FixedQoS)(unsafe.Pointer(in.Fixed))
+	out.Adaptive = (*AdaptiveQoS)(unsafe.Pointer(in.Adaptive))
+	return nil
+}
+
+// Convert_config_QoSTier_To_v1alpha1_QoSTier is an autogenerated conversion function.
+func Convert_config_QoSTier_To_v1alpha1_QoSTier(in *config.QoSTier, out *QoSTier, s conversion.Scope) error {
+	return autoConvert_config_QoSTier_To_v1alpha1_QoSTier(in, out, s)
+}
+
+func autoConvert_v1alpha1_SVMDRConfig_To_config_SVMDRConfig(in *SVMDRConfig, out *config.SVMDRConfig, s conversion.Scope) error {
+	out.Cluster = in.Cluster
+	out.Policy = in.Policy
+	out.Schedule = in.Schedule
+	return nil
+}
+
+// Convert_v1alpha1_SVMDRConfig_To_config_SVMDRConfig is an autogenerated conversion function.
+func Convert_v1alpha1_SVMDRConfig_To_config_SVMDRConfig(in *SVMDRConfig, out *config.SVMDRConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SVMDRConfig_To_config_SVMDRConfig(in, out, s)
+}
+
+func autoConvert_config_SVMDRConfig_To_v1alpha1_SVMDRConfig(in *config.SVMDRConfig, out *SVMDRConfig, s conversion.Scope) error {
+	out.Cluster = in.Cluster
+	out.Policy = in.Policy
+	out.Schedule = in.Schedule
+	return nil
+}
+
+// Convert_config_SVMDRConfig_To_v1alpha1_SVMDRConfig is an autogenerated conversion function.
+func Convert_config_SVMDRConfig_To_v1alpha1_SVMDRConfig(in *config.SVMDRConfig, out *SVMDRConfig, s conversion.Scope) error {
+	return autoConvert_config_SVMDRConfig_To_v1alpha1_SVMDRConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_TracingConfig_To_config_TracingConfig(in *TracingConfig, out *config.TracingConfig, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Insecure = in.Insecure
+	return nil
+}
+
+// Convert_v1alpha1_TracingConfig_To_config_TracingConfig is an autogenerated conversion function.
+func Convert_v1alpha1_TracingConfig_To_config_TracingConfig(in *TracingConfig, out *config.TracingConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TracingConfig_To_config_TracingConfig(in, out, s)
+}
+
+func autoConvert_config_TracingConfig_To_v1alpha1_TracingConfig(in *config.TracingConfig, out *TracingConfig, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Insecure = in.Insecure
+	return nil
+}
+
+// Convert_config_TracingConfig_To_v1alpha1_TracingConfig is an autogenerated conversion function.
+func Convert_config_TracingConfig_To_v1alpha1_TracingConfig(in *config.TracingConfig, out *TracingConfig, s conversion.Scope) error {
+	return autoConvert_config_TracingConfig_To_v1alpha1_TracingConfig(in, out, s)
+}
@@ -17,5 +17,28 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&ControllerConfiguration{}, func(obj interface{}) { SetObjectDefaults_ControllerConfiguration(obj.(*ControllerConfiguration)) })
 	return nil
 }
+
+func SetObjectDefaults_ControllerConfiguration(in *ControllerConfiguration) {
+	SetDefaults_ControllerConfiguration(in)
+	if in.DriftDetection != nil {
+		SetDefaults_DriftDetectionConfig(in.DriftDetection)
+	}
+	if in.GarbageCollection != nil {
+		SetDefaults_GarbageCollectionConfig(in.GarbageCollection)
+	}
+	if in.CredentialsRotation != nil {
+		SetDefaults_CredentialsRotationConfig(in.CredentialsRotation)
+	}
+	if in.PasswordPolicy != nil {
+		SetDefaults_PasswordPolicyConfig(in.PasswordPolicy)
+	}
+	if in.CertificateAuthentication != nil {
+		SetDefaults_CertificateAuthenticationConfig(in.CertificateAuthentication)
+	}
+	if in.SVMDR != nil {
+		SetDefaults_SVMDRConfig(in.SVMDR)
+	}
+}
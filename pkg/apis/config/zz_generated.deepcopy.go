@@ -11,9 +11,45 @@ package config
 
 import (
 	v1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptiveQoS) DeepCopyInto(out *AdaptiveQoS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveQoS.
+func (in *AdaptiveQoS) DeepCopy() *AdaptiveQoS {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptiveQoS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateAuthenticationConfig) DeepCopyInto(out *CertificateAuthenticationConfig) {
+	*out = *in
+	out.CAValidity = in.CAValidity
+	out.Validity = in.Validity
+	out.RenewBefore = in.RenewBefore
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthenticationConfig.
+func (in *CertificateAuthenticationConfig) DeepCopy() *CertificateAuthenticationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateAuthenticationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -44,6 +80,65 @@ func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 		*out = new(v1alpha1.HealthCheckConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingConfig)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionConfig)
+		**out = **in
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionConfig)
+		**out = **in
+	}
+	if in.CredentialsRotation != nil {
+		in, out := &in.CredentialsRotation, &out.CredentialsRotation
+		*out = new(CredentialsRotationConfig)
+		**out = **in
+	}
+	if in.PasswordPolicy != nil {
+		in, out := &in.PasswordPolicy, &out.PasswordPolicy
+		*out = new(PasswordPolicyConfig)
+		**out = **in
+	}
+	if in.CertificateAuthentication != nil {
+		in, out := &in.CertificateAuthentication, &out.CertificateAuthentication
+		*out = new(CertificateAuthenticationConfig)
+		**out = **in
+	}
+	if in.Naming != nil {
+		in, out := &in.Naming, &out.Naming
+		*out = new(NamingConfig)
+		**out = **in
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]ontapv1alpha1.StorageClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QoSTiers != nil {
+		in, out := &in.QoSTiers, &out.QoSTiers
+		*out = make([]QoSTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SVMLimits != nil {
+		in, out := &in.SVMLimits, &out.SVMLimits
+		*out = new(ontapv1alpha1.SVMLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SVMDR != nil {
+		in, out := &in.SVMDR, &out.SVMDR
+		*out = new(SVMDRConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -64,3 +159,162 @@ func (in *ControllerConfiguration) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsRotationConfig) DeepCopyInto(out *CredentialsRotationConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	out.BackendTimeout = in.BackendTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsRotationConfig.
+func (in *CredentialsRotationConfig) DeepCopy() *CredentialsRotationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsRotationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionConfig) DeepCopyInto(out *DriftDetectionConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionConfig.
+func (in *DriftDetectionConfig) DeepCopy() *DriftDetectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixedQoS) DeepCopyInto(out *FixedQoS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixedQoS.
+func (in *FixedQoS) DeepCopy() *FixedQoS {
+	if in == nil {
+		return nil
+	}
+	out := new(FixedQoS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionConfig) DeepCopyInto(out *GarbageCollectionConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionConfig.
+func (in *GarbageCollectionConfig) DeepCopy() *GarbageCollectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamingConfig) DeepCopyInto(out *NamingConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamingConfig.
+func (in *NamingConfig) DeepCopy() *NamingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPolicyConfig) DeepCopyInto(out *PasswordPolicyConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPolicyConfig.
+func (in *PasswordPolicyConfig) DeepCopy() *PasswordPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSTier) DeepCopyInto(out *QoSTier) {
+	*out = *in
+	if in.Fixed != nil {
+		in, out := &in.Fixed, &out.Fixed
+		*out = new(FixedQoS)
+		**out = **in
+	}
+	if in.Adaptive != nil {
+		in, out := &in.Adaptive, &out.Adaptive
+		*out = new(AdaptiveQoS)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSTier.
+func (in *QoSTier) DeepCopy() *QoSTier {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SVMDRConfig) DeepCopyInto(out *SVMDRConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SVMDRConfig.
+func (in *SVMDRConfig) DeepCopy() *SVMDRConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SVMDRConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingConfig) DeepCopyInto(out *TracingConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingConfig.
+func (in *TracingConfig) DeepCopy() *TracingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
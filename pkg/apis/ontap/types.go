@@ -1,6 +1,9 @@
 package ontap
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,6 +16,207 @@ type TridentConfig struct {
 
 	// SvmIpaddresses are the ip addresses provided for the svm to create and/or call the endpoint
 	SvmIpaddresses SvmIpaddresses
+	// Protocols are the storage protocols enabled on the SVM
+	Protocols []Protocol
+	// StorageClasses are the StorageClasses of the shoot
+	StorageClasses []StorageClass
+	// SVMLimits override the limits of the SVM of the project, the largest limits of the shoots of the project apply
+	SVMLimits *SVMLimits
+
+	// Snapshots configure the ONTAP snapshot policies and the snapshot reserve of the volumes of the shoot
+	Snapshots *SnapshotConfig
+
+	// Replication creates a secondary SVM of the project on another cluster volumes can be mirrored to
+	Replication *ReplicationConfig
+
+	// DisasterRecovery replicates the SVM of the project with SnapMirror SVM-DR to the DR cluster
+	DisasterRecovery *DisasterRecoveryConfig
+}
+
+// DisasterRecoveryConfig configures the DR SVM the SVM of the project is replicated to
+type DisasterRecoveryConfig struct {
+	// SvmIpaddresses are the endpoints of the DR SVM, Trident uses them after a failover
+	SvmIpaddresses SvmIpaddresses
+}
+
+// ReplicationConfig configures the secondary SVM volumes of the shoot are mirrored to
+type ReplicationConfig struct {
+	// SvmIpaddresses are the endpoints of the secondary SVM
+	SvmIpaddresses SvmIpaddresses
+	// StorageClass is the name of the StorageClass of the mirrored volumes of the NVMe backend
+	StorageClass string
+}
+
+// SnapshotConfig configures the snapshots of the volumes of a shoot
+type SnapshotConfig struct {
+	// Policy is the snapshot policy of the volumes of the shoot
+	Policy *SnapshotPolicy
+	// TierPolicies override the policy for the volumes of StorageClasses with the given QoS tier
+	TierPolicies []TierSnapshotPolicy
+	// Reserve is the percentage of the size of a volume reserved for snapshots
+	Reserve *int32
+	// VolumeSnapshotClass is the name of the VolumeSnapshotClass of Trident in the shoot
+	VolumeSnapshotClass string
+	// DeletionPolicy of the VolumeSnapshotClass and the VolumeGroupSnapshotClass is Delete or Retain
+	DeletionPolicy string
+	// GroupSnapshots enables crash-consistent snapshots of multiple volumes and deploys a VolumeGroupSnapshotClass
+	GroupSnapshots bool
+	// VolumeGroupSnapshotClass is the name of the VolumeGroupSnapshotClass of Trident in the shoot
+	VolumeGroupSnapshotClass string
+	// InPlaceRestore allows the admins and editors of namespaces to restore volumes from their snapshots in place
+	InPlaceRestore bool
+}
+
+// SnapshotPolicy is an ONTAP snapshot policy which is created on the SVM for the shoot
+type SnapshotPolicy struct {
+	// Schedules are the schedules snapshots are taken with and the number of snapshots retained for each
+	Schedules []SnapshotSchedule
+}
+
+// SnapshotSchedule takes snapshots with a cron schedule of the ONTAP cluster
+type SnapshotSchedule struct {
+	// Schedule is the name of a cron schedule of the ONTAP cluster
+	Schedule string
+	// Count is the number of snapshots retained
+	Count int64
+}
+
+// TierSnapshotPolicy is the snapshot policy of the volumes of the StorageClasses with a QoS tier
+type TierSnapshotPolicy struct {
+	// Tier is the name of the QoS tier
+	Tier string
+	// Schedules are the schedules snapshots are taken with and the number of snapshots retained for each
+	Schedules []SnapshotSchedule
+}
+
+// SVMLimits limit the resources the SVM of a project can consume, unset limits are unlimited
+type SVMLimits struct {
+	// StorageLimit is the maximum space the volumes of the SVM can allocate
+	StorageLimit *resource.Quantity
+	// MaxVolumes is the maximum number of volumes of the SVM
+	MaxVolumes *int64
+}
+
+// Protocol is a storage protocol the SVM serves to the shoot
+type Protocol string
+
+// ProvisioningType is the space allocation of the volumes of a StorageClass
+type ProvisioningType string
+
+// StorageClass declares a StorageClass of the shoot
+type StorageClass struct {
+	// Name is the name of the StorageClass
+	Name string
+	// Protocol is the protocol of the backend the volumes are provisioned from
+	Protocol Protocol
+	// Default marks the StorageClass as the default StorageClass of the shoot
+	Default bool
+	// FSType is the filesystem block volumes are formatted with
+	FSType string
+	// ProvisioningType is thin or thick
+	ProvisioningType ProvisioningType
+	// LUKS encrypts block volumes
+	LUKS bool
+	// ReclaimPolicy is Delete or Retain
+	ReclaimPolicy *corev1.PersistentVolumeReclaimPolicy
+	// VolumeBindingMode is Immediate or WaitForFirstConsumer
+	VolumeBindingMode *storagev1.VolumeBindingMode
+	// MountOptions are passed to the mount of the volumes
+	MountOptions []string
+	// QoSTier is the name of a QoS tier which limits the performance of each volume
+	QoSTier string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TridentStatus is the provider status of the ontap Extension
+type TridentStatus struct {
+	metav1.TypeMeta
+
+	// Credentials contains the state of the SVM account credentials of the shoot
+	Credentials *CredentialsStatus
+	// Account contains the SVM account of the shoot
+	Account *AccountStatus
+	// Usage contains the usage of the SVM of the project against its limits
+	Usage *UsageStatus
+	// StoragePrefix is the prefix of the volumes of the shoot, it cannot be changed once the backends are deployed
+	StoragePrefix string
+	// ShootUsage contains the usage of the volumes of the shoot, it is nil for shoots with the default storage prefix
+	ShootUsage *ShootUsageStatus
+	// DisasterRecovery contains the state of the SVM-DR relationship of the SVM of the project
+	DisasterRecovery *DisasterRecoveryStatus
+	// MetroCluster contains the site of the MetroCluster the SVM of the project is active on
+	MetroCluster *MetroClusterStatus
+}
+
+// DisasterRecoveryStatus contains the state of the SVM-DR relationship of the SVM of a project
+type DisasterRecoveryStatus struct {
+	// SVM is the name of the DR SVM
+	SVM string
+	// State is the state of the SnapMirror relationship
+	State string
+	// Healthy is whether ONTAP reports the relationship as healthy
+	Healthy bool
+	// UnhealthyReason is the reason ONTAP reports for an unhealthy relationship
+	UnhealthyReason string
+	// LagTime is the time since the last transfer as ISO 8601 duration when the state of the relationship changed last
+	LagTime string
+	// FailedOver is whether the SVM failed over to the DR SVM
+	FailedOver bool
+	// LastFailoverTime is the time of the last failover
+	LastFailoverTime *metav1.Time
+	// LastFailoverRequest is the value of the failover annotation of the shoot which was handled last
+	LastFailoverRequest string
+}
+
+// MetroClusterStatus contains the site of the MetroCluster the SVM of a project is active on
+type MetroClusterStatus struct {
+	// ActiveSite is the name of the cluster the SVM is running on
+	ActiveSite string
+	// SVM is the name of the running SVM
+	SVM string
+	// Mode is the MetroCluster mode of operation of the active site
+	Mode string
+	// SwitchedOver is whether the SVM runs on the partner site after a switchover
+	SwitchedOver bool
+}
+
+// ShootUsageStatus contains the usage of the volumes of a shoot on the SVM of its project
+type ShootUsageStatus struct {
+	// Volumes is the number of volumes of the shoot
+	Volumes int64
+	// Provisioned is the provisioned size of the volumes of the shoot
+	Provisioned resource.Quantity
+	// Used is the used size of the volumes of the shoot
+	Used resource.Quantity
+}
+
+// UsageStatus contains the usage of the SVM of a project against its limits
+type UsageStatus struct {
+	// StorageLimit is the maximum space the volumes of the SVM can allocate, it is unlimited if nil
+	StorageLimit *resource.Quantity
+	// StorageAllocated is the space allocated by the volumes of the SVM
+	StorageAllocated resource.Quantity
+	// MaxVolumes is the maximum number of volumes of the SVM, it is unlimited if nil
+	MaxVolumes *int64
+	// Volumes is the number of volumes of the SVM without its root volume
+	Volumes int64
+}
+
+// AccountStatus contains the SVM account of a shoot
+type AccountStatus struct {
+	// Username is the name of the SVM account of the shoot
+	Username string
+	// LegacyUsername is the truncated username of earlier versions, it is only set if it differs from the username
+	LegacyUsername string
+}
+
+// CredentialsStatus contains the state of the SVM account credentials of a shoot
+type CredentialsStatus struct {
+	// LastRotationTime is the time the password of the account was rotated last
+	LastRotationTime *metav1.Time
+	// LastRotationRequest is the value of the rotation annotation of the shoot which was handled last
+	LastRotationRequest string
 }
 
 // SvmIpaddresses contains the network interface addresses for a Storage Virtual Machine (SVM)
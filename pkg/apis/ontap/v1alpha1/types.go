@@ -1,10 +1,16 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
 	"net/netip"
+	"slices"
 
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -12,6 +18,49 @@ const (
 	ShootCsiDriverLvmResourceName = "extension-ontap"
 )
 
+// Protocol is a storage protocol the SVM serves to the shoot
+type Protocol string
+
+const (
+	// ProtocolNVMe serves block volumes with NVMe over TCP
+	ProtocolNVMe Protocol = "nvme"
+	// ProtocolISCSI serves block volumes with iSCSI
+	ProtocolISCSI Protocol = "iscsi"
+	// ProtocolNFS serves file volumes with NFS
+	ProtocolNFS Protocol = "nfs"
+)
+
+// DefaultProtocols are the protocols of shoots which do not configure any
+var DefaultProtocols = []Protocol{ProtocolNVMe}
+
+// ProvisioningType is the space allocation of the volumes of a StorageClass
+type ProvisioningType string
+
+const (
+	// ProvisioningThin allocates the space of a volume when it is written
+	ProvisioningThin ProvisioningType = "thin"
+	// ProvisioningThick reserves the space of a volume when it is created
+	ProvisioningThick ProvisioningType = "thick"
+)
+
+// NoQoSTier is the tier label of the virtual storage pools without QoS policy, it cannot be used as tier name
+const NoQoSTier = "none"
+
+// DefaultVolumeSnapshotClass is the name of the VolumeSnapshotClass of shoots which do not configure one
+const DefaultVolumeSnapshotClass = "ontap-snapshots"
+
+// DefaultReplicaStorageClass is the name of the StorageClass of mirrored volumes of shoots which do not configure one
+const DefaultReplicaStorageClass = "ontap-replica"
+
+// DefaultVolumeGroupSnapshotClass is the name of the VolumeGroupSnapshotClass of shoots which do not configure one
+const DefaultVolumeGroupSnapshotClass = "ontap-group-snapshots"
+
+// maxSnapshotCopies is the maximum number of snapshots ONTAP retains for a schedule of a snapshot policy
+const maxSnapshotCopies = 1023
+
+// filesystems are the filesystems Trident formats block volumes with
+var filesystems = []string{"ext3", "ext4", "xfs"}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TridentConfig configuration resource which configures the trident csi driver
@@ -20,6 +69,271 @@ type TridentConfig struct {
 
 	//SvmIpAddresses are the endpoints needed by the trident csi driver to connect to the SVM
 	SvmIpaddresses SvmIpaddresses `json:"svmIpaddresses"`
+
+	// Protocols are the storage protocols enabled on the SVM, a TridentBackendConfig and StorageClasses are rendered
+	// for each of them. The data LIFs are assigned to the protocols of all shoots sharing the SVM, a LIF keeps its
+	// protocol as long as one of the shoots uses it, defaults to nvme.
+	// +optional
+	Protocols []Protocol `json:"protocols,omitempty"`
+
+	// StorageClasses are the StorageClasses of the shoot, the backends get a virtual storage pool for each combination of
+	// encryption and provisioning type the StorageClasses of their protocol use. Defaults to the StorageClasses of the
+	// ControllerConfiguration, or ontap-gold and ontap-encrypted for each block protocol and ontap-nfs for nfs.
+	// +optional
+	StorageClasses []StorageClass `json:"storageClasses,omitempty"`
+
+	// SVMLimits override the limits of the SVM of the project from the ControllerConfiguration. The SVM is shared by
+	// all shoots of the project, each limit is the largest limit of the shoots of the seed and unlimited if any of
+	// them is unlimited
+	// +optional
+	SVMLimits *SVMLimits `json:"svmLimits,omitempty"`
+
+	// Snapshots configure the ONTAP snapshot policies and the snapshot reserve of the volumes of the shoot, the
+	// VolumeSnapshotClass of Trident is only deployed if set
+	// +optional
+	Snapshots *SnapshotConfig `json:"snapshots,omitempty"`
+
+	// Replication creates a secondary SVM of the project on another cluster which is peered with the SVM of the
+	// project, volumes can be mirrored to it with TridentMirrorRelationships. Volumes cannot be mirrored if not set.
+	// +optional
+	Replication *ReplicationConfig `json:"replication,omitempty"`
+
+	// DisasterRecovery replicates the SVM of the project with SnapMirror SVM-DR to the DR cluster of the
+	// ControllerConfiguration, the shoot can fail over to the DR SVM with the failover annotation. The SVM is shared by
+	// all shoots of the project which fail over together. The SVM is not replicated if not set.
+	// +optional
+	DisasterRecovery *DisasterRecoveryConfig `json:"disasterRecovery,omitempty"`
+}
+
+// DisasterRecoveryConfig configures the DR SVM the SVM of the project is replicated to
+type DisasterRecoveryConfig struct {
+	// SvmIpaddresses are the endpoints of the DR SVM, the Trident backends are re-pointed to them after a failover.
+	// They must differ from the endpoints of the SVM.
+	SvmIpaddresses SvmIpaddresses `json:"svmIpaddresses"`
+}
+
+// ReplicationConfig configures the secondary SVM volumes of the shoot are mirrored to
+type ReplicationConfig struct {
+	// SvmIpaddresses are the endpoints of the secondary SVM, they must differ from the endpoints of the SVM
+	SvmIpaddresses SvmIpaddresses `json:"svmIpaddresses"`
+	// StorageClass is the name of the StorageClass of the mirrored volumes of the NVMe backend, the StorageClass of
+	// the iSCSI backend gets the suffix -iscsi. Defaults to ontap-replica.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// SnapshotConfig configures the snapshots of the volumes of a shoot
+type SnapshotConfig struct {
+	// Policy is the snapshot policy of the volumes of the shoot, volumes are not snapshotted by ONTAP if not set
+	// +optional
+	Policy *SnapshotPolicy `json:"policy,omitempty"`
+	// TierPolicies override the policy for the volumes of StorageClasses with the given QoS tier
+	// +optional
+	TierPolicies []TierSnapshotPolicy `json:"tierPolicies,omitempty"`
+	// Reserve is the percentage of the size of a volume reserved for snapshots
+	// +optional
+	Reserve *int32 `json:"reserve,omitempty"`
+	// VolumeSnapshotClass is the name of the VolumeSnapshotClass of Trident in the shoot, defaults to ontap-snapshots
+	// +optional
+	VolumeSnapshotClass string `json:"volumeSnapshotClass,omitempty"`
+	// DeletionPolicy of the VolumeSnapshotClass and the VolumeGroupSnapshotClass is Delete or Retain, defaults to Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// GroupSnapshots enables crash-consistent snapshots of multiple volumes in Trident and deploys a
+	// VolumeGroupSnapshotClass, it requires Kubernetes 1.32 or later and the VolumeGroupSnapshot CRDs in the shoot
+	// +optional
+	GroupSnapshots bool `json:"groupSnapshots,omitempty"`
+	// VolumeGroupSnapshotClass is the name of the VolumeGroupSnapshotClass of Trident in the shoot, defaults to
+	// ontap-group-snapshots
+	// +optional
+	VolumeGroupSnapshotClass string `json:"volumeGroupSnapshotClass,omitempty"`
+	// InPlaceRestore allows the admins and editors of namespaces to restore volumes from their snapshots in place with
+	// TridentActionSnapshotRestores
+	// +optional
+	InPlaceRestore bool `json:"inPlaceRestore,omitempty"`
+}
+
+// SnapshotPolicy is an ONTAP snapshot policy which is created on the SVM for the shoot
+type SnapshotPolicy struct {
+	// Schedules are the schedules snapshots are taken with and the number of snapshots retained for each
+	Schedules []SnapshotSchedule `json:"schedules"`
+}
+
+// SnapshotSchedule takes snapshots with a cron schedule of the ONTAP cluster
+type SnapshotSchedule struct {
+	// Schedule is the name of a cron schedule of the ONTAP cluster, e.g. hourly, daily or weekly
+	Schedule string `json:"schedule"`
+	// Count is the number of snapshots retained
+	Count int64 `json:"count"`
+}
+
+// TierSnapshotPolicy is the snapshot policy of the volumes of the StorageClasses with a QoS tier
+type TierSnapshotPolicy struct {
+	// Tier is the name of the QoS tier
+	Tier string `json:"tier"`
+	// Schedules are the schedules snapshots are taken with and the number of snapshots retained for each
+	Schedules []SnapshotSchedule `json:"schedules"`
+}
+
+// SVMLimits limit the resources the SVM of a project can consume, unset limits are unlimited
+type SVMLimits struct {
+	// StorageLimit is the maximum space the volumes of the SVM can allocate
+	// +optional
+	StorageLimit *resource.Quantity `json:"storageLimit,omitempty"`
+	// MaxVolumes is the maximum number of volumes of the SVM
+	// +optional
+	MaxVolumes *int64 `json:"maxVolumes,omitempty"`
+}
+
+// StorageClass declares a StorageClass of the shoot
+type StorageClass struct {
+	// Name is the name of the StorageClass
+	Name string `json:"name"`
+	// Protocol is the protocol of the backend the volumes are provisioned from, defaults to the first protocol of the shoot
+	// +optional
+	Protocol Protocol `json:"protocol,omitempty"`
+	// Default marks the StorageClass as the default StorageClass of the shoot
+	// +optional
+	Default bool `json:"default,omitempty"`
+	// FSType is the filesystem block volumes are formatted with, one of ext3, ext4 or xfs, defaults to ext4.
+	// It must not be set for nfs.
+	// +optional
+	FSType string `json:"fsType,omitempty"`
+	// ProvisioningType is thin or thick, defaults to thin
+	// +optional
+	ProvisioningType ProvisioningType `json:"provisioningType,omitempty"`
+	// LUKS encrypts block volumes with the passphrase of the storage-encryption-key secret in the namespace of the claim,
+	// it must not be set for nfs
+	// +optional
+	LUKS bool `json:"luks,omitempty"`
+	// ReclaimPolicy is Delete or Retain, defaults to Delete
+	// +optional
+	ReclaimPolicy *corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// VolumeBindingMode is Immediate or WaitForFirstConsumer, defaults to Immediate
+	// +optional
+	VolumeBindingMode *storagev1.VolumeBindingMode `json:"volumeBindingMode,omitempty"`
+	// MountOptions are passed to the mount of the volumes
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
+	// QoSTier is the name of a QoS tier of the ControllerConfiguration which limits the performance of each volume
+	// +optional
+	QoSTier string `json:"qosTier,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TridentStatus is the provider status of the ontap Extension
+type TridentStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Credentials contains the state of the SVM account credentials of the shoot
+	// +optional
+	Credentials *CredentialsStatus `json:"credentials,omitempty"`
+	// Account contains the SVM account of the shoot
+	// +optional
+	Account *AccountStatus `json:"account,omitempty"`
+	// Usage contains the usage of the SVM of the project against its limits
+	// +optional
+	Usage *UsageStatus `json:"usage,omitempty"`
+	// StoragePrefix is the prefix of the volumes of the shoot, it cannot be changed once the backends are deployed
+	// +optional
+	StoragePrefix string `json:"storagePrefix,omitempty"`
+	// ShootUsage contains the usage of the volumes of the shoot, it is not set for shoots with the default storage
+	// prefix of Trident whose volumes cannot be told apart from the volumes of other shoots of the project
+	// +optional
+	ShootUsage *ShootUsageStatus `json:"shootUsage,omitempty"`
+	// DisasterRecovery contains the state of the SVM-DR relationship of the SVM of the project, it is only set for
+	// shoots which configure disaster recovery
+	// +optional
+	DisasterRecovery *DisasterRecoveryStatus `json:"disasterRecovery,omitempty"`
+	// MetroCluster contains the site of the MetroCluster the SVM of the project is active on, it is only set if the
+	// cluster of the SVM is part of a MetroCluster
+	// +optional
+	MetroCluster *MetroClusterStatus `json:"metroCluster,omitempty"`
+}
+
+// DisasterRecoveryStatus contains the state of the SVM-DR relationship of the SVM of a project
+type DisasterRecoveryStatus struct {
+	// SVM is the name of the DR SVM
+	SVM string `json:"svm"`
+	// State is the state of the SnapMirror relationship, e.g. snapmirrored or broken_off
+	// +optional
+	State string `json:"state,omitempty"`
+	// Healthy is whether ONTAP reports the relationship as healthy
+	Healthy bool `json:"healthy"`
+	// UnhealthyReason is the reason ONTAP reports for an unhealthy relationship
+	// +optional
+	UnhealthyReason string `json:"unhealthyReason,omitempty"`
+	// LagTime is the time since the last transfer as ISO 8601 duration when the state of the relationship changed last
+	// +optional
+	LagTime string `json:"lagTime,omitempty"`
+	// FailedOver is whether the SVM failed over to the DR SVM, the Trident backends use the DR SVM then
+	// +optional
+	FailedOver bool `json:"failedOver,omitempty"`
+	// LastFailoverTime is the time of the last failover executed by the extension
+	// +optional
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+	// LastFailoverRequest is the value of the failover annotation of the shoot which was handled last
+	// +optional
+	LastFailoverRequest string `json:"lastFailoverRequest,omitempty"`
+}
+
+// MetroClusterStatus contains the site of the MetroCluster the SVM of a project is active on
+type MetroClusterStatus struct {
+	// ActiveSite is the name of the cluster the SVM is running on
+	ActiveSite string `json:"activeSite"`
+	// SVM is the name of the running SVM, it has the suffix -mc on the partner site after a switchover
+	SVM string `json:"svm"`
+	// Mode is the MetroCluster mode of operation of the active site, e.g. normal or switchover
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// SwitchedOver is whether the SVM runs on the partner site after a switchover, the Trident backends use the SVM
+	// of the partner site then
+	// +optional
+	SwitchedOver bool `json:"switchedOver,omitempty"`
+}
+
+// ShootUsageStatus contains the usage of the volumes of a shoot on the SVM of its project
+type ShootUsageStatus struct {
+	// Volumes is the number of volumes of the shoot
+	Volumes int64 `json:"volumes"`
+	// Provisioned is the provisioned size of the volumes of the shoot
+	Provisioned resource.Quantity `json:"provisioned"`
+	// Used is the used size of the volumes of the shoot
+	Used resource.Quantity `json:"used"`
+}
+
+// UsageStatus contains the usage of the SVM of a project against its limits
+type UsageStatus struct {
+	// StorageLimit is the maximum space the volumes of the SVM can allocate, it is unlimited if not set
+	// +optional
+	StorageLimit *resource.Quantity `json:"storageLimit,omitempty"`
+	// StorageAllocated is the space allocated by the volumes of the SVM
+	StorageAllocated resource.Quantity `json:"storageAllocated"`
+	// MaxVolumes is the maximum number of volumes of the SVM, it is unlimited if not set
+	// +optional
+	MaxVolumes *int64 `json:"maxVolumes,omitempty"`
+	// Volumes is the number of volumes of the SVM without its root volume
+	Volumes int64 `json:"volumes"`
+}
+
+// AccountStatus contains the SVM account of a shoot
+type AccountStatus struct {
+	// Username is the name of the SVM account of the shoot
+	Username string `json:"username"`
+	// LegacyUsername is the truncated username of earlier versions, it is only set if it differs from the username
+	// +optional
+	LegacyUsername string `json:"legacyUsername,omitempty"`
+}
+
+// CredentialsStatus contains the state of the SVM account credentials of a shoot
+type CredentialsStatus struct {
+	// LastRotationTime is the time the password of the account was rotated last
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// LastRotationRequest is the value of the rotation annotation of the shoot which was handled last
+	// +optional
+	LastRotationRequest string `json:"lastRotationRequest,omitempty"`
 }
 
 // SvmIpaddresses contains the network interface addresses for a Storage Virtual Machine (SVM)
@@ -51,15 +365,298 @@ func (c *TridentConfig) Validate() error {
 			return fmt.Errorf("given data LIF %s is not a valid ip address:%w", ip, err)
 		}
 	}
+
+	seen := map[Protocol]bool{}
+	for _, p := range c.Protocols {
+		switch p {
+		case ProtocolNVMe, ProtocolISCSI, ProtocolNFS:
+		default:
+			return fmt.Errorf("unsupported protocol %q, must be one of %s, %s or %s", p, ProtocolNVMe, ProtocolISCSI, ProtocolNFS)
+		}
+		if seen[p] {
+			return fmt.Errorf("protocol %q is given more than once", p)
+		}
+		seen[p] = true
+	}
+	if len(c.SvmIpaddresses.DataLifs) < len(c.Protocols) {
+		return fmt.Errorf("at least one data LIF per protocol must be provided, got %d data LIFs for %d protocols", len(c.SvmIpaddresses.DataLifs), len(c.Protocols))
+	}
+
+	protocols := c.Protocols
+	if len(protocols) == 0 {
+		protocols = DefaultProtocols
+	}
+	for _, sc := range c.StorageClasses {
+		if sc.Protocol != "" && !slices.Contains(protocols, sc.Protocol) {
+			return fmt.Errorf("storage class %q uses protocol %q which is not enabled", sc.Name, sc.Protocol)
+		}
+	}
+	if err := c.SVMLimits.Validate(); err != nil {
+		return err
+	}
+	if err := c.Snapshots.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateReplication(protocols); err != nil {
+		return err
+	}
+	if err := c.validateDisasterRecovery(protocols); err != nil {
+		return err
+	}
+	return ValidateStorageClasses(c.StorageClasses, protocols[0])
+}
+
+// Validate returns an error if a limit is not positive, nil limits are valid.
+func (l *SVMLimits) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.StorageLimit != nil && l.StorageLimit.Sign() <= 0 {
+		return fmt.Errorf("svm storage limit must be positive")
+	}
+	if l.MaxVolumes != nil && *l.MaxVolumes <= 0 {
+		return fmt.Errorf("svm max volumes must be positive")
+	}
 	return nil
 }
 
-func (config *TridentConfig) ConfigureDefaults(svmName *string, svmSecretRef *string) error {
+// Validate returns an error if a schedule, the reserve or the VolumeSnapshotClass is invalid, nil configs are valid.
+func (c *SnapshotConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	var errs []error
+	if c.Policy != nil {
+		if err := validateSnapshotSchedules("snapshot policy", c.Policy.Schedules); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	tiers := map[string]bool{}
+	for i, p := range c.TierPolicies {
+		if msgs := validation.IsDNS1123Label(p.Tier); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("snapshot policy at index %d has an invalid tier %q: %v", i, p.Tier, msgs))
+		}
+		if p.Tier == NoQoSTier {
+			errs = append(errs, fmt.Errorf("snapshot policy must not reference the reserved tier %q", NoQoSTier))
+		}
+		if tiers[p.Tier] {
+			errs = append(errs, fmt.Errorf("snapshot policy of tier %q is given more than once", p.Tier))
+		}
+		tiers[p.Tier] = true
+		if err := validateSnapshotSchedules(fmt.Sprintf("snapshot policy of tier %q", p.Tier), p.Schedules); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.Reserve != nil && (*c.Reserve < 0 || *c.Reserve > 90) {
+		errs = append(errs, fmt.Errorf("snapshot reserve must be between 0 and 90 percent, got %d", *c.Reserve))
+	}
+	if c.VolumeSnapshotClass != "" {
+		if msgs := validation.IsDNS1123Subdomain(c.VolumeSnapshotClass); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid volume snapshot class name %q: %v", c.VolumeSnapshotClass, msgs))
+		}
+	}
+	if c.VolumeGroupSnapshotClass != "" {
+		if !c.GroupSnapshots {
+			errs = append(errs, fmt.Errorf("volume group snapshot class %q requires group snapshots", c.VolumeGroupSnapshotClass))
+		}
+		if msgs := validation.IsDNS1123Subdomain(c.VolumeGroupSnapshotClass); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid volume group snapshot class name %q: %v", c.VolumeGroupSnapshotClass, msgs))
+		}
+	}
+	switch c.DeletionPolicy {
+	case "", "Delete", "Retain":
+	default:
+		errs = append(errs, fmt.Errorf("unsupported snapshot deletion policy %q, must be one of Delete, Retain", c.DeletionPolicy))
+	}
+	return errors.Join(errs...)
+}
 
-	// if config.Protocols == nil || len(config.Protocols) == 0 {
-	// 	if defaultProtocols != nil {
-	// 		config.Protocols = *defaultProtocols
-	// 	}
-	// }
+// validateReplication returns an error if the endpoints of the secondary SVM are invalid or used by the SVM, or if no
+// block protocol is enabled. NFS volumes are not mirrored, the StorageClasses of NFS select all backends of the shoot.
+func (c *TridentConfig) validateReplication(protocols []Protocol) error {
+	r := c.Replication
+	if r == nil {
+		return nil
+	}
+	if !slices.Contains(protocols, ProtocolNVMe) && !slices.Contains(protocols, ProtocolISCSI) {
+		return fmt.Errorf("replication requires protocol %s or %s", ProtocolNVMe, ProtocolISCSI)
+	}
+	if _, err := netip.ParseAddr(r.SvmIpaddresses.ManagementLif); err != nil {
+		return fmt.Errorf("given replication management LIF IP %q is not a valid ip address:%w", r.SvmIpaddresses.ManagementLif, err)
+	}
+	var blockProtocols int
+	for _, p := range protocols {
+		if p != ProtocolNFS {
+			blockProtocols++
+		}
+	}
+	if len(r.SvmIpaddresses.DataLifs) < blockProtocols {
+		return fmt.Errorf("at least one replication data LIF per block protocol must be provided, got %d data LIFs for %d protocols", len(r.SvmIpaddresses.DataLifs), blockProtocols)
+	}
+	for _, ip := range r.SvmIpaddresses.DataLifs {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			return fmt.Errorf("given replication data LIF %q is not a valid ip address:%w", ip, err)
+		}
+	}
+	used := append([]string{c.SvmIpaddresses.ManagementLif}, c.SvmIpaddresses.DataLifs...)
+	for _, ip := range append([]string{r.SvmIpaddresses.ManagementLif}, r.SvmIpaddresses.DataLifs...) {
+		if slices.Contains(used, ip) {
+			return fmt.Errorf("replication LIF IP %s is already used", ip)
+		}
+		used = append(used, ip)
+	}
+	if r.StorageClass != "" {
+		if msgs := validation.IsDNS1123Subdomain(r.StorageClass + "-iscsi"); len(msgs) > 0 {
+			return fmt.Errorf("invalid replication storage class name %q: %v", r.StorageClass, msgs)
+		}
+	}
+	for _, sc := range c.StorageClasses {
+		if sc.Name == r.StorageClass || (r.StorageClass == "" && sc.Name == DefaultReplicaStorageClass) {
+			return fmt.Errorf("storage class %q is already used for replication", sc.Name)
+		}
+	}
+	return nil
+}
+
+// validateDisasterRecovery returns an error if the endpoints of the DR SVM are invalid or used by another SVM of the
+// shoot. The DR SVM serves the protocols of the SVM after a failover and needs a data LIF per protocol.
+func (c *TridentConfig) validateDisasterRecovery(protocols []Protocol) error {
+	dr := c.DisasterRecovery
+	if dr == nil {
+		return nil
+	}
+	if c.Replication != nil {
+		return fmt.Errorf("disaster recovery cannot be combined with replication")
+	}
+	if _, err := netip.ParseAddr(dr.SvmIpaddresses.ManagementLif); err != nil {
+		return fmt.Errorf("given disaster recovery management LIF IP %q is not a valid ip address:%w", dr.SvmIpaddresses.ManagementLif, err)
+	}
+	if len(dr.SvmIpaddresses.DataLifs) < len(protocols) {
+		return fmt.Errorf("at least one disaster recovery data LIF per protocol must be provided, got %d data LIFs for %d protocols", len(dr.SvmIpaddresses.DataLifs), len(protocols))
+	}
+	for _, ip := range dr.SvmIpaddresses.DataLifs {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			return fmt.Errorf("given disaster recovery data LIF %q is not a valid ip address:%w", ip, err)
+		}
+	}
+	used := append([]string{c.SvmIpaddresses.ManagementLif}, c.SvmIpaddresses.DataLifs...)
+	for _, ip := range append([]string{dr.SvmIpaddresses.ManagementLif}, dr.SvmIpaddresses.DataLifs...) {
+		if slices.Contains(used, ip) {
+			return fmt.Errorf("disaster recovery LIF IP %s is already used", ip)
+		}
+		used = append(used, ip)
+	}
 	return nil
 }
+
+// validateSnapshotSchedules returns an error if the schedules of a snapshot policy are empty, given more than once or
+// retain too few or too many snapshots.
+func validateSnapshotSchedules(policy string, schedules []SnapshotSchedule) error {
+	if len(schedules) == 0 {
+		return fmt.Errorf("%s must have at least one schedule", policy)
+	}
+	var (
+		errs  []error
+		names = map[string]bool{}
+		total int64
+	)
+	for _, s := range schedules {
+		if s.Schedule == "" {
+			errs = append(errs, fmt.Errorf("%s has a schedule without name", policy))
+		}
+		if names[s.Schedule] {
+			errs = append(errs, fmt.Errorf("%s has schedule %q more than once", policy, s.Schedule))
+		}
+		names[s.Schedule] = true
+		if s.Count <= 0 {
+			errs = append(errs, fmt.Errorf("%s must retain at least one snapshot of schedule %q", policy, s.Schedule))
+		}
+		total += s.Count
+	}
+	if total > maxSnapshotCopies {
+		errs = append(errs, fmt.Errorf("%s retains %d snapshots, at most %d are supported", policy, total, maxSnapshotCopies))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateStorageClasses validates a catalog of StorageClasses, classes without protocol are validated as classes of the
+// given default protocol. The default protocol is empty for catalogs which are shared by shoots with different protocols.
+func ValidateStorageClasses(classes []StorageClass, defaultProtocol Protocol) error {
+	var (
+		errs     []error
+		names    = map[string]bool{}
+		defaults []string
+	)
+	for i, sc := range classes {
+		if msgs := validation.IsDNS1123Subdomain(sc.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("storage class at index %d has an invalid name %q: %v", i, sc.Name, msgs))
+		}
+		if names[sc.Name] {
+			errs = append(errs, fmt.Errorf("storage class %q is given more than once", sc.Name))
+		}
+		names[sc.Name] = true
+		if sc.Default {
+			defaults = append(defaults, sc.Name)
+		}
+		if sc.QoSTier == NoQoSTier {
+			errs = append(errs, fmt.Errorf("storage class %q must not reference the reserved tier %q", sc.Name, NoQoSTier))
+		}
+
+		protocol := sc.Protocol
+		if protocol == "" {
+			protocol = defaultProtocol
+		}
+		switch protocol {
+		case ProtocolNVMe, ProtocolISCSI, "":
+			if sc.FSType != "" && !slices.Contains(filesystems, sc.FSType) {
+				errs = append(errs, fmt.Errorf("storage class %q has unsupported filesystem %q, must be one of %v", sc.Name, sc.FSType, filesystems))
+			}
+		case ProtocolNFS:
+			if sc.FSType != "" {
+				errs = append(errs, fmt.Errorf("storage class %q must not set a filesystem for protocol %s", sc.Name, protocol))
+			}
+			if sc.LUKS {
+				errs = append(errs, fmt.Errorf("storage class %q must not enable LUKS for protocol %s", sc.Name, protocol))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("storage class %q has unsupported protocol %q", sc.Name, protocol))
+		}
+
+		switch sc.ProvisioningType {
+		case "", ProvisioningThin, ProvisioningThick:
+		default:
+			errs = append(errs, fmt.Errorf("storage class %q has unsupported provisioning type %q, must be one of %s, %s", sc.Name, sc.ProvisioningType, ProvisioningThin, ProvisioningThick))
+		}
+		if p := sc.ReclaimPolicy; p != nil && *p != corev1.PersistentVolumeReclaimDelete && *p != corev1.PersistentVolumeReclaimRetain {
+			errs = append(errs, fmt.Errorf("storage class %q has unsupported reclaim policy %q, must be one of %s, %s", sc.Name, *p, corev1.PersistentVolumeReclaimDelete, corev1.PersistentVolumeReclaimRetain))
+		}
+		if m := sc.VolumeBindingMode; m != nil && *m != storagev1.VolumeBindingImmediate && *m != storagev1.VolumeBindingWaitForFirstConsumer {
+			errs = append(errs, fmt.Errorf("storage class %q has unsupported volume binding mode %q, must be one of %s, %s", sc.Name, *m, storagev1.VolumeBindingImmediate, storagev1.VolumeBindingWaitForFirstConsumer))
+		}
+	}
+	if len(defaults) > 1 {
+		errs = append(errs, fmt.Errorf("only one storage class can be the default, got %v", defaults))
+	}
+	return errors.Join(errs...)
+}
+
+// ConfigureDefaults sets the defaults of fields which are not configured.
+func (c *TridentConfig) ConfigureDefaults() {
+	if len(c.Protocols) == 0 {
+		c.Protocols = slices.Clone(DefaultProtocols)
+	}
+	if c.Snapshots != nil {
+		if c.Snapshots.VolumeSnapshotClass == "" {
+			c.Snapshots.VolumeSnapshotClass = DefaultVolumeSnapshotClass
+		}
+		if c.Snapshots.DeletionPolicy == "" {
+			c.Snapshots.DeletionPolicy = "Delete"
+		}
+		if c.Snapshots.GroupSnapshots && c.Snapshots.VolumeGroupSnapshotClass == "" {
+			c.Snapshots.VolumeGroupSnapshotClass = DefaultVolumeGroupSnapshotClass
+		}
+	}
+	if c.Replication != nil && c.Replication.StorageClass == "" {
+		c.Replication.StorageClass = DefaultReplicaStorageClass
+	}
+}
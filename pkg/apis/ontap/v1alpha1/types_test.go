@@ -2,8 +2,138 @@ package v1alpha1
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestConfig(t *testing.T) {
+	valid := func(protocols ...Protocol) *TridentConfig {
+		return &TridentConfig{
+			SvmIpaddresses: SvmIpaddresses{ManagementLif: "10.0.0.1", DataLifs: []string{"10.0.0.2", "10.0.0.3"}},
+			Protocols:      protocols,
+		}
+	}
+
+	withClasses := func(protocols []Protocol, classes ...StorageClass) *TridentConfig {
+		c := valid(protocols...)
+		c.StorageClasses = classes
+		return c
+	}
+	withLimits := func(limits *SVMLimits) *TridentConfig {
+		c := valid()
+		c.SVMLimits = limits
+		return c
+	}
+	withSnapshots := func(snapshots *SnapshotConfig) *TridentConfig {
+		c := valid()
+		c.Snapshots = snapshots
+		return c
+	}
+	withReplication := func(protocols []Protocol, replication *ReplicationConfig) *TridentConfig {
+		c := valid(protocols...)
+		c.Replication = replication
+		return c
+	}
+	withDisasterRecovery := func(protocols []Protocol, dr SvmIpaddresses) *TridentConfig {
+		c := valid(protocols...)
+		c.DisasterRecovery = &DisasterRecoveryConfig{SvmIpaddresses: dr}
+		return c
+	}
+	replica := SvmIpaddresses{ManagementLif: "10.0.1.1", DataLifs: []string{"10.0.1.2"}}
+	daily := []SnapshotSchedule{{Schedule: "daily", Count: 7}}
+	nfs := []Protocol{ProtocolNFS}
+
+	tests := []struct {
+		name    string
+		config  *TridentConfig
+		wantErr string
+	}{
+		{name: "default protocols", config: valid()},
+		{name: "nvme and iscsi", config: valid(ProtocolNVMe, ProtocolISCSI)},
+		{name: "unsupported protocol", config: valid(ProtocolNVMe, "fc"), wantErr: `unsupported protocol "fc"`},
+		{name: "duplicate protocol", config: valid(ProtocolNFS, ProtocolNFS), wantErr: `protocol "nfs" is given more than once`},
+		{name: "fewer data LIFs than protocols", config: valid(ProtocolNVMe, ProtocolISCSI, ProtocolNFS), wantErr: "at least one data LIF per protocol"},
+		{name: "catalog", config: withClasses(nil, StorageClass{Name: "fast", Default: true, FSType: "xfs", ProvisioningType: ProvisioningThick, LUKS: true}, StorageClass{Name: "slow"})},
+		{name: "invalid name", config: withClasses(nil, StorageClass{Name: "Fast"}), wantErr: `invalid name "Fast"`},
+		{name: "duplicate name", config: withClasses(nil, StorageClass{Name: "fast"}, StorageClass{Name: "fast"}), wantErr: `storage class "fast" is given more than once`},
+		{name: "two defaults", config: withClasses(nil, StorageClass{Name: "a", Default: true}, StorageClass{Name: "b", Default: true}), wantErr: "only one storage class can be the default"},
+		{name: "protocol not enabled", config: withClasses(nil, StorageClass{Name: "files", Protocol: ProtocolNFS}), wantErr: `protocol "nfs" which is not enabled`},
+		{name: "unsupported filesystem", config: withClasses(nil, StorageClass{Name: "fast", FSType: "btrfs"}), wantErr: `unsupported filesystem "btrfs"`},
+		{name: "luks with nfs", config: withClasses(nfs, StorageClass{Name: "files", LUKS: true}), wantErr: "must not enable LUKS"},
+		{name: "filesystem with nfs", config: withClasses(nfs, StorageClass{Name: "files", FSType: "ext4"}), wantErr: "must not set a filesystem"},
+		{name: "unsupported provisioning type", config: withClasses(nil, StorageClass{Name: "fast", ProvisioningType: "eager"}), wantErr: `unsupported provisioning type "eager"`},
+		{name: "unsupported reclaim policy", config: withClasses(nil, StorageClass{Name: "fast", ReclaimPolicy: new(corev1.PersistentVolumeReclaimRecycle)}), wantErr: `unsupported reclaim policy "Recycle"`},
+		{name: "svm limits", config: withLimits(&SVMLimits{StorageLimit: new(resource.MustParse("500Gi")), MaxVolumes: new(int64(50))})},
+		{name: "zero storage limit", config: withLimits(&SVMLimits{StorageLimit: new(resource.MustParse("0"))}), wantErr: "svm storage limit must be positive"},
+		{name: "snapshots", config: withSnapshots(&SnapshotConfig{Policy: &SnapshotPolicy{Schedules: daily}, TierPolicies: []TierSnapshotPolicy{{Tier: "gold", Schedules: daily}}, Reserve: new(int32(10)), DeletionPolicy: "Retain"})},
+		{name: "snapshot policy without schedules", config: withSnapshots(&SnapshotConfig{Policy: &SnapshotPolicy{}}), wantErr: "snapshot policy must have at least one schedule"},
+		{name: "snapshot schedule without copies", config: withSnapshots(&SnapshotConfig{Policy: &SnapshotPolicy{Schedules: []SnapshotSchedule{{Schedule: "daily"}}}}), wantErr: `must retain at least one snapshot of schedule "daily"`},
+		{name: "too many snapshot copies", config: withSnapshots(&SnapshotConfig{Policy: &SnapshotPolicy{Schedules: []SnapshotSchedule{{Schedule: "hourly", Count: 1000}, {Schedule: "daily", Count: 100}}}}), wantErr: "retains 1100 snapshots, at most 1023"},
+		{name: "duplicate snapshot tier", config: withSnapshots(&SnapshotConfig{TierPolicies: []TierSnapshotPolicy{{Tier: "gold", Schedules: daily}, {Tier: "gold", Schedules: daily}}}), wantErr: `snapshot policy of tier "gold" is given more than once`},
+		{name: "snapshot reserve out of range", config: withSnapshots(&SnapshotConfig{Reserve: new(int32(95))}), wantErr: "snapshot reserve must be between 0 and 90 percent"},
+		{name: "group snapshot class without group snapshots", config: withSnapshots(&SnapshotConfig{VolumeGroupSnapshotClass: "groups"}), wantErr: `volume group snapshot class "groups" requires group snapshots`},
+		{name: "unsupported snapshot deletion policy", config: withSnapshots(&SnapshotConfig{DeletionPolicy: "Keep"}), wantErr: `unsupported snapshot deletion policy "Keep"`},
+		{name: "replication", config: withReplication(nil, &ReplicationConfig{SvmIpaddresses: replica, StorageClass: "mirrors"})},
+		{name: "replication without block protocol", config: withReplication(nfs, &ReplicationConfig{SvmIpaddresses: replica}), wantErr: "replication requires protocol nvme or iscsi"},
+		{name: "replication without management LIF", config: withReplication(nil, &ReplicationConfig{SvmIpaddresses: SvmIpaddresses{DataLifs: replica.DataLifs}}), wantErr: "replication management LIF"},
+		{name: "fewer replication data LIFs than block protocols", config: withReplication([]Protocol{ProtocolNVMe, ProtocolISCSI}, &ReplicationConfig{SvmIpaddresses: replica}), wantErr: "at least one replication data LIF per block protocol"},
+		{name: "replication LIF already used", config: withReplication(nil, &ReplicationConfig{SvmIpaddresses: SvmIpaddresses{ManagementLif: "10.0.1.1", DataLifs: []string{"10.0.0.2"}}}), wantErr: "replication LIF IP 10.0.0.2 is already used"},
+		{name: "replication storage class already used", config: func() *TridentConfig {
+			c := withReplication(nil, &ReplicationConfig{SvmIpaddresses: replica})
+			c.StorageClasses = []StorageClass{{Name: DefaultReplicaStorageClass}}
+			return c
+		}(), wantErr: `storage class "ontap-replica" is already used for replication`},
+		{name: "disaster recovery", config: withDisasterRecovery(nfs, replica)},
+		{name: "disaster recovery with replication", config: func() *TridentConfig {
+			c := withDisasterRecovery(nil, replica)
+			c.Replication = &ReplicationConfig{SvmIpaddresses: SvmIpaddresses{ManagementLif: "10.0.2.1", DataLifs: []string{"10.0.2.2"}}}
+			return c
+		}(), wantErr: "disaster recovery cannot be combined with replication"},
+		{name: "disaster recovery without management LIF", config: withDisasterRecovery(nil, SvmIpaddresses{DataLifs: replica.DataLifs}), wantErr: "disaster recovery management LIF"},
+		{name: "fewer disaster recovery data LIFs than protocols", config: withDisasterRecovery([]Protocol{ProtocolNVMe, ProtocolNFS}, replica), wantErr: "at least one disaster recovery data LIF per protocol"},
+		{name: "disaster recovery LIF already used", config: withDisasterRecovery(nil, SvmIpaddresses{ManagementLif: "10.0.0.1", DataLifs: []string{"10.0.1.2"}}), wantErr: "disaster recovery LIF IP 10.0.0.1 is already used"},
+		{name: "negative max volumes", config: withLimits(&SVMLimits{MaxVolumes: new(int64(-1))}), wantErr: "svm max volumes must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("defaults to nvme", func(t *testing.T) {
+		c := valid()
+		c.ConfigureDefaults()
+		assert.Equal(t, []Protocol{ProtocolNVMe}, c.Protocols)
+
+		c = valid(ProtocolNFS)
+		c.ConfigureDefaults()
+		assert.Equal(t, []Protocol{ProtocolNFS}, c.Protocols)
+	})
+
+	t.Run("defaults the volume snapshot class", func(t *testing.T) {
+		c := withSnapshots(&SnapshotConfig{})
+		c.ConfigureDefaults()
+		assert.Equal(t, DefaultVolumeSnapshotClass, c.Snapshots.VolumeSnapshotClass)
+		assert.Equal(t, "Delete", c.Snapshots.DeletionPolicy)
+		assert.Empty(t, c.Snapshots.VolumeGroupSnapshotClass)
+
+		c = withSnapshots(&SnapshotConfig{GroupSnapshots: true})
+		c.ConfigureDefaults()
+		assert.Equal(t, DefaultVolumeGroupSnapshotClass, c.Snapshots.VolumeGroupSnapshotClass)
+	})
 
+	t.Run("defaults the replica storage class", func(t *testing.T) {
+		c := withReplication(nil, &ReplicationConfig{SvmIpaddresses: replica})
+		c.ConfigureDefaults()
+		assert.Equal(t, DefaultReplicaStorageClass, c.Replication.StorageClass)
+	})
 }
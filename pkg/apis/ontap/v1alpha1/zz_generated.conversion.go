@@ -13,6 +13,10 @@ import (
 	unsafe "unsafe"
 
 	ontap "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -24,6 +28,126 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AccountStatus)(nil), (*ontap.AccountStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AccountStatus_To_ontap_AccountStatus(a.(*AccountStatus), b.(*ontap.AccountStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.AccountStatus)(nil), (*AccountStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_AccountStatus_To_v1alpha1_AccountStatus(a.(*ontap.AccountStatus), b.(*AccountStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CredentialsStatus)(nil), (*ontap.CredentialsStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CredentialsStatus_To_ontap_CredentialsStatus(a.(*CredentialsStatus), b.(*ontap.CredentialsStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.CredentialsStatus)(nil), (*CredentialsStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_CredentialsStatus_To_v1alpha1_CredentialsStatus(a.(*ontap.CredentialsStatus), b.(*CredentialsStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DisasterRecoveryConfig)(nil), (*ontap.DisasterRecoveryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DisasterRecoveryConfig_To_ontap_DisasterRecoveryConfig(a.(*DisasterRecoveryConfig), b.(*ontap.DisasterRecoveryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.DisasterRecoveryConfig)(nil), (*DisasterRecoveryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_DisasterRecoveryConfig_To_v1alpha1_DisasterRecoveryConfig(a.(*ontap.DisasterRecoveryConfig), b.(*DisasterRecoveryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DisasterRecoveryStatus)(nil), (*ontap.DisasterRecoveryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DisasterRecoveryStatus_To_ontap_DisasterRecoveryStatus(a.(*DisasterRecoveryStatus), b.(*ontap.DisasterRecoveryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.DisasterRecoveryStatus)(nil), (*DisasterRecoveryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_DisasterRecoveryStatus_To_v1alpha1_DisasterRecoveryStatus(a.(*ontap.DisasterRecoveryStatus), b.(*DisasterRecoveryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetroClusterStatus)(nil), (*ontap.MetroClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MetroClusterStatus_To_ontap_MetroClusterStatus(a.(*MetroClusterStatus), b.(*ontap.MetroClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.MetroClusterStatus)(nil), (*MetroClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_MetroClusterStatus_To_v1alpha1_MetroClusterStatus(a.(*ontap.MetroClusterStatus), b.(*MetroClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ReplicationConfig)(nil), (*ontap.ReplicationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ReplicationConfig_To_ontap_ReplicationConfig(a.(*ReplicationConfig), b.(*ontap.ReplicationConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.ReplicationConfig)(nil), (*ReplicationConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_ReplicationConfig_To_v1alpha1_ReplicationConfig(a.(*ontap.ReplicationConfig), b.(*ReplicationConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SVMLimits)(nil), (*ontap.SVMLimits)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SVMLimits_To_ontap_SVMLimits(a.(*SVMLimits), b.(*ontap.SVMLimits), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.SVMLimits)(nil), (*SVMLimits)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_SVMLimits_To_v1alpha1_SVMLimits(a.(*ontap.SVMLimits), b.(*SVMLimits), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ShootUsageStatus)(nil), (*ontap.ShootUsageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ShootUsageStatus_To_ontap_ShootUsageStatus(a.(*ShootUsageStatus), b.(*ontap.ShootUsageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.ShootUsageStatus)(nil), (*ShootUsageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_ShootUsageStatus_To_v1alpha1_ShootUsageStatus(a.(*ontap.ShootUsageStatus), b.(*ShootUsageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SnapshotConfig)(nil), (*ontap.SnapshotConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SnapshotConfig_To_ontap_SnapshotConfig(a.(*SnapshotConfig), b.(*ontap.SnapshotConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.SnapshotConfig)(nil), (*SnapshotConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_SnapshotConfig_To_v1alpha1_SnapshotConfig(a.(*ontap.SnapshotConfig), b.(*SnapshotConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SnapshotPolicy)(nil), (*ontap.SnapshotPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SnapshotPolicy_To_ontap_SnapshotPolicy(a.(*SnapshotPolicy), b.(*ontap.SnapshotPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.SnapshotPolicy)(nil), (*SnapshotPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_SnapshotPolicy_To_v1alpha1_SnapshotPolicy(a.(*ontap.SnapshotPolicy), b.(*SnapshotPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SnapshotSchedule)(nil), (*ontap.SnapshotSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SnapshotSchedule_To_ontap_SnapshotSchedule(a.(*SnapshotSchedule), b.(*ontap.SnapshotSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.SnapshotSchedule)(nil), (*SnapshotSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_SnapshotSchedule_To_v1alpha1_SnapshotSchedule(a.(*ontap.SnapshotSchedule), b.(*SnapshotSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageClass)(nil), (*ontap.StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StorageClass_To_ontap_StorageClass(a.(*StorageClass), b.(*ontap.StorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.StorageClass)(nil), (*StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_StorageClass_To_v1alpha1_StorageClass(a.(*ontap.StorageClass), b.(*StorageClass), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SvmIpaddresses)(nil), (*ontap.SvmIpaddresses)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SvmIpaddresses_To_ontap_SvmIpaddresses(a.(*SvmIpaddresses), b.(*ontap.SvmIpaddresses), scope)
 	}); err != nil {
@@ -34,6 +158,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TierSnapshotPolicy)(nil), (*ontap.TierSnapshotPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TierSnapshotPolicy_To_ontap_TierSnapshotPolicy(a.(*TierSnapshotPolicy), b.(*ontap.TierSnapshotPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.TierSnapshotPolicy)(nil), (*TierSnapshotPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_TierSnapshotPolicy_To_v1alpha1_TierSnapshotPolicy(a.(*ontap.TierSnapshotPolicy), b.(*TierSnapshotPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TridentConfig)(nil), (*ontap.TridentConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TridentConfig_To_ontap_TridentConfig(a.(*TridentConfig), b.(*ontap.TridentConfig), scope)
 	}); err != nil {
@@ -44,9 +178,343 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TridentStatus)(nil), (*ontap.TridentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TridentStatus_To_ontap_TridentStatus(a.(*TridentStatus), b.(*ontap.TridentStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.TridentStatus)(nil), (*TridentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_TridentStatus_To_v1alpha1_TridentStatus(a.(*ontap.TridentStatus), b.(*TridentStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UsageStatus)(nil), (*ontap.UsageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UsageStatus_To_ontap_UsageStatus(a.(*UsageStatus), b.(*ontap.UsageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ontap.UsageStatus)(nil), (*UsageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_ontap_UsageStatus_To_v1alpha1_UsageStatus(a.(*ontap.UsageStatus), b.(*UsageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_AccountStatus_To_ontap_AccountStatus(in *AccountStatus, out *ontap.AccountStatus, s conversion.Scope) error {
+	out.Username = in.Username
+	out.LegacyUsername = in.LegacyUsername
+	return nil
+}
+
+// Convert_v1alpha1_AccountStatus_To_ontap_AccountStatus is an autogenerated conversion function.
+func Convert_v1alpha1_AccountStatus_To_ontap_AccountStatus(in *AccountStatus, out *ontap.AccountStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AccountStatus_To_ontap_AccountStatus(in, out, s)
+}
+
+func autoConvert_ontap_AccountStatus_To_v1alpha1_AccountStatus(in *ontap.AccountStatus, out *AccountStatus, s conversion.Scope) error {
+	out.Username = in.Username
+	out.LegacyUsername = in.LegacyUsername
+	return nil
+}
+
+// Convert_ontap_AccountStatus_To_v1alpha1_AccountStatus is an autogenerated conversion function.
+func Convert_ontap_AccountStatus_To_v1alpha1_AccountStatus(in *ontap.AccountStatus, out *AccountStatus, s conversion.Scope) error {
+	return autoConvert_ontap_AccountStatus_To_v1alpha1_AccountStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_CredentialsStatus_To_ontap_CredentialsStatus(in *CredentialsStatus, out *ontap.CredentialsStatus, s conversion.Scope) error {
+	out.LastRotationTime = (*v1.Time)(unsafe.Pointer(in.LastRotationTime))
+	out.LastRotationRequest = in.LastRotationRequest
+	return nil
+}
+
+// Convert_v1alpha1_CredentialsStatus_To_ontap_CredentialsStatus is an autogenerated conversion function.
+func Convert_v1alpha1_CredentialsStatus_To_ontap_CredentialsStatus(in *CredentialsStatus, out *ontap.CredentialsStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CredentialsStatus_To_ontap_CredentialsStatus(in, out, s)
+}
+
+func autoConvert_ontap_CredentialsStatus_To_v1alpha1_CredentialsStatus(in *ontap.CredentialsStatus, out *CredentialsStatus, s conversion.Scope) error {
+	out.LastRotationTime = (*v1.Time)(unsafe.Pointer(in.LastRotationTime))
+	out.LastRotationRequest = in.LastRotationRequest
+	return nil
+}
+
+// Convert_ontap_CredentialsStatus_To_v1alpha1_CredentialsStatus is an autogenerated conversion function.
+func Convert_ontap_CredentialsStatus_To_v1alpha1_CredentialsStatus(in *ontap.CredentialsStatus, out *CredentialsStatus, s conversion.Scope) error {
+	return autoConvert_ontap_CredentialsStatus_To_v1alpha1_CredentialsStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_DisasterRecoveryConfig_To_ontap_DisasterRecoveryConfig(in *DisasterRecoveryConfig, out *ontap.DisasterRecoveryConfig, s conversion.Scope) error {
+	if err := Convert_v1alpha1_SvmIpaddresses_To_ontap_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_DisasterRecoveryConfig_To_ontap_DisasterRecoveryConfig is an autogenerated conversion function.
+func Convert_v1alpha1_DisasterRecoveryConfig_To_ontap_DisasterRecoveryConfig(in *DisasterRecoveryConfig, out *ontap.DisasterRecoveryConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DisasterRecoveryConfig_To_ontap_DisasterRecoveryConfig(in, out, s)
+}
+
+func autoConvert_ontap_DisasterRecoveryConfig_To_v1alpha1_DisasterRecoveryConfig(in *ontap.DisasterRecoveryConfig, out *DisasterRecoveryConfig, s conversion.Scope) error {
+	if err := Convert_ontap_SvmIpaddresses_To_v1alpha1_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_ontap_DisasterRecoveryConfig_To_v1alpha1_DisasterRecoveryConfig is an autogenerated conversion function.
+func Convert_ontap_DisasterRecoveryConfig_To_v1alpha1_DisasterRecoveryConfig(in *ontap.DisasterRecoveryConfig, out *DisasterRecoveryConfig, s conversion.Scope) error {
+	return autoConvert_ontap_DisasterRecoveryConfig_To_v1alpha1_DisasterRecoveryConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_DisasterRecoveryStatus_To_ontap_DisasterRecoveryStatus(in *DisasterRecoveryStatus, out *ontap.DisasterRecoveryStatus, s conversion.Scope) error {
+	out.SVM = in.SVM
+	out.State = in.State
+	out.Healthy = in.Healthy
+	out.UnhealthyReason = in.UnhealthyReason
+	out.LagTime = in.LagTime
+	out.FailedOver = in.FailedOver
+	out.LastFailoverTime = (*v1.Time)(unsafe.Pointer(in.LastFailoverTime))
+	out.LastFailoverRequest = in.LastFailoverRequest
+	return nil
+}
+
+// Convert_v1alpha1_DisasterRecoveryStatus_To_ontap_DisasterRecoveryStatus is an autogenerated conversion function.
+func Convert_v1alpha1_DisasterRecoveryStatus_To_ontap_DisasterRecoveryStatus(in *DisasterRecoveryStatus, out *ontap.DisasterRecoveryStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DisasterRecoveryStatus_To_ontap_DisasterRecoveryStatus(in, out, s)
+}
+
+func autoConvert_ontap_DisasterRecoveryStatus_To_v1alpha1_DisasterRecoveryStatus(in *ontap.DisasterRecoveryStatus, out *DisasterRecoveryStatus, s conversion.Scope) error {
+	out.SVM = in.SVM
+	out.State = in.State
+	out.Healthy = in.Healthy
+	out.UnhealthyReason = in.UnhealthyReason
+	out.LagTime = in.LagTime
+	out.FailedOver = in.FailedOver
+	out.LastFailoverTime = (*v1.Time)(unsafe.Pointer(in.LastFailoverTime))
+	out.LastFailoverRequest = in.LastFailoverRequest
+	return nil
+}
+
+// Convert_ontap_DisasterRecoveryStatus_To_v1alpha1_DisasterRecoveryStatus is an autogenerated conversion function.
+func Convert_ontap_DisasterRecoveryStatus_To_v1alpha1_DisasterRecoveryStatus(in *ontap.DisasterRecoveryStatus, out *DisasterRecoveryStatus, s conversion.Scope) error {
+	return autoConvert_ontap_DisasterRecoveryStatus_To_v1alpha1_DisasterRecoveryStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_MetroClusterStatus_To_ontap_MetroClusterStatus(in *MetroClusterStatus, out *ontap.MetroClusterStatus, s conversion.Scope) error {
+	out.ActiveSite = in.ActiveSite
+	out.SVM = in.SVM
+	out.Mode = in.Mode
+	out.SwitchedOver = in.SwitchedOver
+	return nil
+}
+
+// Convert_v1alpha1_MetroClusterStatus_To_ontap_MetroClusterStatus is an autogenerated conversion function.
+func Convert_v1alpha1_MetroClusterStatus_To_ontap_MetroClusterStatus(in *MetroClusterStatus, out *ontap.MetroClusterStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MetroClusterStatus_To_ontap_MetroClusterStatus(in, out, s)
+}
+
+func autoConvert_ontap_MetroClusterStatus_To_v1alpha1_MetroClusterStatus(in *ontap.MetroClusterStatus, out *MetroClusterStatus, s conversion.Scope) error {
+	out.ActiveSite = in.ActiveSite
+	out.SVM = in.SVM
+	out.Mode = in.Mode
+	out.SwitchedOver = in.SwitchedOver
+	return nil
+}
+
+// Convert_ontap_MetroClusterStatus_To_v1alpha1_MetroClusterStatus is an autogenerated conversion function.
+func Convert_ontap_MetroClusterStatus_To_v1alpha1_MetroClusterStatus(in *ontap.MetroClusterStatus, out *MetroClusterStatus, s conversion.Scope) error {
+	return autoConvert_ontap_MetroClusterStatus_To_v1alpha1_MetroClusterStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_ReplicationConfig_To_ontap_ReplicationConfig(in *ReplicationConfig, out *ontap.ReplicationConfig, s conversion.Scope) error {
+	if err := Convert_v1alpha1_SvmIpaddresses_To_ontap_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
+		return err
+	}
+	out.StorageClass = in.StorageClass
+	return nil
+}
+
+// Convert_v1alpha1_ReplicationConfig_To_ontap_ReplicationConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ReplicationConfig_To_ontap_ReplicationConfig(in *ReplicationConfig, out *ontap.ReplicationConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ReplicationConfig_To_ontap_ReplicationConfig(in, out, s)
+}
+
+func autoConvert_ontap_ReplicationConfig_To_v1alpha1_ReplicationConfig(in *ontap.ReplicationConfig, out *ReplicationConfig, s conversion.Scope) error {
+	if err := Convert_ontap_SvmIpaddresses_To_v1alpha1_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
+		return err
+	}
+	out.StorageClass = in.StorageClass
+	return nil
+}
+
+// Convert_ontap_ReplicationConfig_To_v1alpha1_ReplicationConfig is an autogenerated conversion function.
+func Convert_ontap_ReplicationConfig_To_v1alpha1_ReplicationConfig(in *ontap.ReplicationConfig, out *ReplicationConfig, s conversion.Scope) error {
+	return autoConvert_ontap_ReplicationConfig_To_v1alpha1_ReplicationConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_SVMLimits_To_ontap_SVMLimits(in *SVMLimits, out *ontap.SVMLimits, s conversion.Scope) error {
+	out.StorageLimit = (*resource.Quantity)(unsafe.Pointer(in.StorageLimit))
+	out.MaxVolumes = (*int64)(unsafe.Pointer(in.MaxVolumes))
+	return nil
+}
+
+// Convert_v1alpha1_SVMLimits_To_ontap_SVMLimits is an autogenerated conversion function.
+func Convert_v1alpha1_SVMLimits_To_ontap_SVMLimits(in *SVMLimits, out *ontap.SVMLimits, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SVMLimits_To_ontap_SVMLimits(in, out, s)
+}
+
+func autoConvert_ontap_SVMLimits_To_v1alpha1_SVMLimits(in *ontap.SVMLimits, out *SVMLimits, s conversion.Scope) error {
+	out.StorageLimit = (*resource.Quantity)(unsafe.Pointer(in.StorageLimit))
+	out.MaxVolumes = (*int64)(unsafe.Pointer(in.MaxVolumes))
+	return nil
+}
+
+// Convert_ontap_SVMLimits_To_v1alpha1_SVMLimits is an autogenerated conversion function.
+func Convert_ontap_SVMLimits_To_v1alpha1_SVMLimits(in *ontap.SVMLimits, out *SVMLimits, s conversion.Scope) error {
+	return autoConvert_ontap_SVMLimits_To_v1alpha1_SVMLimits(in, out, s)
+}
+
+func autoConvert_v1alpha1_ShootUsageStatus_To_ontap_ShootUsageStatus(in *ShootUsageStatus, out *ontap.ShootUsageStatus, s conversion.Scope) error {
+	out.Volumes = in.Volumes
+	out.Provisioned = in.Provisioned
+	out.Used = in.Used
+	return nil
+}
+
+// Convert_v1alpha1_ShootUsageStatus_To_ontap_ShootUsageStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ShootUsageStatus_To_ontap_ShootUsageStatus(in *ShootUsageStatus, out *ontap.ShootUsageStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ShootUsageStatus_To_ontap_ShootUsageStatus(in, out, s)
+}
+
+func autoConvert_ontap_ShootUsageStatus_To_v1alpha1_ShootUsageStatus(in *ontap.ShootUsageStatus, out *ShootUsageStatus, s conversion.Scope) error {
+	out.Volumes = in.Volumes
+	out.Provisioned = in.Provisioned
+	out.Used = in.Used
+	return nil
+}
+
+// Convert_ontap_ShootUsageStatus_To_v1alpha1_ShootUsageStatus is an autogenerated conversion function.
+func Convert_ontap_ShootUsageStatus_To_v1alpha1_ShootUsageStatus(in *ontap.ShootUsageStatus, out *ShootUsageStatus, s conversion.Scope) error {
+	return autoConvert_ontap_ShootUsageStatus_To_v1alpha1_ShootUsageStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_SnapshotConfig_To_ontap_SnapshotConfig(in *SnapshotConfig, out *ontap.SnapshotConfig, s conversion.Scope) error {
+	out.Policy = (*ontap.SnapshotPolicy)(unsafe.Pointer(in.Policy))
+	out.TierPolicies = *(*[]ontap.TierSnapshotPolicy)(unsafe.Pointer(&in.TierPolicies))
+	out.Reserve = (*int32)(unsafe.Pointer(in.Reserve))
+	out.VolumeSnapshotClass = in.VolumeSnapshotClass
+	out.DeletionPolicy = in.DeletionPolicy
+	out.GroupSnapshots = in.GroupSnapshots
+	out.VolumeGroupSnapshotClass = in.VolumeGroupSnapshotClass
+	out.InPlaceRestore = in.InPlaceRestore
+	return nil
+}
+
+// Convert_v1alpha1_SnapshotConfig_To_ontap_SnapshotConfig is an autogenerated conversion function.
+func Convert_v1alpha1_SnapshotConfig_To_ontap_SnapshotConfig(in *SnapshotConfig, out *ontap.SnapshotConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SnapshotConfig_To_ontap_SnapshotConfig(in, out, s)
+}
+
+func autoConvert_ontap_SnapshotConfig_To_v1alpha1_SnapshotConfig(in *ontap.SnapshotConfig, out *SnapshotConfig, s conversion.Scope) error {
+	out.Policy = (*SnapshotPolicy)(unsafe.Pointer(in.Policy))
+	out.TierPolicies = *(*[]TierSnapshotPolicy)(unsafe.Pointer(&in.TierPolicies))
+	out.Reserve = (*int32)(unsafe.Pointer(in.Reserve))
+	out.VolumeSnapshotClass = in.VolumeSnapshotClass
+	out.DeletionPolicy = in.DeletionPolicy
+	out.GroupSnapshots = in.GroupSnapshots
+	out.VolumeGroupSnapshotClass = in.VolumeGroupSnapshotClass
+	out.InPlaceRestore = in.InPlaceRestore
+	return nil
+}
+
+// Convert_ontap_SnapshotConfig_To_v1alpha1_SnapshotConfig is an autogenerated conversion function.
+func Convert_ontap_SnapshotConfig_To_v1alpha1_SnapshotConfig(in *ontap.SnapshotConfig, out *SnapshotConfig, s conversion.Scope) error {
+	return autoConvert_ontap_SnapshotConfig_To_v1alpha1_SnapshotConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_SnapshotPolicy_To_ontap_SnapshotPolicy(in *SnapshotPolicy, out *ontap.SnapshotPolicy, s conversion.Scope) error {
+	out.Schedules = *(*[]ontap.SnapshotSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+// Convert_v1alpha1_SnapshotPolicy_To_ontap_SnapshotPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_SnapshotPolicy_To_ontap_SnapshotPolicy(in *SnapshotPolicy, out *ontap.SnapshotPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SnapshotPolicy_To_ontap_SnapshotPolicy(in, out, s)
+}
+
+func autoConvert_ontap_SnapshotPolicy_To_v1alpha1_SnapshotPolicy(in *ontap.SnapshotPolicy, out *SnapshotPolicy, s conversion.Scope) error {
+	out.Schedules = *(*[]SnapshotSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+// Convert_ontap_SnapshotPolicy_To_v1alpha1_SnapshotPolicy is an autogenerated conversion function.
+func Convert_ontap_SnapshotPolicy_To_v1alpha1_SnapshotPolicy(in *ontap.SnapshotPolicy, out *SnapshotPolicy, s conversion.Scope) error {
+	return autoConvert_ontap_SnapshotPolicy_To_v1alpha1_SnapshotPolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_SnapshotSchedule_To_ontap_SnapshotSchedule(in *SnapshotSchedule, out *ontap.SnapshotSchedule, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Count = in.Count
 	return nil
 }
 
+// Convert_v1alpha1_SnapshotSchedule_To_ontap_SnapshotSchedule is an autogenerated conversion function.
+func Convert_v1alpha1_SnapshotSchedule_To_ontap_SnapshotSchedule(in *SnapshotSchedule, out *ontap.SnapshotSchedule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SnapshotSchedule_To_ontap_SnapshotSchedule(in, out, s)
+}
+
+func autoConvert_ontap_SnapshotSchedule_To_v1alpha1_SnapshotSchedule(in *ontap.SnapshotSchedule, out *SnapshotSchedule, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Count = in.Count
+	return nil
+}
+
+// Convert_ontap_SnapshotSchedule_To_v1alpha1_SnapshotSchedule is an autogenerated conversion function.
+func Convert_ontap_SnapshotSchedule_To_v1alpha1_SnapshotSchedule(in *ontap.SnapshotSchedule, out *SnapshotSchedule, s conversion.Scope) error {
+	return autoConvert_ontap_SnapshotSchedule_To_v1alpha1_SnapshotSchedule(in, out, s)
+}
+
+func autoConvert_v1alpha1_StorageClass_To_ontap_StorageClass(in *StorageClass, out *ontap.StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Protocol = ontap.Protocol(in.Protocol)
+	out.Default = in.Default
+	out.FSType = in.FSType
+	out.ProvisioningType = ontap.ProvisioningType(in.ProvisioningType)
+	out.LUKS = in.LUKS
+	out.ReclaimPolicy = (*corev1.PersistentVolumeReclaimPolicy)(unsafe.Pointer(in.ReclaimPolicy))
+	out.VolumeBindingMode = (*storagev1.VolumeBindingMode)(unsafe.Pointer(in.VolumeBindingMode))
+	out.MountOptions = *(*[]string)(unsafe.Pointer(&in.MountOptions))
+	out.QoSTier = in.QoSTier
+	return nil
+}
+
+// Convert_v1alpha1_StorageClass_To_ontap_StorageClass is an autogenerated conversion function.
+func Convert_v1alpha1_StorageClass_To_ontap_StorageClass(in *StorageClass, out *ontap.StorageClass, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StorageClass_To_ontap_StorageClass(in, out, s)
+}
+
+func autoConvert_ontap_StorageClass_To_v1alpha1_StorageClass(in *ontap.StorageClass, out *StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Protocol = Protocol(in.Protocol)
+	out.Default = in.Default
+	out.FSType = in.FSType
+	out.ProvisioningType = ProvisioningType(in.ProvisioningType)
+	out.LUKS = in.LUKS
+	out.ReclaimPolicy = (*corev1.PersistentVolumeReclaimPolicy)(unsafe.Pointer(in.ReclaimPolicy))
+	out.VolumeBindingMode = (*storagev1.VolumeBindingMode)(unsafe.Pointer(in.VolumeBindingMode))
+	out.MountOptions = *(*[]string)(unsafe.Pointer(&in.MountOptions))
+	out.QoSTier = in.QoSTier
+	return nil
+}
+
+// Convert_ontap_StorageClass_To_v1alpha1_StorageClass is an autogenerated conversion function.
+func Convert_ontap_StorageClass_To_v1alpha1_StorageClass(in *ontap.StorageClass, out *StorageClass, s conversion.Scope) error {
+	return autoConvert_ontap_StorageClass_To_v1alpha1_StorageClass(in, out, s)
+}
+
 func autoConvert_v1alpha1_SvmIpaddresses_To_ontap_SvmIpaddresses(in *SvmIpaddresses, out *ontap.SvmIpaddresses, s conversion.Scope) error {
 	out.DataLifs = *(*[]string)(unsafe.Pointer(&in.DataLifs))
 	out.ManagementLif = in.ManagementLif
@@ -69,10 +537,38 @@ func Convert_ontap_SvmIpaddresses_To_v1alpha1_SvmIpaddresses(in *ontap.SvmIpaddr
 	return autoConvert_ontap_SvmIpaddresses_To_v1alpha1_SvmIpaddresses(in, out, s)
 }
 
+func autoConvert_v1alpha1_TierSnapshotPolicy_To_ontap_TierSnapshotPolicy(in *TierSnapshotPolicy, out *ontap.TierSnapshotPolicy, s conversion.Scope) error {
+	out.Tier = in.Tier
+	out.Schedules = *(*[]ontap.SnapshotSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+// Convert_v1alpha1_TierSnapshotPolicy_To_ontap_TierSnapshotPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_TierSnapshotPolicy_To_ontap_TierSnapshotPolicy(in *TierSnapshotPolicy, out *ontap.TierSnapshotPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TierSnapshotPolicy_To_ontap_TierSnapshotPolicy(in, out, s)
+}
+
+func autoConvert_ontap_TierSnapshotPolicy_To_v1alpha1_TierSnapshotPolicy(in *ontap.TierSnapshotPolicy, out *TierSnapshotPolicy, s conversion.Scope) error {
+	out.Tier = in.Tier
+	out.Schedules = *(*[]SnapshotSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+// Convert_ontap_TierSnapshotPolicy_To_v1alpha1_TierSnapshotPolicy is an autogenerated conversion function.
+func Convert_ontap_TierSnapshotPolicy_To_v1alpha1_TierSnapshotPolicy(in *ontap.TierSnapshotPolicy, out *TierSnapshotPolicy, s conversion.Scope) error {
+	return autoConvert_ontap_TierSnapshotPolicy_To_v1alpha1_TierSnapshotPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_TridentConfig_To_ontap_TridentConfig(in *TridentConfig, out *ontap.TridentConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha1_SvmIpaddresses_To_ontap_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
 		return err
 	}
+	out.Protocols = *(*[]ontap.Protocol)(unsafe.Pointer(&in.Protocols))
+	out.StorageClasses = *(*[]ontap.StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.SVMLimits = (*ontap.SVMLimits)(unsafe.Pointer(in.SVMLimits))
+	out.Snapshots = (*ontap.SnapshotConfig)(unsafe.Pointer(in.Snapshots))
+	out.Replication = (*ontap.ReplicationConfig)(unsafe.Pointer(in.Replication))
+	out.DisasterRecovery = (*ontap.DisasterRecoveryConfig)(unsafe.Pointer(in.DisasterRecovery))
 	return nil
 }
 
@@ -85,6 +581,12 @@ func autoConvert_ontap_TridentConfig_To_v1alpha1_TridentConfig(in *ontap.Trident
 	if err := Convert_ontap_SvmIpaddresses_To_v1alpha1_SvmIpaddresses(&in.SvmIpaddresses, &out.SvmIpaddresses, s); err != nil {
 		return err
 	}
+	out.Protocols = *(*[]Protocol)(unsafe.Pointer(&in.Protocols))
+	out.StorageClasses = *(*[]StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.SVMLimits = (*SVMLimits)(unsafe.Pointer(in.SVMLimits))
+	out.Snapshots = (*SnapshotConfig)(unsafe.Pointer(in.Snapshots))
+	out.Replication = (*ReplicationConfig)(unsafe.Pointer(in.Replication))
+	out.DisasterRecovery = (*DisasterRecoveryConfig)(unsafe.Pointer(in.DisasterRecovery))
 	return nil
 }
 
@@ -92,3 +594,61 @@ func autoConvert_ontap_TridentConfig_To_v1alpha1_TridentConfig(in *ontap.Trident
 func Convert_ontap_TridentConfig_To_v1alpha1_TridentConfig(in *ontap.TridentConfig, out *TridentConfig, s conversion.Scope) error {
 	return autoConvert_ontap_TridentConfig_To_v1alpha1_TridentConfig(in, out, s)
 }
+
+func autoConvert_v1alpha1_TridentStatus_To_ontap_TridentStatus(in *TridentStatus, out *ontap.TridentStatus, s conversion.Scope) error {
+	out.Credentials = (*ontap.CredentialsStatus)(unsafe.Pointer(in.Credentials))
+	out.Account = (*ontap.AccountStatus)(unsafe.Pointer(in.Account))
+	out.Usage = (*ontap.UsageStatus)(unsafe.Pointer(in.Usage))
+	out.StoragePrefix = in.StoragePrefix
+	out.ShootUsage = (*ontap.ShootUsageStatus)(unsafe.Pointer(in.ShootUsage))
+	out.DisasterRecovery = (*ontap.DisasterRecoveryStatus)(unsafe.Pointer(in.DisasterRecovery))
+	out.MetroCluster = (*ontap.MetroClusterStatus)(unsafe.Pointer(in.MetroCluster))
+	return nil
+}
+
+// Convert_v1alpha1_TridentStatus_To_ontap_TridentStatus is an autogenerated conversion function.
+func Convert_v1alpha1_TridentStatus_To_ontap_TridentStatus(in *TridentStatus, out *ontap.TridentStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TridentStatus_To_ontap_TridentStatus(in, out, s)
+}
+
+func autoConvert_ontap_TridentStatus_To_v1alpha1_TridentStatus(in *ontap.TridentStatus, out *TridentStatus, s conversion.Scope) error {
+	out.Credentials = (*CredentialsStatus)(unsafe.Pointer(in.Credentials))
+	out.Account = (*AccountStatus)(unsafe.Pointer(in.Account))
+	out.Usage = (*UsageStatus)(unsafe.Pointer(in.Usage))
+	out.StoragePrefix = in.StoragePrefix
+	out.ShootUsage = (*ShootUsageStatus)(unsafe.Pointer(in.ShootUsage))
+	out.DisasterRecovery = (*DisasterRecoveryStatus)(unsafe.Pointer(in.DisasterRecovery))
+	out.MetroCluster = (*MetroClusterStatus)(unsafe.Pointer(in.MetroCluster))
+	return nil
+}
+
+// Convert_ontap_TridentStatus_To_v1alpha1_TridentStatus is an autogenerated conversion function.
+func Convert_ontap_TridentStatus_To_v1alpha1_TridentStatus(in *ontap.TridentStatus, out *TridentStatus, s conversion.Scope) error {
+	return autoConvert_ontap_TridentStatus_To_v1alpha1_TridentStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_UsageStatus_To_ontap_UsageStatus(in *UsageStatus, out *ontap.UsageStatus, s conversion.Scope) error {
+	out.StorageLimit = (*resource.Quantity)(unsafe.Pointer(in.StorageLimit))
+	out.StorageAllocated = in.StorageAllocated
+	out.MaxVolumes = (*int64)(unsafe.Pointer(in.MaxVolumes))
+	out.Volumes = in.Volumes
+	return nil
+}
+
+// Convert_v1alpha1_UsageStatus_To_ontap_UsageStatus is an autogenerated conversion function.
+func Convert_v1alpha1_UsageStatus_To_ontap_UsageStatus(in *UsageStatus, out *ontap.UsageStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UsageStatus_To_ontap_UsageStatus(in, out, s)
+}
+
+func autoConvert_ontap_UsageStatus_To_v1alpha1_UsageStatus(in *ontap.UsageStatus, out *UsageStatus, s conversion.Scope) error {
+	out.StorageLimit = (*resource.Quantity)(unsafe.Pointer(in.StorageLimit))
+	out.StorageAllocated = in.StorageAllocated
+	out.MaxVolumes = (*int64)(unsafe.Pointer(in.MaxVolumes))
+	out.Volumes = in.Volumes
+	return nil
+}
+
+// Convert_ontap_UsageStatus_To_v1alpha1_UsageStatus is an autogenerated conversion function.
+func Convert_ontap_UsageStatus_To_v1alpha1_UsageStatus(in *ontap.UsageStatus, out *UsageStatus, s conversion.Scope) error {
+	return autoConvert_ontap_UsageStatus_To_v1alpha1_UsageStatus(in, out, s)
+}
@@ -10,9 +10,262 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
+func (in *AccountStatus) DeepCopy() *AccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsStatus) DeepCopyInto(out *CredentialsStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsStatus.
+func (in *CredentialsStatus) DeepCopy() *CredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryConfig) DeepCopyInto(out *DisasterRecoveryConfig) {
+	*out = *in
+	in.SvmIpaddresses.DeepCopyInto(&out.SvmIpaddresses)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryConfig.
+func (in *DisasterRecoveryConfig) DeepCopy() *DisasterRecoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
+	*out = *in
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
+func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetroClusterStatus) DeepCopyInto(out *MetroClusterStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetroClusterStatus.
+func (in *MetroClusterStatus) DeepCopy() *MetroClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MetroClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConfig) DeepCopyInto(out *ReplicationConfig) {
+	*out = *in
+	in.SvmIpaddresses.DeepCopyInto(&out.SvmIpaddresses)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConfig.
+func (in *ReplicationConfig) DeepCopy() *ReplicationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SVMLimits) DeepCopyInto(out *SVMLimits) {
+	*out = *in
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxVolumes != nil {
+		in, out := &in.MaxVolumes, &out.MaxVolumes
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SVMLimits.
+func (in *SVMLimits) DeepCopy() *SVMLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(SVMLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShootUsageStatus) DeepCopyInto(out *ShootUsageStatus) {
+	*out = *in
+	out.Provisioned = in.Provisioned.DeepCopy()
+	out.Used = in.Used.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShootUsageStatus.
+func (in *ShootUsageStatus) DeepCopy() *ShootUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShootUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotConfig) DeepCopyInto(out *SnapshotConfig) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(SnapshotPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TierPolicies != nil {
+		in, out := &in.TierPolicies, &out.TierPolicies
+		*out = make([]TierSnapshotPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotConfig.
+func (in *SnapshotConfig) DeepCopy() *SnapshotConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotPolicy) DeepCopyInto(out *SnapshotPolicy) {
+	*out = *in
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]SnapshotSchedule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotPolicy.
+func (in *SnapshotPolicy) DeepCopy() *SnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSchedule) DeepCopyInto(out *SnapshotSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSchedule.
+func (in *SnapshotSchedule) DeepCopy() *SnapshotSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClass) DeepCopyInto(out *StorageClass) {
+	*out = *in
+	if in.ReclaimPolicy != nil {
+		in, out := &in.ReclaimPolicy, &out.ReclaimPolicy
+		*out = new(v1.PersistentVolumeReclaimPolicy)
+		**out = **in
+	}
+	if in.VolumeBindingMode != nil {
+		in, out := &in.VolumeBindingMode, &out.VolumeBindingMode
+		*out = new(storagev1.VolumeBindingMode)
+		**out = **in
+	}
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClass.
+func (in *StorageClass) DeepCopy() *StorageClass {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SvmIpaddresses) DeepCopyInto(out *SvmIpaddresses) {
 	*out = *in
@@ -34,11 +287,64 @@ func (in *SvmIpaddresses) DeepCopy() *SvmIpaddresses {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TierSnapshotPolicy) DeepCopyInto(out *TierSnapshotPolicy) {
+	*out = *in
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]SnapshotSchedule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TierSnapshotPolicy.
+func (in *TierSnapshotPolicy) DeepCopy() *TierSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TierSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TridentConfig) DeepCopyInto(out *TridentConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.SvmIpaddresses.DeepCopyInto(&out.SvmIpaddresses)
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]Protocol, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]StorageClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SVMLimits != nil {
+		in, out := &in.SVMLimits, &out.SVMLimits
+		*out = new(SVMLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(SnapshotConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -59,3 +365,85 @@ func (in *TridentConfig) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentStatus) DeepCopyInto(out *TridentStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(CredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Account != nil {
+		in, out := &in.Account, &out.Account
+		*out = new(AccountStatus)
+		**out = **in
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(UsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShootUsage != nil {
+		in, out := &in.ShootUsage, &out.ShootUsage
+		*out = new(ShootUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetroCluster != nil {
+		in, out := &in.MetroCluster, &out.MetroCluster
+		*out = new(MetroClusterStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentStatus.
+func (in *TridentStatus) DeepCopy() *TridentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TridentStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageStatus) DeepCopyInto(out *UsageStatus) {
+	*out = *in
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.StorageAllocated = in.StorageAllocated.DeepCopy()
+	if in.MaxVolumes != nil {
+		in, out := &in.MaxVolumes, &out.MaxVolumes
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageStatus.
+func (in *UsageStatus) DeepCopy() *UsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
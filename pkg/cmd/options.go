@@ -6,6 +6,8 @@ import (
 	webhookcmd "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
 	extensionshootwebhook "github.com/gardener/gardener/extensions/pkg/webhook/shoot"
 
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/drift"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/gc"
 	ontap "github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
 	shootwebhook "github.com/metal-stack/gardener-extension-ontap/pkg/webhook/shoot"
 )
@@ -14,6 +16,8 @@ import (
 func ControllerSwitchOptions() *controllercmd.SwitchOptions {
 	return controllercmd.NewSwitchOptions(
 		controllercmd.Switch(ontap.ControllerName, ontap.AddToManager),
+		controllercmd.Switch(drift.ControllerName, drift.AddToManager),
+		controllercmd.Switch(gc.ControllerName, gc.AddToManager),
 		controllercmd.Switch(extensionsheartbeatcontroller.ControllerName, extensionsheartbeatcontroller.AddToManager),
 	)
 }
@@ -0,0 +1,73 @@
+package drift
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+const (
+	// ControllerName is the name of the drift detection controller.
+	ControllerName = "ontap_drift_controller"
+)
+
+var (
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
+
+// AddOptions are options to apply when adding the drift detection controller to the manager.
+type AddOptions struct {
+	// Config contains configuration for the drift detection.
+	Config config.ControllerConfiguration
+}
+
+// AddToManager adds the drift detection controller with the default Options to the given Controller Manager.
+func AddToManager(ctx context.Context, mgr manager.Manager) error {
+	return AddToManagerWithOptions(ctx, mgr, DefaultAddOptions)
+}
+
+// AddToManagerWithOptions adds the drift detection controller with the given Options to the given manager.
+// Nothing is added if drift detection is not configured.
+func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
+	log := runtimelog.Log.WithName(ControllerName)
+
+	if opts.Config.DriftDetection == nil {
+		log.Info("drift detection is not configured, not adding controller")
+		return nil
+	}
+
+	clients, err := ontap.CreateAdminClients(ctx, opts.Config)
+	if err != nil {
+		return err
+	}
+
+	naming, err := trident.NewNaming(opts.Config.Naming)
+	if err != nil {
+		return err
+	}
+
+	return mgr.Add(&detector{
+		log:                       log,
+		clients:                   clients,
+		drClient:                  ontap.SVMDRClient(opts.Config, clients),
+		clusterNames:              ontap.ClusterNames(opts.Config, clients),
+		svmLimits:                 opts.Config.SVMLimits,
+		client:                    mgr.GetClient(),
+		decoder:                   serializer.NewCodecFactory(mgr.GetScheme()).UniversalDeserializer(),
+		recorder:                  mgr.GetEventRecorder(ControllerName),
+		config:                    *opts.Config.DriftDetection,
+		seed:                      opts.Config.SeedName,
+		passwordPolicy:            opts.Config.PasswordPolicy,
+		certificateAuthentication: opts.Config.CertificateAuthentication,
+		accountRole:               trident.AccountRoleName(opts.Config.AccountRole),
+		naming:                    naming,
+		passwordPolicies:          trident.NewPasswordPolicyValidator(opts.Config.PasswordPolicy, trident.AccountRoleName(opts.Config.AccountRole)),
+	})
+}
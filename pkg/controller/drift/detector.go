@@ -0,0 +1,168 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+// detector periodically compares the SVMs of all ontap Extensions with their desired state.
+// It only runs on the leader to avoid concurrent repairs.
+type detector struct {
+	log     logr.Logger
+	clients []*ontapv1.Ontap
+	client  client.Client
+	// drClient is the client of the DR cluster of SVM-DR, no SVMs of projects are placed on it
+	drClient *ontapv1.Ontap
+	decoder  runtime.Decoder
+	recorder k8sevents.EventRecorder
+	config   config.DriftDetectionConfig
+	// seed is the configured seed name, it falls back to the seed of the Cluster if empty
+	seed string
+	// passwordPolicy is used if an account has to be recreated
+	passwordPolicy *config.PasswordPolicyConfig
+	// certificateAuthentication is set if the accounts authenticate with client certificates
+	certificateAuthentication *config.CertificateAuthenticationConfig
+	// accountRole is the ONTAP role of recreated accounts
+	accountRole string
+	// naming renders the names of the SVM, its LIFs and the credentials secret
+	naming *trident.Naming
+	// clusterNames are the configured names of the clusters of the clients
+	clusterNames map[*ontapv1.Ontap]string
+	// svmLimits are the default limits of the SVMs, repairs converge the SVMs to them like the actuator
+	svmLimits *ontapv1alpha1.SVMLimits
+	// passwordPolicies validates the password policy before accounts are recreated
+	passwordPolicies *trident.PasswordPolicyValidator
+}
+
+var (
+	_ manager.Runnable               = &detector{}
+	_ manager.LeaderElectionRunnable = &detector{}
+)
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (d *detector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable, it blocks until the context is cancelled.
+func (d *detector) Start(ctx context.Context) error {
+	d.log.Info("starting drift detection", "interval", d.config.Interval.Duration, "policy", d.config.Policy)
+
+	wait.JitterUntilWithContext(ctx, d.detectAll, d.config.Interval.Duration, 0.1, true)
+	return nil
+}
+
+// detectAll runs the drift detection for all ontap Extensions, failures of single Extensions are only logged.
+func (d *detector) detectAll(ctx context.Context) {
+	extensions := &extensionsv1alpha1.ExtensionList{}
+	if err := d.client.List(ctx, extensions); err != nil {
+		d.log.Error(err, "unable to list extensions")
+		return
+	}
+
+	// drop the drift of shoots which are gone or repaired in the meantime
+	metrics.SVMDrift.Reset()
+
+	for i := range extensions.Items {
+		ex := &extensions.Items[i]
+		if ex.Spec.Type != ontap.ControllerType || ex.DeletionTimestamp != nil || ex.Status.LastOperation == nil {
+			continue
+		}
+
+		log := d.log.WithValues("namespace", ex.Namespace)
+		if err := d.detect(ctx, log, ex); err != nil {
+			log.Error(err, "drift detection failed")
+		}
+	}
+}
+
+// svmOptions returns the options the SVM of the Extension is compared with and repaired with, they match the options
+// of the actuator so a repair does not revert the limits or protocols of a reconcile.
+func (d *detector) svmOptions(ex *extensionsv1alpha1.Extension, resolved *ontap.ResolvedExtension, projectConfigs []*ontapv1alpha1.TridentConfig) trident.CreateSVMOptions {
+	opts := trident.CreateSVMOptions{
+		ProjectID:                 resolved.SVMName,
+		ShootNamespace:            ex.Namespace,
+		SvmIpaddresses:            resolved.TridentConfig.SvmIpaddresses,
+		SvmSeedSecretNamespace:    ex.Namespace,
+		Seed:                      resolved.OwnerSeed(d.seed),
+		Owner:                     ex,
+		PasswordPolicy:            d.passwordPolicy,
+		CertificateAuthentication: d.certificateAuthentication,
+		AccountRole:               d.accountRole,
+		Naming:                    d.naming,
+		SVMAliases:                resolved.SVMAliases,
+		Protocols:                 resolved.TridentConfig.Protocols,
+		Limits:                    trident.ProjectSVMLimits(projectConfigs, d.svmLimits),
+		SVMProtocols:              trident.ProjectProtocols(projectConfigs),
+	}
+	if d.drClient != nil {
+		opts.ExcludedClients = []*ontapv1.Ontap{d.drClient}
+	}
+	// the SVM is stopped after a failover and must not be started again by a repair
+	if dr := resolved.TridentConfig.DisasterRecovery; dr != nil && ontap.FailedOver(d.decoder, ex) {
+		opts = trident.DRSVMOptions(opts, dr)
+	}
+	return opts
+}
+
+func (d *detector) detect(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) (err error) {
+	ctx, span := tracing.Start(tracing.WithAttributes(ctx, tracing.ShootKey.String(ex.Namespace)), "DriftDetection")
+	defer func() { tracing.End(span, err) }()
+
+	resolved, err := ontap.ResolveExtension(ctx, log, d.client, d.decoder, d.naming, ex)
+	if err != nil {
+		return err
+	}
+	ctx = tracing.WithAttributes(ctx, tracing.SVMKey.String(resolved.SVMName))
+
+	projectConfigs, err := ontap.ProjectConfigs(ctx, log, d.client, d.decoder, d.naming, ex, resolved)
+	if err != nil {
+		return err
+	}
+
+	var (
+		recorder   = events.NewRecorder(log, d.recorder, ex, nil)
+		svmManager = trident.NewSvmManager(log, d.clients, d.client, recorder).WithClusterNames(d.clusterNames).WithPasswordPolicyValidator(d.passwordPolicies)
+		opts       = d.svmOptions(ex, resolved, projectConfigs)
+	)
+
+	report, err := svmManager.DetectDrift(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to detect drift of SVM %s: %w", opts.ProjectID, err)
+	}
+	if len(report.Drifts) == 0 {
+		log.Info("no drift detected", "svm", opts.ProjectID)
+		return nil
+	}
+
+	repairable := false
+	for _, drift := range report.Drifts {
+		metrics.SVMDrift.WithLabelValues(ex.Namespace, opts.ProjectID, string(drift.Type)).Set(1)
+		log.Info("drift detected", "svm", opts.ProjectID, "type", drift.Type, "object", drift.Object, "message", drift.Message)
+		recorder.Warning(ctx, events.ReasonDriftDetected, events.ActionDetect, "detected drift %s: %s", drift.Type, drift.Message)
+		repairable = repairable || drift.Repairable()
+	}
+
+	if d.config.Policy != config.DriftPolicyRepair || !repairable {
+		return nil
+	}
+
+	return svmManager.RepairDrift(ctx, opts, report)
+}
@@ -0,0 +1,34 @@
+package drift
+
+import (
+	"testing"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+)
+
+func TestSVMOptionsLimits(t *testing.T) {
+	ex := &extensionsv1alpha1.Extension{ObjectMeta: metav1.ObjectMeta{Name: "ontap", Namespace: "shoot--proj--a"}}
+	config := &ontapv1alpha1.TridentConfig{SVMLimits: &ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(10))}}
+	resolved := &ontap.ResolvedExtension{TridentConfig: config, SVMName: "proj"}
+
+	d := &detector{svmLimits: &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(100))}}
+
+	// a repair converges the SVM to the same limits as the actuator and does not lift them
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(10))},
+		d.svmOptions(ex, resolved, []*ontapv1alpha1.TridentConfig{config}).Limits,
+	)
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(20))},
+		d.svmOptions(ex, resolved, []*ontapv1alpha1.TridentConfig{
+			config,
+			{SVMLimits: &ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(20))}},
+		}).Limits,
+	)
+}
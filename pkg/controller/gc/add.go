@@ -0,0 +1,74 @@
+package gc
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+const (
+	// ControllerName is the name of the garbage collection controller.
+	ControllerName = "ontap_gc_controller"
+
+	// eventNamespace is the namespace in the seed where events about orphans are recorded.
+	eventNamespace = "kube-system"
+)
+
+var (
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
+
+// AddOptions are options to apply when adding the garbage collection controller to the manager.
+type AddOptions struct {
+	// Config contains configuration for the garbage collection.
+	Config config.ControllerConfiguration
+}
+
+// AddToManager adds the garbage collection controller with the default Options to the given Controller Manager.
+func AddToManager(ctx context.Context, mgr manager.Manager) error {
+	return AddToManagerWithOptions(ctx, mgr, DefaultAddOptions)
+}
+
+// AddToManagerWithOptions adds the garbage collection controller with the given Options to the given manager.
+// Nothing is added if garbage collection is not configured.
+func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
+	log := runtimelog.Log.WithName(ControllerName)
+
+	if opts.Config.GarbageCollection == nil {
+		log.Info("garbage collection is not configured, not adding controller")
+		return nil
+	}
+
+	clients, err := ontap.CreateAdminClients(ctx, opts.Config)
+	if err != nil {
+		return err
+	}
+
+	naming, err := trident.NewNaming(opts.Config.Naming)
+	if err != nil {
+		return err
+	}
+
+	return mgr.Add(&collector{
+		log:          log,
+		clients:      clients,
+		clusterNames: ontap.ClusterNames(opts.Config, clients),
+		client:       mgr.GetClient(),
+		decoder:      serializer.NewCodecFactory(mgr.GetScheme()).UniversalDeserializer(),
+		recorder:     events.NewNamespaceRecorder(log, mgr.GetEventRecorder(ControllerName), eventNamespace),
+		config:       *opts.Config.GarbageCollection,
+		seed:         opts.Config.SeedName,
+		naming:       naming,
+		now:          time.Now,
+		firstSeen:    map[string]time.Time{},
+	})
+}
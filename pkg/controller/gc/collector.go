@@ -0,0 +1,299 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/controller/ontap"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+const (
+	orphanKindAccount = "Account"
+	orphanKindSVM     = "SVM"
+)
+
+// orphan is an account or an SVM which does not belong to any shoot of the seed.
+type orphan struct {
+	kind    string
+	svm     *trident.ManagedSVM
+	account string
+	// owned is true if the ownership recorded on the object matches the seed, only owned orphans are removed
+	owned bool
+}
+
+func (o orphan) key() string {
+	if o.kind == orphanKindAccount {
+		return fmt.Sprintf("%s/%s/%s/%s", o.kind, o.svm.Cluster, o.svm.Name, o.account)
+	}
+	return fmt.Sprintf("%s/%s/%s", o.kind, o.svm.Cluster, o.svm.Name)
+}
+
+func (o orphan) String() string {
+	if o.kind == orphanKindAccount {
+		return fmt.Sprintf("account %s of SVM %s on cluster %s", o.account, o.svm.Name, o.svm.Cluster)
+	}
+	return fmt.Sprintf("SVM %s on cluster %s", o.svm.Name, o.svm.Cluster)
+}
+
+// collector periodically maps the SVMs and accounts on all clusters back to the ontap Extensions of the seed
+// and reports, and optionally removes, the ones without a shoot. It only runs on the leader.
+type collector struct {
+	log      logr.Logger
+	clients  []*ontapv1.Ontap
+	client   client.Client
+	decoder  runtime.Decoder
+	recorder *events.Recorder
+	config   config.GarbageCollectionConfig
+	// seed is the name of the seed, it falls back to the seed of the Clusters if not configured
+	seed string
+	// naming derives the SVM names of the live shoots
+	naming *trident.Naming
+	now    func() time.Time
+	// clusterNames are the configured names of the clusters of the clients
+	clusterNames map[*ontapv1.Ontap]string
+
+	// firstSeen stores when an orphan was detected first, the grace period restarts with the controller
+	firstSeen map[string]time.Time
+}
+
+var (
+	_ manager.Runnable               = &collector{}
+	_ manager.LeaderElectionRunnable = &collector{}
+)
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *collector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable, it blocks until the context is cancelled.
+func (c *collector) Start(ctx context.Context) error {
+	c.log.Info("starting garbage collection", "interval", c.config.Interval.Duration, "gracePeriod", c.config.GracePeriod.Duration, "remove", c.config.Remove, "dryRun", c.config.DryRun)
+
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.collect(ctx); err != nil {
+			c.log.Error(err, "garbage collection failed")
+		}
+	}, c.config.Interval.Duration, 0.1, true)
+	return nil
+}
+
+func (c *collector) collect(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "GarbageCollection")
+	defer func() { tracing.End(span, err) }()
+
+	live, seed, err := c.liveShoots(ctx)
+	if err != nil {
+		return err
+	}
+	if c.seed != "" {
+		seed = c.seed
+	}
+
+	svmManager := trident.NewSvmManager(c.log, c.clients, c.client, c.recorder).WithClusterNames(c.clusterNames)
+	svms, err := svmManager.ListManagedSVMs(ctx)
+	if err != nil {
+		return err
+	}
+
+	orphans := findOrphans(svms, live, seed)
+
+	metrics.OrphanedSVMs.Reset()
+	metrics.OrphanedAccounts.Reset()
+	for _, rc := range c.clients {
+		cluster := svmManager.ClusterName(rc)
+		metrics.OrphanedSVMs.WithLabelValues(cluster).Set(0)
+		metrics.OrphanedAccounts.WithLabelValues(cluster).Set(0)
+	}
+
+	var (
+		now  = c.now()
+		seen = map[string]bool{}
+		errs []error
+	)
+	for _, o := range orphans {
+		switch o.kind {
+		case orphanKindSVM:
+			metrics.OrphanedSVMs.WithLabelValues(o.svm.Cluster).Inc()
+		case orphanKindAccount:
+			metrics.OrphanedAccounts.WithLabelValues(o.svm.Cluster).Inc()
+		}
+
+		key := o.key()
+		seen[key] = true
+		firstSeen, ok := c.firstSeen[key]
+		if !ok {
+			firstSeen = now
+			c.firstSeen[key] = now
+			c.log.Info("detected orphan", "orphan", o.String())
+			c.recorder.Warning(ctx, events.ReasonOrphanDetected, events.ActionDetect, "detected orphaned %s", o)
+		}
+
+		if !c.config.Remove || now.Sub(firstSeen) < c.config.GracePeriod.Duration {
+			continue
+		}
+		if !o.owned {
+			c.log.Info("not removing orphan because it is not owned by this seed", "orphan", o.String(), "seed", seed)
+			continue
+		}
+		if !o.svm.Running() {
+			c.log.Info("not removing orphan because its SVM is not running", "orphan", o.String(), "state", o.svm.State)
+			continue
+		}
+		if c.config.DryRun {
+			c.log.Info("dry run, would remove orphan", "orphan", o.String(), "orphanedSince", firstSeen)
+			continue
+		}
+
+		if err := c.remove(ctx, svmManager, o); err != nil {
+			if errors.Is(err, trident.ErrSvmNotEmpty) {
+				metrics.OrphansRemoved.WithLabelValues(o.kind, "skipped").Inc()
+				c.log.Info("not removing orphaned SVM because it still contains volumes", "orphan", o.String())
+				continue
+			}
+			metrics.OrphansRemoved.WithLabelValues(o.kind, "failure").Inc()
+			errs = append(errs, err)
+			continue
+		}
+
+		metrics.OrphansRemoved.WithLabelValues(o.kind, "success").Inc()
+		c.recorder.Normal(ctx, events.ReasonOrphanRemoved, events.ActionDelete, "removed orphaned %s", o)
+		delete(c.firstSeen, key)
+	}
+
+	for key := range c.firstSeen {
+		if !seen[key] {
+			delete(c.firstSeen, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *collector) remove(ctx context.Context, svmManager *trident.SvmManager, o orphan) error {
+	switch o.kind {
+	case orphanKindAccount:
+		return svmManager.DeleteAccount(ctx, o.svm, o.account)
+	case orphanKindSVM:
+		return svmManager.DeleteSVMIfEmpty(ctx, o.svm)
+	default:
+		return fmt.Errorf("unknown orphan kind %q", o.kind)
+	}
+}
+
+// liveShoots returns the account names of all shoots of the seed by the name of their SVM and the name of the seed
+// taken from the Clusters. Any Extension which cannot be resolved, except for ones whose Cluster is already gone, aborts the garbage collection
+// because its SVM and account would be considered orphaned otherwise.
+func (c *collector) liveShoots(ctx context.Context) (map[string]map[string]bool, string, error) {
+	extensions := &extensionsv1alpha1.ExtensionList{}
+	if err := c.client.List(ctx, extensions); err != nil {
+		return nil, "", fmt.Errorf("unable to list extensions: %w", err)
+	}
+
+	var (
+		live = map[string]map[string]bool{}
+		seed string
+	)
+	for i := range extensions.Items {
+		ex := &extensions.Items[i]
+		if ex.Spec.Type != ontap.ControllerType {
+			continue
+		}
+
+		log := c.log.WithValues("namespace", ex.Namespace)
+		resolved, err := ontap.ResolveExtension(ctx, log, c.client, c.decoder, c.naming, ex)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("cluster of extension is gone, ignoring extension")
+				continue
+			}
+			return nil, "", fmt.Errorf("unable to resolve extension %s/%s: %w", ex.Namespace, ex.Name, err)
+		}
+		if resolved.SeedName != "" {
+			seed = resolved.SeedName
+		}
+
+		// the legacy account is removed by the actuator once it is not used anymore
+		accounts, err := trident.ShootAccountNames(ex.Namespace)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// the SVM may still have the name of an earlier version, the secondary SVM is only live while replication is configured
+		// and the DR SVM while disaster recovery is configured
+		svmNames := append([]string{resolved.SVMName}, resolved.SVMAliases...)
+		if resolved.TridentConfig.Replication != nil {
+			svmNames = append(svmNames, trident.ReplicaSVMName(resolved.SVMName))
+		}
+		if resolved.TridentConfig.DisasterRecovery != nil {
+			svmNames = append(svmNames, trident.DRSVMName(resolved.SVMName))
+		}
+		for _, svmName := range svmNames {
+			if live[svmName] == nil {
+				live[svmName] = map[string]bool{}
+			}
+			for _, account := range accounts {
+				live[svmName][account] = true
+			}
+		}
+	}
+
+	return live, seed, nil
+}
+
+// findOrphans returns the accounts and SVMs which do not belong to a live shoot.
+// Objects whose ownership was recorded by another seed are ignored, they are orphans of that seed at most.
+// SVMs with accounts of another seed or without ownership are no orphans.
+// Accounts are returned first, so they are removed before their SVM.
+func findOrphans(svms []*trident.ManagedSVM, live map[string]map[string]bool, seed string) []orphan {
+	var (
+		accounts []orphan
+		orphaned []orphan
+	)
+	for _, svm := range svms {
+		if svm.Ownership != nil && svm.Ownership.Seed != seed {
+			continue
+		}
+		liveAccounts, ok := live[trident.ProjectSVMName(svm.Name)]
+		// project SVMs are shared by the shoots of all seeds, an SVM is kept while accounts of other seeds or
+		// accounts without ownership exist on it
+		if !ok && !hasForeignAccounts(svm, seed) {
+			orphaned = append(orphaned, orphan{kind: orphanKindSVM, svm: svm, owned: svm.OwnedBy(seed)})
+		}
+		for _, account := range svm.Accounts {
+			if account.Ownership != nil && account.Ownership.Seed != seed {
+				continue
+			}
+			if !liveAccounts[account.Name] {
+				accounts = append(accounts, orphan{kind: orphanKindAccount, svm: svm, account: account.Name, owned: account.OwnedBy(seed, svm)})
+			}
+		}
+	}
+	return append(accounts, orphaned...)
+}
+
+// hasForeignAccounts returns whether the SVM has accounts owned by another seed or without recorded ownership.
+func hasForeignAccounts(svm *trident.ManagedSVM, seed string) bool {
+	for _, account := range svm.Accounts {
+		if account.Ownership == nil || account.Ownership.Seed != seed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	mocksvm "github.com/metal-stack/ontap-go/test/mocks/s_vm"
+	mocksecurity "github.com/metal-stack/ontap-go/test/mocks/security"
+	mockstorage "github.com/metal-stack/ontap-go/test/mocks/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+const (
+	liveSVM   = "p0123456789abcdef0123456789abcdef"
+	orphanSVM = "pfedcba9876543210fedcba9876543210"
+	sharedSVM = "p00000000000000000000000000000001"
+	emptySVM  = "p00000000000000000000000000000002"
+)
+
+func TestFindOrphans(t *testing.T) {
+	var (
+		live = map[string]map[string]bool{
+			liveSVM: {"myshoot": true},
+		}
+		seedA = &trident.Ownership{Seed: "seed-a"}
+		seedB = &trident.Ownership{Seed: "seed-b"}
+	)
+	svms := []*trident.ManagedSVM{
+		{Name: liveSVM, Cluster: "a", Ownership: seedA, Accounts: []trident.ManagedAccount{
+			{Name: "myshoot", Ownership: seedA},
+			{Name: "gone-shoot", Ownership: seedA},
+			{Name: "other-seed-shoot", Ownership: seedB},
+		}},
+		{Name: liveSVM + "-mc", Cluster: "b", Ownership: seedA, Accounts: []trident.ManagedAccount{{Name: "myshoot", Ownership: seedA}}},
+		{Name: orphanSVM, Cluster: "a", Accounts: []trident.ManagedAccount{{Name: "other"}}},
+		{Name: orphanSVM, Cluster: "b", Ownership: seedB, Accounts: []trident.ManagedAccount{{Name: "other", Ownership: seedB}}},
+		{Name: sharedSVM, Cluster: "a", Ownership: seedA, Accounts: []trident.ManagedAccount{
+			{Name: "gone-shoot", Ownership: seedA},
+			{Name: "other-seed-shoot", Ownership: seedB},
+		}},
+		{Name: emptySVM, Cluster: "a", Ownership: seedA},
+	}
+
+	orphans := findOrphans(svms, live, "seed-a")
+
+	var (
+		keys  []string
+		owned []bool
+	)
+	for _, o := range orphans {
+		keys = append(keys, o.key())
+		owned = append(owned, o.owned)
+	}
+	// SVMs with accounts of another seed or without recorded ownership are kept, the accounts of this seed are removed
+	assert.Equal(t, []string{
+		"Account/a/" + liveSVM + "/gone-shoot",
+		"Account/a/" + orphanSVM + "/other",
+		"Account/a/" + sharedSVM + "/gone-shoot",
+		"SVM/a/" + emptySVM,
+	}, keys)
+	// objects without recorded ownership are reported but never removed
+	assert.Equal(t, []bool{true, false, true, true}, owned)
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, extensionsv1alpha1.AddToScheme(scheme))
+
+	ownerComment := trident.NewOwnership("seed-a", "shoot--other--gone", orphanSVM).Comment()
+
+	svm := &mocksvm.ClientService{}
+	svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+			SvmResponseInlineRecords: []*models.Svm{{Name: new(orphanSVM), UUID: new("uuid-1"), State: new("running"), Comment: new(ownerComment)}},
+		}}, nil)
+	sec := &mocksecurity.ClientService{}
+	sec.On("AccountCollectionGet", mock.Anything, mock.Anything).
+		Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+			AccountResponseInlineRecords: []*models.Account{{Name: new("gone-shoot"), Comment: new(ownerComment)}},
+		}}, nil)
+	sec.On("AccountDelete", mock.Anything, mock.Anything).Return(&security.AccountDeleteOK{}, nil)
+	st := &mockstorage.ClientService{}
+	st.On("VolumeCollectionGet", mock.Anything, mock.Anything).
+		Return(&storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{
+			VolumeResponseInlineRecords: []*models.Volume{{Name: new("trident_pvc")}},
+		}}, nil)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &collector{
+		log:     logr.Discard(),
+		clients: []*ontapv1.Ontap{{SVM: svm, Security: sec, Storage: st}},
+		client:  fake.NewClientBuilder().WithScheme(scheme).Build(),
+		config: config.GarbageCollectionConfig{
+			GracePeriod: metav1.Duration{Duration: time.Hour},
+			Remove:      true,
+			DryRun:      true,
+		},
+		seed:      "seed-a",
+		now:       func() time.Time { return now },
+		firstSeen: map[string]time.Time{},
+	}
+
+	// orphans are only detected within the grace period
+	require.NoError(t, c.collect(ctx))
+	assert.Len(t, c.firstSeen, 2)
+	sec.AssertNotCalled(t, "AccountDelete", mock.Anything, mock.Anything)
+
+	// dry run does not remove anything after the grace period
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, c.collect(ctx))
+	sec.AssertNotCalled(t, "AccountDelete", mock.Anything, mock.Anything)
+
+	// the account is removed, the svm is kept because it still contains volumes
+	c.config.DryRun = false
+	require.NoError(t, c.collect(ctx))
+	sec.AssertCalled(t, "AccountDelete", mock.MatchedBy(func(p *security.AccountDeleteParams) bool {
+		return p.Name == "gone-shoot" && p.OwnerUUID == "uuid-1"
+	}), mock.Anything)
+	svm.AssertNotCalled(t, "SvmDelete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package ontap
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+// recordAccount stores the username of the shoot account and the truncated username of earlier versions in the
+// provider status of the Extension, the status is only patched if the mapping changed.
+func (a *actuator) recordAccount(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, username string) error {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	account := &ontapv1alpha1.AccountStatus{Username: username}
+	names, err := trident.ShootAccountNames(ex.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(names) > 1 {
+		account.LegacyUsername = names[1]
+	}
+
+	if status.Account != nil && *status.Account == *account {
+		return nil
+	}
+	status.Account = account
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record account in extension status: %w", err)
+	}
+
+	log.Info("Recorded account", "user", account.Username, "legacyUser", account.LegacyUsername)
+	return nil
+}
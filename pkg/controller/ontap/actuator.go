@@ -3,24 +3,29 @@ package ontap
 import (
 	"context"
 	"fmt"
-	"strings"
+	"slices"
+	"sync"
 	"sync/atomic"
 
+	extensionsconfigv1alpha1 "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/extension"
+	"github.com/gardener/gardener/extensions/pkg/util"
 	"github.com/gardener/gardener/extensions/pkg/webhook"
 
 	"github.com/gardener/gardener/extensions/pkg/webhook/shoot"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 
-	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/go-logr/logr"
 	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
 	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
 	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
-	"github.com/metal-stack/metal-lib/pkg/tag"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	k8sevents "k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -37,31 +42,49 @@ import (
 
 type actuator struct {
 	clients            []*ontapv1.Ontap
+	drClient           *ontapv1.Ontap // client of the DR cluster of SVM-DR, nil if SVM-DR is not configured
+	clusterNames       map[*ontapv1.Ontap]string
 	client             client.Client
 	decoder            runtime.Decoder
 	config             config.ControllerConfiguration
 	shootWebhookConfig *atomic.Value
+	recorder           k8sevents.EventRecorder
+	naming             *trident.Naming
+	// passwordPolicies validates the password policy against the password rules of a cluster before accounts are
+	// created on it, an unreachable cluster must not prevent the start of the extension
+	passwordPolicies *trident.PasswordPolicyValidator
 }
 
 const ShootWebhooksResourceName = "extension-ontap-shoot"
 
 // NewActuator returns an actuator responsible for Extension resources.
 func NewActuator(ctx context.Context, mgr manager.Manager, config config.ControllerConfiguration, shootWebhookConfig *atomic.Value) (extension.Actuator, error) {
-	clients, err := createAdminClient(ctx, config)
+	clients, err := CreateAdminClients(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	naming, err := trident.NewNaming(config.Naming)
 	if err != nil {
 		return nil, err
 	}
 
 	return &actuator{
 		clients:            clients,
+		drClient:           SVMDRClient(config, clients),
+		clusterNames:       ClusterNames(config, clients),
 		client:             mgr.GetClient(),
 		decoder:            serializer.NewCodecFactory(mgr.GetScheme()).UniversalDeserializer(),
 		config:             config,
 		shootWebhookConfig: shootWebhookConfig,
+		recorder:           mgr.GetEventRecorder(ControllerName),
+		naming:             naming,
+		passwordPolicies:   trident.NewPasswordPolicyValidator(config.PasswordPolicy, trident.AccountRoleName(config.AccountRole)),
 	}, nil
 }
 
-func createAdminClient(ctx context.Context, config config.ControllerConfiguration) ([]*ontapv1.Ontap, error) {
+// CreateAdminClients returns an instrumented admin client for every configured ONTAP cluster.
+func CreateAdminClients(ctx context.Context, config config.ControllerConfiguration) ([]*ontapv1.Ontap, error) {
 	err := config.Validate()
 	if err != nil {
 		return nil, err
@@ -99,6 +122,8 @@ func createAdminClient(ctx context.Context, config config.ControllerConfiguratio
 
 	for i := range mcClients {
 		client := &mcClients[i]
+		tracing.InstrumentClient(client, config.Clusters[i].Name)
+		metrics.InstrumentClient(client, config.Clusters[i].Name)
 
 		cgparams := cluster.NewClusterGetParamsWithContext(ctx)
 		cgok, err := client.Cluster.ClusterGet(cgparams, nil)
@@ -119,92 +144,271 @@ func createAdminClient(ctx context.Context, config config.ControllerConfiguratio
 	return clients, nil
 }
 
+// ClusterNames returns the configured names of the clusters of the clients, the clients are in the order of the
+// configured clusters.
+func ClusterNames(config config.ControllerConfiguration, clients []*ontapv1.Ontap) map[*ontapv1.Ontap]string {
+	names := map[*ontapv1.Ontap]string{}
+	for i, cluster := range config.Clusters {
+		if i < len(clients) {
+			names[clients[i]] = cluster.Name
+		}
+	}
+	return names
+}
+
 // Reconcile handles extension creation and updates.
 func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.reconcile(ctx, log, ex, false)
+}
+
+// reconcile ensures the SVM, the account and the Trident deployment of the shoot, handOver hands the account of the
+// shoot over to this seed after a migration of the control plane.
+func (a *actuator) reconcile(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, handOver bool) (err error) {
 	shootNamespace := ex.Namespace
 
-	if ex.Spec.ProviderConfig == nil {
-		return fmt.Errorf("provider config is nil")
-	}
+	ctx, span := tracing.Start(tracing.WithAttributes(ctx, tracing.ShootKey.String(shootNamespace)), "Reconcile")
+	defer func() { tracing.End(span, err) }()
 
-	ontapConfig := &ontapv1alpha1.TridentConfig{}
-	if _, _, err := a.decoder.Decode(ex.Spec.ProviderConfig.Raw, nil, ontapConfig); err != nil {
-		return fmt.Errorf("failed to decode provider config: %w", err)
+	resolved, err := ResolveExtension(ctx, log, a.client, a.decoder, a.naming, ex)
+	if err != nil {
+		return err
 	}
+	var (
+		ontapConfig = resolved.TridentConfig
+		projectId   = resolved.SVMName
+		seed        = resolved.OwnerSeed(a.config.SeedName)
+	)
 
-	log.Info("raw provideconfig", "tridentconfig", string(ex.Spec.ProviderConfig.Raw))
+	ctx = tracing.WithAttributes(ctx, tracing.SVMKey.String(projectId))
+	span.SetAttributes(tracing.SVMKey.String(projectId))
 
-	if err := ontapConfig.Validate(); err != nil {
-		return fmt.Errorf("invalid trident config: %w", err)
+	// the SVM is shared by the shoots of the project, its settings are derived from the configs of all of them
+	projectConfigs, err := ProjectConfigs(ctx, log, a.client, a.decoder, a.naming, ex, resolved)
+	if err != nil {
+		return err
 	}
 
-	cluster := &extensionsv1alpha1.Cluster{}
-	if err := a.client.Get(ctx, client.ObjectKey{Name: ex.Namespace}, cluster); err != nil {
-		return fmt.Errorf("failed to get cluster object: %w", err)
+	// the credentials are stored next to the control plane of the shoot and share the lifecycle of the Extension
+	svmSeedSecretNamespace := shootNamespace
+
+	var (
+		shootClient = a.shootClient(ctx, ex)
+		recorder    = a.newEventRecorder(log, ex, shootClient)
+		svmManager  = trident.NewSvmManager(log, a.clients, a.client, recorder).WithClusterNames(a.clusterNames).WithPasswordPolicyValidator(a.passwordPolicies)
+	)
+
+	svmOpts := trident.CreateSVMOptions{
+		ProjectID:                 projectId,
+		ShootNamespace:            shootNamespace,
+		SvmIpaddresses:            ontapConfig.SvmIpaddresses,
+		SvmSeedSecretNamespace:    svmSeedSecretNamespace,
+		Seed:                      seed,
+		Owner:                     ex,
+		PasswordPolicy:            a.config.PasswordPolicy,
+		CertificateAuthentication: a.config.CertificateAuthentication,
+		AccountRole:               trident.AccountRoleName(a.config.AccountRole),
+		Naming:                    a.naming,
+		SVMAliases:                resolved.SVMAliases,
+		Protocols:                 ontapConfig.Protocols,
+		NodeCIDRs:                 resolved.NodeCIDRs,
+		Limits:                    trident.ProjectSVMLimits(projectConfigs, a.config.SVMLimits),
+		SVMProtocols:              trident.ProjectProtocols(projectConfigs),
+		HandOver:                  handOver,
 	}
-	if cluster.Spec.Shoot.Raw == nil {
-		return fmt.Errorf("cluster.spec.shoot.raw is nil")
+	if a.drClient != nil {
+		svmOpts.ExcludedClients = []*ontapv1.Ontap{a.drClient}
 	}
 
-	shoot := &gardencorev1beta1.Shoot{}
-	if _, _, err := a.decoder.Decode(cluster.Spec.Shoot.Raw, nil, shoot); err != nil {
-		log.Error(err, "failed to decode shoot, continuing with partial shoot object")
+	// the DR SVM replaces the SVM of the project after a failover, the backends are re-pointed to its LIFs
+	drStatus, err := a.reconcileFailover(ctx, log, recorder, svmManager, ex, resolved, &svmOpts)
+	if err != nil {
+		return err
 	}
 
-	log.Info("Shoot annotations", "annotations", shoot.Annotations)
-	var projectTag tag.TagMap = shoot.Annotations
-	projectId, ok := projectTag.Value(tag.ClusterProject)
-	if !ok || projectId == "" {
-		return fmt.Errorf("no project ID found in shoot annotations")
+	storageClasses := trident.ShootStorageClasses(ontapConfig, a.config.StorageClasses)
+	qosTiers, err := trident.ShootQoSTiers(storageClasses, a.config.QoSTiers)
+	if err != nil {
+		return err
 	}
 
-	log.Info("Found project ID and shoot namespace", "projectId", projectId, "shootNamespace", shootNamespace)
-	// Project id "-" to be replaced, ontap doesn't like "-"
-	projectId = strings.ReplaceAll(projectId, "-", "")
-	// ontap wants a letter or _ as prefix
-	projectId = "p" + projectId
+	log.Info("Using project ID for SVM creation", "projectId", svmOpts.ProjectID, "shootNamespace", shootNamespace, "namespace", svmSeedSecretNamespace, "managementLifIp", svmOpts.SvmIpaddresses.ManagementLif, "dataLifIps", svmOpts.SvmIpaddresses.DataLifs)
+	if err := a.ensureSvmForProject(ctx, log, svmManager, svmOpts); err != nil {
+		recorder.Warning(ctx, events.ReasonSVMNotReady, events.ActionCreate, "SVM %s is not ready: %v", svmOpts.ProjectID, err)
+		return err
+	}
 
-	svmSeedSecretNamespace := "kube-system"
+	nfs := slices.Contains(ontapConfig.Protocols, ontapv1alpha1.ProtocolNFS)
+	if nfs {
+		if err := svmManager.EnsureExportPolicy(ctx, svmOpts); err != nil {
+			return err
+		}
+	}
 
-	log.Info("Using project ID for SVM creation", "projectId", projectId, "shootNamespace", shootNamespace, "namespace", svmSeedSecretNamespace, "managementLifIp", ontapConfig.SvmIpaddresses.ManagementLif, "dataLifIps", ontapConfig.SvmIpaddresses.DataLifs)
-	if err := a.ensureSvmForProject(ctx, log, ontapConfig.SvmIpaddresses, projectId, shootNamespace, svmSeedSecretNamespace); err != nil {
+	if err := svmManager.EnsureQoSPolicies(ctx, svmOpts, qosTiers); err != nil {
+		return err
+	}
+	if err := svmManager.EnsureSnapshotPolicies(ctx, svmOpts, ontapConfig.Snapshots, qosTiers); err != nil {
 		return err
 	}
 
-	// Use cluster-specific secret name (remove shoot-- prefix for cleaner names)
-	shootNamespaceForSecret := strings.TrimPrefix(shootNamespace, "shoot--")
-	seedsecretName := fmt.Sprintf(trident.ClusterSecretNameFormat, projectId, shootNamespaceForSecret)
-	log.Info("Using credentials from secret in shoot cluster", "secretName", seedsecretName, "namespace", "kube-system")
+	if err := svmManager.EnsureReplication(ctx, svmOpts, ontapConfig.Replication); err != nil {
+		return err
+	}
+	if drStatus != nil && !drStatus.FailedOver {
+		if err := svmManager.EnsureSVMDR(ctx, svmOpts, a.drClient, ontapConfig.DisasterRecovery, a.config.SVMDR); err != nil {
+			return err
+		}
+		drStatus, err = svmManager.SVMDRState(ctx, a.drClient, trident.DRSVMName(projectId))
+		if err != nil {
+			return err
+		}
+	}
 
-	// get existing secret for svm in kube-system namespace
-	existingSecret := &corev1.Secret{}
-	if err := a.client.Get(ctx, client.ObjectKey{Namespace: svmSeedSecretNamespace, Name: seedsecretName}, existingSecret); err != nil {
-		return fmt.Errorf("failed to get secret: %w", err)
+	// the Trident backends are re-pointed to the SVM of the partner site after a MetroCluster switchover
+	mcStatus, err := svmManager.MetroClusterState(ctx, svmOpts)
+	if err != nil {
+		// the backends keep the SVM of the recorded site until the state of the MetroCluster can be read again
+		log.Error(err, "Failed to get MetroCluster state, keeping the recorded site")
+		status, err := a.decodeStatus(ex)
+		if err != nil {
+			return err
+		}
+		mcStatus = status.MetroCluster
 	}
 
-	username, ok := existingSecret.Data["username"]
-	if !ok {
-		return fmt.Errorf("username not found in seed secret, secretname:%s", seedsecretName)
+	rotated, err := a.rotateCredentialsIfDue(ctx, log, svmManager, ex, resolved, svmOpts)
+	if err != nil {
+		return err
 	}
-	password, ok := existingSecret.Data["password"]
-	if !ok {
-		return fmt.Errorf("password not found in seed secret secretname:%s", seedsecretName)
+
+	// accounts of earlier versions with a truncated username are removed once trident uses the new account
+	legacyAccount, err := svmManager.LegacyAccount(ctx, svmOpts)
+	if err != nil {
+		return err
+	}
+
+	seedsecretName := a.naming.SecretName(svmOpts.ProjectID, shootNamespace)
+	log.Info("Using credentials from secret in seed", "secretName", seedsecretName, "namespace", svmSeedSecretNamespace)
+
+	credentials, err := a.seedCredentials(ctx, svmSeedSecretNamespace, seedsecretName)
+	if err != nil {
+		return err
 	}
 
 	svmIpAddresses := ontapv1alpha1.SvmIpaddresses{
-		DataLifs:      ontapConfig.SvmIpaddresses.DataLifs,
-		ManagementLif: ontapConfig.SvmIpaddresses.ManagementLif,
+		DataLifs:      svmOpts.SvmIpaddresses.DataLifs,
+		ManagementLif: svmOpts.SvmIpaddresses.ManagementLif,
 	}
 
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+	storagePrefix, err := trident.StoragePrefix(ctx, a.client, shootNamespace, status.StoragePrefix)
+	if err != nil {
+		return err
+	}
+
+	// the backend config keeps its name when the SVM is renamed by a naming template or fails over to the DR SVM
 	tridentValues := trident.DeployTridentValues{
-		Namespace:      shootNamespace,
-		ProjectId:      projectId,
-		SeedsecretName: &seedsecretName,
-		SvmIpAddresses: svmIpAddresses,
-		Username:       string(username),
-		Password:       string(password),
-	}
-	if err := trident.DeployTrident(ctx, log, a.client, tridentValues); err != nil {
+		Namespace:         shootNamespace,
+		ProjectId:         svmOpts.ProjectID,
+		BackendName:       a.naming.BackendName(projectId),
+		BackendConfigName: trident.BackendConfigName(projectId, resolved.SVMAliases),
+		SeedsecretName:    &seedsecretName,
+		SvmIpAddresses:    svmIpAddresses,
+		StoragePrefix:     storagePrefix,
+		Protocols:         ontapConfig.Protocols,
+		StorageClasses:    storageClasses,
+		QoSTiers:          qosTiers,
+		Snapshots:         ontapConfig.Snapshots,
+		KubernetesVersion: resolved.Shoot.Spec.Kubernetes.Version,
+		Username:          credentials.username,
+		Password:          credentials.password,
+	}
+	if nfs {
+		tridentValues.ExportPolicy = trident.ExportPolicyName(shootNamespace)
+	}
+	if mcStatus != nil && mcStatus.SwitchedOver {
+		tridentValues.SVMName = mcStatus.SVM
+	}
+	if a.config.CertificateAuthentication != nil {
+		tridentValues.Password = ""
+		tridentValues.ClientCertificate = credentials.clientCertificate
+		tridentValues.ClientPrivateKey = credentials.clientPrivateKey
+	}
+	if replication := ontapConfig.Replication; replication != nil {
+		replicaSecretName := a.naming.SecretName(trident.ReplicaSVMName(projectId), shootNamespace)
+		replicaCredentials, err := a.seedCredentials(ctx, svmSeedSecretNamespace, replicaSecretName)
+		if err != nil {
+			return err
+		}
+		tridentValues.Replica = &trident.ReplicaValues{
+			SeedsecretName: replicaSecretName,
+			SvmIpAddresses: replication.SvmIpaddresses,
+			StorageClass:   replication.StorageClass,
+			Username:       replicaCredentials.username,
+		}
+		if a.config.CertificateAuthentication != nil {
+			tridentValues.Replica.ClientCertificate = replicaCredentials.clientCertificate
+			tridentValues.Replica.ClientPrivateKey = replicaCredentials.clientPrivateKey
+		} else {
+			tridentValues.Replica.Password = replicaCredentials.password
+		}
+	}
+	// the backends are online before the rotation, only operations of Trident after the deployment of the rotated
+	// credentials prove that they were accepted
+	var backendVersions map[string]string
+	if rotated || legacyAccount != "" {
+		backendVersions, err = a.backendConfigVersions(ctx, shootClient, trident.BackendConfigNames(tridentValues))
+		if err != nil {
+			return err
+		}
+	}
+	deployCtx, deploySpan := tracing.Start(ctx, "DeployTrident")
+	deployed, err := trident.DeployTrident(deployCtx, log, a.client, tridentValues)
+	tracing.End(deploySpan, err)
+	if err != nil {
+		recorder.Warning(ctx, events.ReasonTridentFailed, events.ActionDeploy, "failed to deploy trident: %v", err)
+		return err
+	}
+	if deployed {
+		recorder.Normal(ctx, events.ReasonTridentDeployed, events.ActionDeploy, "trident backend for SVM %s deployed", svmOpts.ProjectID)
+	}
+	if err := a.recordStoragePrefix(ctx, log, ex, storagePrefix); err != nil {
+		return err
+	}
+
+	if rotated || legacyAccount != "" {
+		if err := a.waitForBackendOnline(ctx, shootClient, backendVersions, trident.BackendConfigNames(tridentValues)); err != nil {
+			recorder.Warning(ctx, events.ReasonTridentFailed, events.ActionRotate, "trident backend did not accept rotated credentials: %v", err)
+			return err
+		}
+	}
+	if legacyAccount != "" {
+		if err := svmManager.RemoveLegacyAccount(ctx, svmOpts, legacyAccount); err != nil {
+			return err
+		}
+	}
+	if err := a.recordAccount(ctx, log, ex, credentials.username); err != nil {
+		return err
+	}
+	usage, err := svmManager.SVMUsage(ctx, svmOpts)
+	if err != nil {
+		return err
+	}
+	shootUsage, err := svmManager.ShootUsage(ctx, svmOpts, storagePrefix)
+	if err != nil {
+		return err
+	}
+	if err := a.recordUsage(ctx, log, ex, usage, shootUsage); err != nil {
+		return err
+	}
+	svmManager.RecordClusterMetrics(ctx)
+	if err := a.recordDisasterRecovery(ctx, log, recorder, ex, drStatus); err != nil {
+		return err
+	}
+	if err := a.recordMetroCluster(ctx, log, recorder, ex, mcStatus); err != nil {
 		return err
 	}
 
@@ -223,8 +427,16 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, ex *extension
 }
 
 // Delete the Extension resource.
-func (a *actuator) Delete(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) error {
-	return trident.DeleteManagedResources(ctx, log, a.client, ex)
+func (a *actuator) Delete(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) (err error) {
+	ctx, span := tracing.Start(tracing.WithAttributes(ctx, tracing.ShootKey.String(ex.Namespace)), "Delete")
+	defer func() { tracing.End(span, err) }()
+
+	if err := trident.DeleteManagedResources(ctx, log, a.client, ex); err != nil {
+		return err
+	}
+
+	// the credentials secret is garbage collected together with the Extension
+	return trident.ReleaseSeedSecrets(ctx, a.client, ex.Namespace)
 }
 
 // ForceDelete the Extension resource
@@ -232,36 +444,96 @@ func (a *actuator) ForceDelete(_ context.Context, _ logr.Logger, _ *extensionsv1
 	return nil
 }
 
-// Restore the Extension resource.
+// Restore the Extension resource. The account of the shoot is still owned by the seed the control plane was migrated
+// from and is handed over to this seed.
 func (a *actuator) Restore(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) error {
-	return a.Reconcile(ctx, log, ex)
+	return a.reconcile(ctx, log, ex, true)
 }
 
 // Migrate the Extension resource.
 func (a *actuator) Migrate(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension) error {
-	return nil
+	// the shoot namespace is deleted on the source seed after the migration
+	return trident.ReleaseSeedSecrets(ctx, a.client, ex.Namespace)
 }
 
 // ensureSvmForProject ensures a complete SVM exists with all required components
-func (a *actuator) ensureSvmForProject(ctx context.Context, log logr.Logger, SvmIpaddresses ontapv1alpha1.SvmIpaddresses, projectId string, shootNamespace string, svmSeedSecretNamespace string) error {
-	svmManager := trident.NewSvmManager(log, a.clients, a.client)
-
-	svmOpts := trident.CreateSVMOptions{
-		ProjectID:              projectId,
-		ShootNamespace:         shootNamespace,
-		SvmIpaddresses:         SvmIpaddresses,
-		SvmSeedSecretNamespace: svmSeedSecretNamespace,
-	}
+func (a *actuator) ensureSvmForProject(ctx context.Context, log logr.Logger, svmManager *trident.SvmManager, svmOpts trident.CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureSVM")
+	defer func() { tracing.End(span, err) }()
 
 	if err := svmManager.EnsureCompleteSVM(ctx, svmOpts); err != nil {
-		return fmt.Errorf("failed to ensure complete SVM for project %s shoot namespace %s: %w", projectId, shootNamespace, err)
+		return fmt.Errorf("failed to ensure complete SVM for project %s shoot namespace %s: %w", svmOpts.ProjectID, svmOpts.ShootNamespace, err)
 	}
 
-	log.Info("SVM state ensured successfully", "projectId", projectId, "shootNamespace", shootNamespace)
+	log.Info("SVM state ensured successfully", "projectId", svmOpts.ProjectID, "shootNamespace", svmOpts.ShootNamespace)
 	return nil
 }
 
-func (a *actuator) reconcileShootWebhookConfig(ctx context.Context, cluster *extensionscontroller.Cluster) error {
+// svmCredentials are the credentials of an SVM account stored in a secret in the seed.
+type svmCredentials struct {
+	username          string
+	password          string
+	clientCertificate string
+	clientPrivateKey  string
+}
+
+// seedCredentials reads the credentials of an SVM account from the given secret in the seed.
+func (a *actuator) seedCredentials(ctx context.Context, namespace, name string) (*svmCredentials, error) {
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	username, ok := secret.Data["username"]
+	if !ok {
+		return nil, fmt.Errorf("username not found in seed secret, secretname:%s", name)
+	}
+	password, ok := secret.Data["password"]
+	if !ok && a.config.CertificateAuthentication == nil {
+		return nil, fmt.Errorf("password not found in seed secret secretname:%s", name)
+	}
+	clientCertificate, ok := secret.Data[trident.ClientCertificateKey]
+	if !ok && a.config.CertificateAuthentication != nil {
+		return nil, fmt.Errorf("client certificate not found in seed secret secretname:%s", name)
+	}
+
+	return &svmCredentials{
+		username:          string(username),
+		password:          string(password),
+		clientCertificate: string(clientCertificate),
+		clientPrivateKey:  string(secret.Data[trident.ClientPrivateKeyKey]),
+	}, nil
+}
+
+// shootClient returns a function which creates the client of the shoot of the given extension on its first call,
+// later calls of the same reconcile return the same client.
+func (a *actuator) shootClient(ctx context.Context, ex *extensionsv1alpha1.Extension) func() (client.Client, error) {
+	return sync.OnceValues(func() (client.Client, error) {
+		_, shootClient, err := util.NewClientForShoot(ctx, a.client, ex.Namespace, client.Options{}, extensionsconfigv1alpha1.RESTOptions{})
+		return shootClient, err
+	})
+}
+
+// newEventRecorder returns a recorder for lifecycle events of the given extension.
+// Events are only mirrored into the shoot if enabled and the shoot is reachable.
+func (a *actuator) newEventRecorder(log logr.Logger, ex *extensionsv1alpha1.Extension, getShootClient func() (client.Client, error)) *events.Recorder {
+	if !a.config.ShootEvents {
+		return events.NewRecorder(log, a.recorder, ex, nil)
+	}
+
+	shootClient, err := getShootClient()
+	if err != nil {
+		log.Error(err, "unable to create shoot client, events are not mirrored into the shoot")
+		return events.NewRecorder(log, a.recorder, ex, nil)
+	}
+
+	return events.NewRecorder(log, a.recorder, ex, shootClient)
+}
+
+func (a *actuator) reconcileShootWebhookConfig(ctx context.Context, cluster *extensionscontroller.Cluster) (err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileShootWebhookConfig")
+	defer func() { tracing.End(span, err) }()
+
 	value := a.shootWebhookConfig.Load()
 	webhookConfig, ok := value.(*webhook.Configs)
 	if !ok {
@@ -14,8 +14,8 @@ import (
 )
 
 const (
-	// controllerType is the type of Extension resource.
-	controllerType = "ontap"
+	// ControllerType is the type of Extension resource.
+	ControllerType = "ontap"
 	// ControllerName is the name of the registry cache service controller.
 	ControllerName = "ontap_controller"
 	// finalizerSuffix is the finalizer suffix for the registry cache service controller.
@@ -61,7 +61,7 @@ func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddO
 		FinalizerSuffix:   finalizerSuffix,
 		Resync:            0,
 		Predicates:        extension.DefaultPredicates(ctx, mgr, DefaultAddOptions.IgnoreOperationAnnotation),
-		Type:              controllerType,
+		Type:              ControllerType,
 		ExtensionClasses:  []extensionsv1alpha1.ExtensionClass{opts.ExtensionClass},
 	})
 }
@@ -0,0 +1,165 @@
+package ontap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+// AnnotationFailover requests a failover of the SVM of a shoot to its DR SVM. Every new value of the annotation
+// triggers at most one failover, the failover is refused if the SVM-DR relationship is not snapmirrored.
+const AnnotationFailover = "ontap.metal-stack.io/failover"
+
+// SVMDRClient returns the client of the DR cluster of SVM-DR, it is nil if SVM-DR is not configured. The clients are
+// in the order of the configured clusters.
+func SVMDRClient(config config.ControllerConfiguration, clients []*ontapv1.Ontap) *ontapv1.Ontap {
+	if config.SVMDR == nil {
+		return nil
+	}
+	for i, cluster := range config.Clusters {
+		if cluster.Name == config.SVMDR.Cluster && i < len(clients) {
+			return clients[i]
+		}
+	}
+	return nil
+}
+
+// FailedOver returns whether the SVM of the Extension failed over to its DR SVM according to its provider status.
+func FailedOver(decoder runtime.Decoder, ex *extensionsv1alpha1.Extension) bool {
+	if ex.Status.ProviderStatus == nil || ex.Status.ProviderStatus.Raw == nil {
+		return false
+	}
+	status := &ontapv1alpha1.TridentStatus{}
+	if _, _, err := decoder.Decode(ex.Status.ProviderStatus.Raw, nil, status); err != nil {
+		return false
+	}
+	return status.DisasterRecovery != nil && status.DisasterRecovery.FailedOver
+}
+
+// reconcileFailover executes a requested failover of the SVM to the DR SVM and returns the state of the SVM-DR
+// relationship, it is nil if the shoot does not configure disaster recovery. The DR SVM replaces the SVM in the
+// options once the SVM failed over.
+func (a *actuator) reconcileFailover(ctx context.Context, log logr.Logger, recorder *events.Recorder, svmManager *trident.SvmManager, ex *extensionsv1alpha1.Extension, resolved *ResolvedExtension, opts *trident.CreateSVMOptions) (*ontapv1alpha1.DisasterRecoveryStatus, error) {
+	dr := resolved.TridentConfig.DisasterRecovery
+	if dr == nil {
+		return nil, nil
+	}
+	if a.drClient == nil {
+		return nil, fmt.Errorf("disaster recovery is configured, but svm-dr is not enabled for the seed")
+	}
+
+	drName := trident.DRSVMName(opts.ProjectID)
+
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return nil, err
+	}
+	if requested := failoverRequest(resolved.Shoot, status.DisasterRecovery); requested != "" {
+		log.Info("Failing over", "svm", opts.ProjectID, "drSVM", drName, "request", requested)
+		err := svmManager.FailoverSVMDR(ctx, a.drClient, drName)
+		switch {
+		case errors.Is(err, trident.ErrFailoverRefused):
+			recorder.Warning(ctx, events.ReasonFailoverRefused, events.ActionFailover, "failover requested with annotation %s=%s refused: %v", AnnotationFailover, requested, err)
+		case err != nil:
+			return nil, err
+		}
+		if err := a.recordFailover(ctx, log, ex, status, drName, requested, err == nil); err != nil {
+			return nil, err
+		}
+	}
+
+	drStatus, err := svmManager.SVMDRState(ctx, a.drClient, drName)
+	if err != nil {
+		return nil, err
+	}
+	if drStatus.FailedOver {
+		log.Info("SVM failed over, using DR SVM", "svm", opts.ProjectID, "drSVM", drName)
+		*opts = trident.DRSVMOptions(*opts, dr)
+	}
+	return drStatus, nil
+}
+
+// failoverRequest returns the value of the failover annotation of the shoot, it is empty if no failover is requested
+// or the request was handled already.
+func failoverRequest(shoot *gardencorev1beta1.Shoot, status *ontapv1alpha1.DisasterRecoveryStatus) string {
+	requested := shoot.Annotations[AnnotationFailover]
+	if requested == "" || (status != nil && requested == status.LastFailoverRequest) {
+		return ""
+	}
+	return requested
+}
+
+// recordFailover stores the handled failover request and the time of an executed failover in the provider status of
+// the Extension. Refused requests are recorded as well, a failover has to be requested with a new value again.
+func (a *actuator) recordFailover(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, status *ontapv1alpha1.TridentStatus, drName, requested string, failedOver bool) error {
+	if status.DisasterRecovery == nil {
+		status.DisasterRecovery = &ontapv1alpha1.DisasterRecoveryStatus{SVM: drName}
+	}
+	status.DisasterRecovery.LastFailoverRequest = requested
+	if failedOver {
+		status.DisasterRecovery.LastFailoverTime = &metav1.Time{Time: time.Now()}
+	}
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record failover in extension status: %w", err)
+	}
+
+	log.Info("Recorded failover request", "request", requested, "failedOver", failedOver)
+	return nil
+}
+
+// recordDisasterRecovery stores the state of the SVM-DR relationship in the provider status of the Extension, the
+// status is only patched if the state changed. A warning is emitted when the relationship becomes unhealthy.
+func (a *actuator) recordDisasterRecovery(ctx context.Context, log logr.Logger, recorder *events.Recorder, ex *extensionsv1alpha1.Extension, drStatus *ontapv1alpha1.DisasterRecoveryStatus) error {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	previous := status.DisasterRecovery
+	if drStatus != nil && previous != nil {
+		drStatus.LastFailoverTime = previous.LastFailoverTime
+		drStatus.LastFailoverRequest = previous.LastFailoverRequest
+	}
+	if !disasterRecoveryChanged(previous, drStatus) {
+		return nil
+	}
+	if drStatus != nil && drStatus.State != "" && !drStatus.Healthy && (previous == nil || previous.Healthy) {
+		recorder.Warning(ctx, events.ReasonSVMDRUnhealthy, events.ActionDetect, "SVM-DR relationship to SVM %s is unhealthy: %s", drStatus.SVM, drStatus.UnhealthyReason)
+	}
+	status.DisasterRecovery = drStatus
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record disaster recovery in extension status: %w", err)
+	}
+
+	if drStatus != nil {
+		log.Info("Recorded SVM-DR relationship", "drSVM", drStatus.SVM, "state", drStatus.State, "healthy", drStatus.Healthy, "failedOver", drStatus.FailedOver)
+	}
+	return nil
+}
+
+// disasterRecoveryChanged returns whether the state of the SVM-DR relationship changed. The lag time grows with every
+// poll between two transfers and is only updated together with the other fields, it is recorded in the metrics.
+func disasterRecoveryChanged(previous, current *ontapv1alpha1.DisasterRecoveryStatus) bool {
+	if previous != nil && current != nil {
+		withoutLag := *current
+		withoutLag.LagTime = previous.LagTime
+		current = &withoutLag
+	}
+	return !equality.Semantic.DeepEqual(previous, current)
+}
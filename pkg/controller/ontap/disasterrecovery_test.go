@@ -0,0 +1,65 @@
+package ontap
+
+import (
+	"testing"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestFailoverRequest(t *testing.T) {
+	shoot := func(annotation string) *gardencorev1beta1.Shoot {
+		s := &gardencorev1beta1.Shoot{}
+		if annotation != "" {
+			s.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{AnnotationFailover: annotation}}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		shoot  *gardencorev1beta1.Shoot
+		status *ontapv1alpha1.DisasterRecoveryStatus
+		want   string
+	}{
+		{name: "not requested", shoot: shoot("")},
+		{name: "requested without status", shoot: shoot("1"), want: "1"},
+		{name: "request already handled", shoot: shoot("1"), status: &ontapv1alpha1.DisasterRecoveryStatus{LastFailoverRequest: "1"}},
+		{name: "new request", shoot: shoot("2"), status: &ontapv1alpha1.DisasterRecoveryStatus{LastFailoverRequest: "1"}, want: "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, failoverRequest(tt.shoot, tt.status))
+		})
+	}
+}
+
+func TestDisasterRecoveryChanged(t *testing.T) {
+	var (
+		healthy   = &ontapv1alpha1.DisasterRecoveryStatus{SVM: "p1-dr", State: "snapmirrored", Healthy: true, LagTime: "PT5M"}
+		lagged    = &ontapv1alpha1.DisasterRecoveryStatus{SVM: "p1-dr", State: "snapmirrored", Healthy: true, LagTime: "PT10M"}
+		unhealthy = &ontapv1alpha1.DisasterRecoveryStatus{SVM: "p1-dr", State: "snapmirrored", LagTime: "PT10M"}
+	)
+
+	tests := []struct {
+		name     string
+		previous *ontapv1alpha1.DisasterRecoveryStatus
+		current  *ontapv1alpha1.DisasterRecoveryStatus
+		want     bool
+	}{
+		{name: "no SVM-DR"},
+		{name: "first reconcile", current: healthy, want: true},
+		{name: "unchanged", previous: healthy, current: healthy},
+		{name: "only the lag time changed", previous: healthy, current: lagged},
+		{name: "became unhealthy", previous: healthy, current: unhealthy, want: true},
+		{name: "SVM-DR removed", previous: healthy, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, disasterRecoveryChanged(tt.previous, tt.current))
+		})
+	}
+}
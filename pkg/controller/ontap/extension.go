@@ -0,0 +1,151 @@
+package ontap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+// ResolvedExtension bundles the configuration an Extension is reconciled with.
+type ResolvedExtension struct {
+	// TridentConfig is the decoded and validated provider config of the Extension
+	TridentConfig *ontapv1alpha1.TridentConfig
+	// Shoot is the shoot the Extension belongs to, it may be partial if decoding failed
+	Shoot *gardencorev1beta1.Shoot
+	// SVMName is the name of the project SVM
+	SVMName string
+	// SVMAliases are the names of the project SVM of earlier versions which differ from SVMName
+	SVMAliases []string
+	// SeedName is the name of the seed the shoot is scheduled on, it is empty if unknown
+	SeedName string
+	// NodeCIDRs are the node networks of the shoot
+	NodeCIDRs []string
+}
+
+// ResolveExtension decodes the provider config of the given Extension and derives the SVM name from the project of its shoot
+// with the given naming.
+func ResolveExtension(ctx context.Context, log logr.Logger, c client.Client, decoder runtime.Decoder, naming *trident.Naming, ex *extensionsv1alpha1.Extension) (*ResolvedExtension, error) {
+	shootNamespace := ex.Namespace
+
+	if ex.Spec.ProviderConfig == nil {
+		return nil, fmt.Errorf("provider config is nil")
+	}
+
+	ontapConfig := &ontapv1alpha1.TridentConfig{}
+	if _, _, err := decoder.Decode(ex.Spec.ProviderConfig.Raw, nil, ontapConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode provider config: %w", err)
+	}
+
+	log.Info("raw provideconfig", "tridentconfig", string(ex.Spec.ProviderConfig.Raw))
+
+	ontapConfig.ConfigureDefaults()
+	if err := ontapConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid trident config: %w", err)
+	}
+
+	cluster := &extensionsv1alpha1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Name: ex.Namespace}, cluster); err != nil {
+		return nil, fmt.Errorf("failed to get cluster object: %w", err)
+	}
+	if cluster.Spec.Shoot.Raw == nil {
+		return nil, fmt.Errorf("cluster.spec.shoot.raw is nil")
+	}
+
+	shoot := &gardencorev1beta1.Shoot{}
+	if _, _, err := decoder.Decode(cluster.Spec.Shoot.Raw, nil, shoot); err != nil {
+		log.Error(err, "failed to decode shoot, continuing with partial shoot object")
+	}
+
+	if err := trident.ValidateSnapshotFeatures(ontapConfig.Snapshots, shoot.Spec.Kubernetes.Version); err != nil {
+		return nil, fmt.Errorf("invalid trident config: %w", err)
+	}
+
+	var seedName string
+	if cluster.Spec.Seed.Raw != nil {
+		seed := &gardencorev1beta1.Seed{}
+		if _, _, err := decoder.Decode(cluster.Spec.Seed.Raw, nil, seed); err != nil {
+			log.Error(err, "failed to decode seed, continuing without seed name")
+		}
+		seedName = seed.Name
+	}
+
+	log.Info("Shoot annotations", "annotations", shoot.Annotations)
+	var projectTag tag.TagMap = shoot.Annotations
+	projectId, ok := projectTag.Value(tag.ClusterProject)
+	if !ok || projectId == "" {
+		return nil, fmt.Errorf("no project ID found in shoot annotations")
+	}
+
+	log.Info("Found project ID and shoot namespace", "projectId", projectId, "shootNamespace", shootNamespace)
+	// Project id "-" to be replaced, ontap doesn't like "-"
+	projectId = strings.ReplaceAll(projectId, "-", "")
+
+	return &ResolvedExtension{
+		TridentConfig: ontapConfig,
+		Shoot:         shoot,
+		SVMName:       naming.SVMName(projectId),
+		SVMAliases:    naming.SVMAliases(projectId),
+		SeedName:      seedName,
+		NodeCIDRs:     nodeCIDRs(shoot),
+	}, nil
+}
+
+// ProjectConfigs returns the provider configs of the ontap Extensions of the seed whose shoots share the SVM of the
+// given resolved Extension, its own config is the first one. Extensions in deletion and Extensions which cannot be
+// resolved are skipped.
+func ProjectConfigs(ctx context.Context, log logr.Logger, c client.Client, decoder runtime.Decoder, naming *trident.Naming, ex *extensionsv1alpha1.Extension, resolved *ResolvedExtension) ([]*ontapv1alpha1.TridentConfig, error) {
+	extensions := &extensionsv1alpha1.ExtensionList{}
+	if err := c.List(ctx, extensions); err != nil {
+		return nil, fmt.Errorf("unable to list extensions: %w", err)
+	}
+
+	configs := []*ontapv1alpha1.TridentConfig{resolved.TridentConfig}
+	for i := range extensions.Items {
+		other := &extensions.Items[i]
+		if other.Spec.Type != ControllerType || other.Namespace == ex.Namespace || other.DeletionTimestamp != nil {
+			continue
+		}
+		otherResolved, err := ResolveExtension(ctx, logr.Discard(), c, decoder, naming, other)
+		if err != nil {
+			log.Error(err, "skipping extension which cannot be resolved", "extensionNamespace", other.Namespace)
+			continue
+		}
+		if otherResolved.SVMName == resolved.SVMName {
+			configs = append(configs, otherResolved.TridentConfig)
+		}
+	}
+	return configs, nil
+}
+
+// nodeCIDRs returns the node networks of the shoot, dual-stack shoots list one network per ip family.
+func nodeCIDRs(shoot *gardencorev1beta1.Shoot) []string {
+	if shoot.Spec.Networking == nil || shoot.Spec.Networking.Nodes == nil {
+		return nil
+	}
+	var cidrs []string
+	for cidr := range strings.SplitSeq(*shoot.Spec.Networking.Nodes, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// OwnerSeed returns the seed recorded as owner of the ONTAP objects, the configured seed name takes precedence
+// over the seed of the Cluster.
+func (r *ResolvedExtension) OwnerSeed(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return r.SeedName
+}
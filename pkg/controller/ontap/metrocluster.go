@@ -0,0 +1,60 @@
+package ontap
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+// recordMetroCluster stores the site of the MetroCluster the SVM is active on in the provider status of the Extension,
+// the status is only patched if it changed. An event is emitted when the SVM switched over or back.
+func (a *actuator) recordMetroCluster(ctx context.Context, log logr.Logger, recorder *events.Recorder, ex *extensionsv1alpha1.Extension, mcStatus *ontapv1alpha1.MetroClusterStatus) error {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	previous := status.MetroCluster
+	if equality.Semantic.DeepEqual(previous, mcStatus) {
+		return nil
+	}
+	switch metroClusterTransition(previous, mcStatus) {
+	case events.ReasonSwitchover:
+		recorder.Warning(ctx, events.ReasonSwitchover, events.ActionDetect, "SVM switched over to SVM %s on site %s", mcStatus.SVM, mcStatus.ActiveSite)
+	case events.ReasonSwitchback:
+		recorder.Normal(ctx, events.ReasonSwitchback, events.ActionDetect, "SVM switched back to SVM %s on site %s", mcStatus.SVM, mcStatus.ActiveSite)
+	}
+	status.MetroCluster = mcStatus
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record metrocluster site in extension status: %w", err)
+	}
+
+	if mcStatus != nil {
+		log.Info("Recorded MetroCluster site", "activeSite", mcStatus.ActiveSite, "svm", mcStatus.SVM, "mode", mcStatus.Mode, "switchedOver", mcStatus.SwitchedOver)
+	}
+	return nil
+}
+
+// metroClusterTransition returns the reason of the event of a switchover or switchback of the SVM between the
+// previous and the current status, it is empty if the SVM stayed on its site.
+func metroClusterTransition(previous, current *ontapv1alpha1.MetroClusterStatus) string {
+	var (
+		wasSwitchedOver = previous != nil && previous.SwitchedOver
+		isSwitchedOver  = current != nil && current.SwitchedOver
+	)
+	switch {
+	case !wasSwitchedOver && isSwitchedOver:
+		return events.ReasonSwitchover
+	case wasSwitchedOver && current != nil && !isSwitchedOver:
+		return events.ReasonSwitchback
+	default:
+		return ""
+	}
+}
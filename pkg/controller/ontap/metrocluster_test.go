@@ -0,0 +1,37 @@
+package ontap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+func TestMetroClusterTransition(t *testing.T) {
+	var (
+		primary      = &ontapv1alpha1.MetroClusterStatus{ActiveSite: "cluster-a", SVM: "p1", Mode: "normal"}
+		switchedOver = &ontapv1alpha1.MetroClusterStatus{ActiveSite: "cluster-b", SVM: "p1-mc", Mode: "switchover", SwitchedOver: true}
+	)
+
+	tests := []struct {
+		name     string
+		previous *ontapv1alpha1.MetroClusterStatus
+		current  *ontapv1alpha1.MetroClusterStatus
+		want     string
+	}{
+		{name: "no metrocluster"},
+		{name: "first reconcile on the primary site", current: primary},
+		{name: "first reconcile after a switchover", current: switchedOver, want: events.ReasonSwitchover},
+		{name: "switchover", previous: primary, current: switchedOver, want: events.ReasonSwitchover},
+		{name: "still switched over", previous: switchedOver, current: switchedOver},
+		{name: "switchback", previous: switchedOver, current: primary, want: events.ReasonSwitchback},
+		{name: "metrocluster removed", previous: switchedOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, metroClusterTransition(tt.previous, tt.current))
+		})
+	}
+}
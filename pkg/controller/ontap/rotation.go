@@ -0,0 +1,160 @@
+package ontap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/timewindow"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/trident"
+)
+
+const (
+	// AnnotationRotateCredentials requests a rotation of the SVM account password of a shoot.
+	// Every new value of the annotation triggers exactly one rotation, independent of the maintenance window.
+	AnnotationRotateCredentials = "ontap.metal-stack.io/rotate-credentials"
+
+	// defaultBackendTimeout is used for requested rotations if no credentials rotation is configured.
+	defaultBackendTimeout = 5 * time.Minute
+)
+
+// rotateCredentialsIfDue rotates the password of the shoot account if a rotation is requested, scheduled
+// or was interrupted before, it returns whether the password was rotated.
+func (a *actuator) rotateCredentialsIfDue(ctx context.Context, log logr.Logger, svmManager *trident.SvmManager, ex *extensionsv1alpha1.Extension, resolved *ResolvedExtension, opts trident.CreateSVMOptions) (bool, error) {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		now    = time.Now()
+		reason = rotationReason(a.config.CredentialsRotation, resolved.Shoot, status.Credentials, now)
+	)
+	if reason == "" {
+		pending, err := svmManager.CredentialsRotationPending(ctx, opts)
+		if err != nil || !pending {
+			return false, err
+		}
+		reason = "previous rotation was interrupted"
+	}
+
+	log.Info("Rotating credentials", "svm", opts.ProjectID, "reason", reason)
+	if err := svmManager.RotateCredentials(ctx, opts); err != nil {
+		return false, fmt.Errorf("failed to rotate credentials: %w", err)
+	}
+
+	return true, a.recordCredentialsRotation(ctx, log, ex, status, resolved.Shoot, now)
+}
+
+// backendConfigVersions returns the resource versions of the Trident backend configs in the shoot before rotated
+// credentials are deployed.
+func (a *actuator) backendConfigVersions(ctx context.Context, getShootClient func() (client.Client, error), backendConfigNames []string) (map[string]string, error) {
+	shootClient, err := getShootClient()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create shoot client: %w", err)
+	}
+	return trident.BackendConfigVersions(ctx, shootClient, backendConfigNames)
+}
+
+// waitForBackendOnline waits until the Trident backends in the shoot accepted the rotated credentials, versions are
+// the resource versions of the backend configs before the rotated credentials were deployed.
+func (a *actuator) waitForBackendOnline(ctx context.Context, getShootClient func() (client.Client, error), versions map[string]string, backendConfigNames []string) error {
+	timeout := defaultBackendTimeout
+	if a.config.CredentialsRotation != nil {
+		timeout = a.config.CredentialsRotation.BackendTimeout.Duration
+	}
+
+	shootClient, err := getShootClient()
+	if err != nil {
+		return fmt.Errorf("unable to create shoot client: %w", err)
+	}
+	for _, name := range backendConfigNames {
+		if err := trident.WaitForBackendOnline(ctx, shootClient, name, versions[name], timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotationReason returns why the credentials of the shoot have to be rotated now, it is empty if no rotation is due.
+// Requested rotations are executed immediately, scheduled rotations only inside the maintenance window of the shoot.
+func rotationReason(rotation *config.CredentialsRotationConfig, shoot *gardencorev1beta1.Shoot, status *ontapv1alpha1.CredentialsStatus, now time.Time) string {
+	if requested := shoot.Annotations[AnnotationRotateCredentials]; requested != "" && requested != status.LastRotationRequest {
+		return fmt.Sprintf("requested with annotation %s=%s", AnnotationRotateCredentials, requested)
+	}
+
+	if rotation == nil {
+		return ""
+	}
+	if status.LastRotationTime != nil && now.Sub(status.LastRotationTime.Time) < rotation.Interval.Duration {
+		return ""
+	}
+	if !inMaintenanceWindow(shoot, now) {
+		return ""
+	}
+	return fmt.Sprintf("scheduled after %s", rotation.Interval.Duration)
+}
+
+// inMaintenanceWindow returns whether now is inside the maintenance window of the shoot.
+// Shoots without a valid maintenance window are always in maintenance.
+func inMaintenanceWindow(shoot *gardencorev1beta1.Shoot, now time.Time) bool {
+	if shoot.Spec.Maintenance == nil || shoot.Spec.Maintenance.TimeWindow == nil {
+		return true
+	}
+	window, err := timewindow.ParseMaintenanceTimeWindow(shoot.Spec.Maintenance.TimeWindow.Begin, shoot.Spec.Maintenance.TimeWindow.End)
+	if err != nil {
+		return true
+	}
+	return window.Contains(now)
+}
+
+// decodeStatus returns the provider status of the Extension, it is empty if none was recorded yet.
+func (a *actuator) decodeStatus(ex *extensionsv1alpha1.Extension) (*ontapv1alpha1.TridentStatus, error) {
+	status := &ontapv1alpha1.TridentStatus{}
+	if ex.Status.ProviderStatus != nil && ex.Status.ProviderStatus.Raw != nil {
+		if _, _, err := a.decoder.Decode(ex.Status.ProviderStatus.Raw, nil, status); err != nil {
+			return nil, fmt.Errorf("failed to decode provider status: %w", err)
+		}
+	}
+	if status.Credentials == nil {
+		status.Credentials = &ontapv1alpha1.CredentialsStatus{}
+	}
+	return status, nil
+}
+
+// recordCredentialsRotation stores the time and the handled request of a rotation in the provider status of the Extension.
+func (a *actuator) recordCredentialsRotation(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, status *ontapv1alpha1.TridentStatus, shoot *gardencorev1beta1.Shoot, now time.Time) error {
+	status.Credentials.LastRotationTime = &metav1.Time{Time: now}
+	status.Credentials.LastRotationRequest = shoot.Annotations[AnnotationRotateCredentials]
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record credentials rotation in extension status: %w", err)
+	}
+
+	log.Info("Recorded credentials rotation", "lastRotationTime", now)
+	return nil
+}
+
+// patchStatus stores the provider status in the Extension.
+func (a *actuator) patchStatus(ctx context.Context, ex *extensionsv1alpha1.Extension, status *ontapv1alpha1.TridentStatus) error {
+	status.SetGroupVersionKind(ontapv1alpha1.SchemeGroupVersion.WithKind("TridentStatus"))
+
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode provider status: %w", err)
+	}
+
+	patch := client.MergeFrom(ex.DeepCopy())
+	ex.Status.ProviderStatus = &runtime.RawExtension{Raw: raw}
+	return a.client.Status().Patch(ctx, ex, patch)
+}
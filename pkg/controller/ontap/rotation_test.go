@@ -0,0 +1,60 @@
+package ontap
+
+import (
+	"testing"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestRotationReason(t *testing.T) {
+	var (
+		inWindow  = time.Date(2025, 1, 10, 22, 30, 0, 0, time.UTC)
+		outWindow = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+		rotation  = &config.CredentialsRotationConfig{Interval: metav1.Duration{Duration: 24 * time.Hour}}
+		shoot     = func(annotation string) *gardencorev1beta1.Shoot {
+			s := &gardencorev1beta1.Shoot{
+				Spec: gardencorev1beta1.ShootSpec{
+					Maintenance: &gardencorev1beta1.Maintenance{
+						TimeWindow: &gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"},
+					},
+				},
+			}
+			if annotation != "" {
+				s.Annotations = map[string]string{AnnotationRotateCredentials: annotation}
+			}
+			return s
+		}
+		rotatedAt = func(t time.Time) *ontapv1alpha1.CredentialsStatus {
+			return &ontapv1alpha1.CredentialsStatus{LastRotationTime: &metav1.Time{Time: t}}
+		}
+	)
+
+	tests := []struct {
+		name     string
+		rotation *config.CredentialsRotationConfig
+		shoot    *gardencorev1beta1.Shoot
+		status   *ontapv1alpha1.CredentialsStatus
+		now      time.Time
+		wantDue  bool
+	}{
+		{name: "nothing configured", shoot: shoot(""), status: &ontapv1alpha1.CredentialsStatus{}, now: inWindow},
+		{name: "requested outside of the maintenance window", shoot: shoot("1"), status: &ontapv1alpha1.CredentialsStatus{}, now: outWindow, wantDue: true},
+		{name: "request already handled", shoot: shoot("1"), status: &ontapv1alpha1.CredentialsStatus{LastRotationRequest: "1"}, now: inWindow},
+		{name: "never rotated", rotation: rotation, shoot: shoot(""), status: &ontapv1alpha1.CredentialsStatus{}, now: inWindow, wantDue: true},
+		{name: "interval elapsed", rotation: rotation, shoot: shoot(""), status: rotatedAt(inWindow.Add(-48 * time.Hour)), now: inWindow, wantDue: true},
+		{name: "interval elapsed outside of the maintenance window", rotation: rotation, shoot: shoot(""), status: rotatedAt(outWindow.Add(-48 * time.Hour)), now: outWindow},
+		{name: "interval not elapsed", rotation: rotation, shoot: shoot(""), status: rotatedAt(inWindow.Add(-time.Hour)), now: inWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := rotationReason(tt.rotation, tt.shoot, tt.status, tt.now)
+			assert.Equal(t, tt.wantDue, reason != "", reason)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ontap
+
+import (
+	"context"
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+// recordStoragePrefix stores the storage prefix of the deployed backends in the provider status of the Extension, it
+// is kept for the lifetime of the shoot because Trident rejects changes of the storage prefix.
+func (a *actuator) recordStoragePrefix(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, storagePrefix string) error {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	if status.StoragePrefix == storagePrefix {
+		return nil
+	}
+	status.StoragePrefix = storagePrefix
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record storage prefix in extension status: %w", err)
+	}
+
+	log.Info("Recorded storage prefix", "storagePrefix", storagePrefix)
+	return nil
+}
+
+// recordUsage stores the usage of the SVM against its limits and the usage of the volumes of the shoot in the provider
+// status of the Extension, the status is only patched if the usage changed.
+func (a *actuator) recordUsage(ctx context.Context, log logr.Logger, ex *extensionsv1alpha1.Extension, usage *ontapv1alpha1.UsageStatus, shootUsage *ontapv1alpha1.ShootUsageStatus) error {
+	status, err := a.decodeStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(status.Usage, usage) && equality.Semantic.DeepEqual(status.ShootUsage, shootUsage) {
+		return nil
+	}
+	status.Usage = usage
+	status.ShootUsage = shootUsage
+
+	if err := a.patchStatus(ctx, ex, status); err != nil {
+		return fmt.Errorf("failed to record usage in extension status: %w", err)
+	}
+
+	log.Info("Recorded SVM usage", "storageAllocated", usage.StorageAllocated.String(), "volumes", usage.Volumes)
+	return nil
+}
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the lifecycle events emitted by the extension.
+const (
+	ReasonSVMCreated            = "SVMCreated"
+	ReasonSVMNotReady           = "SVMNotReady"
+	ReasonLIFCreated            = "LIFCreated"
+	ReasonAccountCreated        = "AccountCreated"
+	ReasonPasswordReset         = "PasswordReset"
+	ReasonSecretCreated         = "SecretCreated"
+	ReasonSecretMigrated        = "SecretMigrated"
+	ReasonTridentDeployed       = "TridentDeployed"
+	ReasonTridentFailed         = "TridentDeploymentFailed"
+	ReasonDriftDetected         = "DriftDetected"
+	ReasonDriftRepaired         = "DriftRepaired"
+	ReasonOrphanDetected        = "OrphanDetected"
+	ReasonOrphanRemoved         = "OrphanRemoved"
+	ReasonCredentialsRotated    = "CredentialsRotated"
+	ReasonCertificateIssued     = "CertificateIssued"
+	ReasonAccountMigrated       = "AccountMigrated"
+	ReasonSVMProtocolsEnabled   = "SVMProtocolsEnabled"
+	ReasonLIFUpdated            = "LIFUpdated"
+	ReasonExportPolicyCreated   = "ExportPolicyCreated"
+	ReasonExportPolicyUpdated   = "ExportPolicyUpdated"
+	ReasonQoSPolicyCreated      = "QoSPolicyCreated"
+	ReasonQoSPolicyUpdated      = "QoSPolicyUpdated"
+	ReasonSVMLimitsUpdated      = "SVMLimitsUpdated"
+	ReasonSnapshotPolicyCreated = "SnapshotPolicyCreated"
+	ReasonSnapshotPolicyUpdated = "SnapshotPolicyUpdated"
+	ReasonClusterPeered         = "ClusterPeered"
+	ReasonSVMPeered             = "SVMPeered"
+	ReasonSVMDRCreated          = "SVMDRRelationshipCreated"
+	ReasonSVMDRUnhealthy        = "SVMDRRelationshipUnhealthy"
+	ReasonFailedOver            = "SVMFailedOver"
+	ReasonFailoverRefused       = "SVMFailoverRefused"
+	ReasonSwitchover            = "MetroClusterSwitchover"
+	ReasonSwitchback            = "MetroClusterSwitchback"
+)
+
+// Actions of the lifecycle events emitted by the extension.
+const (
+	ActionCreate   = "Create"
+	ActionRepair   = "Repair"
+	ActionDeploy   = "Deploy"
+	ActionDetect   = "Detect"
+	ActionDelete   = "Delete"
+	ActionRotate   = "Rotate"
+	ActionUpdate   = "Update"
+	ActionFailover = "Failover"
+)
+
+const (
+	// shootEventNamespace is the namespace in the shoot where events are mirrored to.
+	shootEventNamespace = "kube-system"
+	// component is the source component of events in the shoot.
+	component = "gardener-extension-ontap"
+)
+
+// Recorder emits lifecycle events on an Extension and optionally mirrors them into the shoot's kube-system namespace.
+// A nil Recorder is valid and drops all events.
+type Recorder struct {
+	log         logr.Logger
+	recorder    k8sevents.EventRecorder
+	regarding   runtime.Object
+	shootClient client.Client
+}
+
+// NewRecorder returns a Recorder for the given Extension. shootClient may be nil if events should not be mirrored into the shoot.
+func NewRecorder(log logr.Logger, recorder k8sevents.EventRecorder, extension *extensionsv1alpha1.Extension, shootClient client.Client) *Recorder {
+	r := &Recorder{
+		log:         log,
+		recorder:    recorder,
+		shootClient: shootClient,
+	}
+	if extension != nil {
+		r.regarding = extension
+	}
+	return r
+}
+
+// NewNamespaceRecorder returns a Recorder which emits events on the given seed namespace.
+// It is used for events which do not belong to an Extension, e.g. about orphaned ONTAP objects.
+func NewNamespaceRecorder(log logr.Logger, recorder k8sevents.EventRecorder, namespace string) *Recorder {
+	return &Recorder{
+		log:      log,
+		recorder: recorder,
+		regarding: &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       namespace,
+			Namespace:  namespace,
+		},
+	}
+}
+
+// Normal emits an informational event.
+func (r *Recorder) Normal(ctx context.Context, reason, action, messageFmt string, args ...any) {
+	r.emit(ctx, corev1.EventTypeNormal, reason, action, fmt.Sprintf(messageFmt, args...))
+}
+
+// Warning emits a warning event.
+func (r *Recorder) Warning(ctx context.Context, reason, action, messageFmt string, args ...any) {
+	r.emit(ctx, corev1.EventTypeWarning, reason, action, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) emit(ctx context.Context, eventType, reason, action, message string) {
+	if r == nil {
+		return
+	}
+
+	if r.recorder != nil && r.regarding != nil {
+		r.recorder.Eventf(r.regarding, nil, eventType, reason, action, "%s", message)
+	}
+
+	if r.shootClient == nil {
+		return
+	}
+
+	// repeated events are counted on the existing event like the event recorder does, e.g. for every reconciliation
+	now := metav1.NewTime(time.Now())
+	existing := &corev1.Event{}
+	err := r.shootClient.Get(ctx, client.ObjectKey{Namespace: shootEventNamespace, Name: shootEventName(eventType, reason)}, existing)
+	if err == nil {
+		patch := client.MergeFrom(existing.DeepCopy())
+		existing.Count++
+		existing.LastTimestamp = now
+		existing.Action = action
+		existing.Message = message
+		if err := r.shootClient.Patch(ctx, existing, patch); err != nil {
+			r.log.Error(err, "unable to mirror event into shoot", "reason", reason)
+		}
+		return
+	}
+	if !apierrors.IsNotFound(err) {
+		r.log.Error(err, "unable to mirror event into shoot", "reason", reason)
+		return
+	}
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      shootEventName(eventType, reason),
+			Namespace: shootEventNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       shootEventNamespace,
+		},
+		Type:                eventType,
+		Reason:              reason,
+		Action:              action,
+		Message:             message,
+		Source:              corev1.EventSource{Component: component},
+		ReportingController: component,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	if err := r.shootClient.Create(ctx, event); err != nil {
+		r.log.Error(err, "unable to mirror event into shoot", "reason", reason)
+	}
+}
+
+// shootEventName returns the name of the mirrored event of the given type and reason, all events of the same type
+// and reason regard the same object and are counted on one event.
+func shootEventName(eventType, reason string) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s", component, eventType, reason))
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	ex := &extensionsv1alpha1.Extension{ObjectMeta: metav1.ObjectMeta{Name: "ontap", Namespace: "shoot--proj--myshoot"}}
+
+	t.Run("emits on extension and mirrors into shoot", func(t *testing.T) {
+		fakeRecorder := k8sevents.NewFakeRecorder(10)
+		shootClient := fake.NewClientBuilder().Build()
+
+		r := NewRecorder(logr.Discard(), fakeRecorder, ex, shootClient)
+		r.Normal(ctx, ReasonSVMCreated, ActionCreate, "SVM %s created", "p123")
+
+		require.Len(t, fakeRecorder.Events, 1)
+		assert.Equal(t, "Normal SVMCreated SVM p123 created", <-fakeRecorder.Events)
+
+		shootEvents := &corev1.EventList{}
+		require.NoError(t, shootClient.List(ctx, shootEvents, client.InNamespace("kube-system")))
+		require.Len(t, shootEvents.Items, 1)
+		assert.Equal(t, ReasonSVMCreated, shootEvents.Items[0].Reason)
+		assert.Equal(t, "SVM p123 created", shootEvents.Items[0].Message)
+	})
+
+	t.Run("counts repeated events on one shoot event", func(t *testing.T) {
+		fakeRecorder := k8sevents.NewFakeRecorder(10)
+		shootClient := fake.NewClientBuilder().Build()
+
+		r := NewRecorder(logr.Discard(), fakeRecorder, ex, shootClient)
+		r.Normal(ctx, ReasonTridentDeployed, ActionDeploy, "deployed %d", 1)
+		r.Normal(ctx, ReasonTridentDeployed, ActionDeploy, "deployed %d", 2)
+		r.Warning(ctx, ReasonTridentFailed, ActionDeploy, "failed")
+
+		shootEvents := &corev1.EventList{}
+		require.NoError(t, shootClient.List(ctx, shootEvents, client.InNamespace("kube-system")))
+		require.Len(t, shootEvents.Items, 2)
+
+		deployed := &corev1.Event{}
+		require.NoError(t, shootClient.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: "gardener-extension-ontap.normal.tridentdeployed"}, deployed))
+		assert.Equal(t, int32(2), deployed.Count)
+		assert.Equal(t, "deployed 2", deployed.Message)
+	})
+
+	t.Run("does not mirror without shoot client", func(t *testing.T) {
+		fakeRecorder := k8sevents.NewFakeRecorder(10)
+
+		r := NewRecorder(logr.Discard(), fakeRecorder, ex, nil)
+		r.Warning(ctx, ReasonSVMNotReady, ActionCreate, "not ready")
+
+		require.Len(t, fakeRecorder.Events, 1)
+		assert.Equal(t, "Warning SVMNotReady not ready", <-fakeRecorder.Events)
+	})
+
+	t.Run("nil recorder drops events", func(t *testing.T) {
+		var r *Recorder
+		r.Normal(ctx, ReasonSVMCreated, ActionCreate, "dropped")
+	})
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "gardener_extension_ontap"
+
+	// UnknownCluster is used as cluster label if the cluster name of a client cannot be determined.
+	UnknownCluster = "unknown"
+)
+
+var (
+	// ONTAPRequestDuration observes the latency of ONTAP REST API calls by cluster and endpoint.
+	ONTAPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ontap_request_duration_seconds",
+		Help:      "Latency of ONTAP REST API requests.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"cluster", "endpoint"})
+
+	// ONTAPRequestErrors counts failed ONTAP REST API calls by cluster and endpoint.
+	ONTAPRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ontap_request_errors_total",
+		Help:      "Number of failed ONTAP REST API requests.",
+	}, []string{"cluster", "endpoint"})
+
+	// SVMs is the number of SVMs found on a cluster.
+	SVMs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svms",
+		Help:      "Number of SVMs on an ONTAP cluster.",
+	}, []string{"cluster"})
+
+	// SVMLIFs is the number of network interfaces of an SVM.
+	SVMLIFs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_lifs",
+		Help:      "Number of network interfaces of an SVM.",
+	}, []string{"cluster", "svm"})
+
+	// SVMStorageLimitBytes is the storage limit of an SVM, it is not reported for SVMs without storage limit.
+	SVMStorageLimitBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_storage_limit_bytes",
+		Help:      "Storage limit of an SVM in bytes.",
+	}, []string{"cluster", "svm"})
+
+	// SVMStorageAllocatedBytes is the space allocated by the volumes of an SVM.
+	SVMStorageAllocatedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_storage_allocated_bytes",
+		Help:      "Space allocated by the volumes of an SVM in bytes.",
+	}, []string{"cluster", "svm"})
+
+	// SVMMaxVolumes is the maximum number of volumes of an SVM, it is not reported for SVMs without volume limit.
+	SVMMaxVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_max_volumes",
+		Help:      "Maximum number of volumes of an SVM.",
+	}, []string{"cluster", "svm"})
+
+	// SVMVolumes is the number of volumes of an SVM without its root volume.
+	SVMVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_volumes",
+		Help:      "Number of volumes of an SVM.",
+	}, []string{"cluster", "svm"})
+
+	// SVMDRHealthy is whether the SVM-DR relationship of an SVM is healthy.
+	SVMDRHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_dr_healthy",
+		Help:      "Whether the SVM-DR relationship of an SVM is healthy (1) or not (0).",
+	}, []string{"cluster", "svm"})
+
+	// SVMDRLag is the time since the last transfer of the SVM-DR relationship of an SVM.
+	SVMDRLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_dr_lag_seconds",
+		Help:      "Time since the last transfer of the SVM-DR relationship of an SVM in seconds.",
+	}, []string{"cluster", "svm"})
+
+	// MetroClusterSwitchover is whether a MetroCluster site runs the SVMs of its partner site.
+	MetroClusterSwitchover = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "metrocluster_switchover",
+		Help:      "Whether a MetroCluster site is in switchover (1) or not (0).",
+	}, []string{"cluster"})
+
+	// ShootVolumes is the number of volumes of a shoot on the SVM of its project.
+	ShootVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shoot_volumes",
+		Help:      "Number of volumes of a shoot.",
+	}, []string{"cluster", "svm", "shoot"})
+
+	// ShootProvisionedBytes is the provisioned size of the volumes of a shoot.
+	ShootProvisionedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shoot_provisioned_bytes",
+		Help:      "Provisioned size of the volumes of a shoot in bytes.",
+	}, []string{"cluster", "svm", "shoot"})
+
+	// ShootUsedBytes is the used size of the volumes of a shoot.
+	ShootUsedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shoot_used_bytes",
+		Help:      "Used size of the volumes of a shoot in bytes.",
+	}, []string{"cluster", "svm", "shoot"})
+
+	// AggregateUsedBytes is the used block storage of an aggregate.
+	AggregateUsedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "aggregate_used_bytes",
+		Help:      "Used block storage of an ONTAP aggregate in bytes.",
+	}, []string{"cluster", "aggregate"})
+
+	// AggregateAvailableBytes is the available block storage of an aggregate.
+	AggregateAvailableBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "aggregate_available_bytes",
+		Help:      "Available block storage of an ONTAP aggregate in bytes.",
+	}, []string{"cluster", "aggregate"})
+
+	// AggregateVolumes is the number of volumes placed on an aggregate.
+	AggregateVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "aggregate_volumes",
+		Help:      "Number of volumes on an ONTAP aggregate.",
+	}, []string{"cluster", "aggregate"})
+
+	// SVMDrift reports drift of an SVM from its desired state by type, it is set to 1 while the drift persists.
+	SVMDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "svm_drift",
+		Help:      "Detected drift of an SVM from its desired state.",
+	}, []string{"shoot", "svm", "type"})
+
+	// SVMDriftRepairs counts drift repairs by type and result.
+	SVMDriftRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "svm_drift_repairs_total",
+		Help:      "Number of attempted SVM drift repairs.",
+	}, []string{"type", "result"})
+
+	// OrphanedSVMs is the number of SVMs on a cluster which do not belong to any shoot of the seed.
+	OrphanedSVMs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphaned_svms",
+		Help:      "Number of SVMs without a shoot.",
+	}, []string{"cluster"})
+
+	// OrphanedAccounts is the number of SVM accounts on a cluster which do not belong to any shoot of the seed.
+	OrphanedAccounts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphaned_accounts",
+		Help:      "Number of SVM accounts without a shoot.",
+	}, []string{"cluster"})
+
+	// OrphansRemoved counts removals of orphaned objects by kind and result.
+	OrphansRemoved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orphans_removed_total",
+		Help:      "Number of attempted removals of orphaned ONTAP objects.",
+	}, []string{"kind", "result"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ONTAPRequestDuration,
+		ONTAPRequestErrors,
+		SVMs,
+		SVMLIFs,
+		SVMStorageLimitBytes,
+		SVMStorageAllocatedBytes,
+		SVMMaxVolumes,
+		SVMVolumes,
+		SVMDRHealthy,
+		SVMDRLag,
+		MetroClusterSwitchover,
+		ShootVolumes,
+		ShootProvisionedBytes,
+		ShootUsedBytes,
+		AggregateUsedBytes,
+		AggregateAvailableBytes,
+		AggregateVolumes,
+		SVMDrift,
+		SVMDriftRepairs,
+		OrphanedSVMs,
+		OrphanedAccounts,
+		OrphansRemoved,
+	)
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+)
+
+// transport wraps an ONTAP client transport and records latency and errors of every request.
+type transport struct {
+	cluster string
+	next    runtime.ClientTransport
+}
+
+// InstrumentClient replaces the transport of the given client with one that records request metrics
+// labeled with the given cluster name.
+func InstrumentClient(c *ontapv1.Ontap, cluster string) {
+	c.SetTransport(&transport{cluster: cluster, next: c.Transport})
+}
+
+// ClusterName returns the cluster name an instrumented client was registered with.
+func ClusterName(c *ontapv1.Ontap) string {
+	if c == nil {
+		return UnknownCluster
+	}
+	if t, ok := c.Transport.(*transport); ok {
+		return t.cluster
+	}
+	return UnknownCluster
+}
+
+// Submit implements runtime.ClientTransport.
+func (t *transport) Submit(op *runtime.ClientOperation) (any, error) {
+	start := time.Now()
+	result, err := t.next.Submit(op)
+
+	ONTAPRequestDuration.WithLabelValues(t.cluster, op.ID).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ONTAPRequestErrors.WithLabelValues(t.cluster, op.ID).Inc()
+	}
+
+	return result, err
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTransport struct {
+	err error
+}
+
+func (f *fakeTransport) Submit(_ *runtime.ClientOperation) (any, error) {
+	return nil, f.err
+}
+
+func TestInstrumentClient(t *testing.T) {
+	t.Run("records latency and errors per endpoint", func(t *testing.T) {
+		c := ontapv1.New(&fakeTransport{err: errors.New("boom")}, strfmt.Default)
+		InstrumentClient(c, "cluster-a")
+
+		assert.Equal(t, "cluster-a", ClusterName(c))
+
+		_, err := c.Transport.Submit(&runtime.ClientOperation{ID: "svm_collection_get"})
+		require.Error(t, err)
+
+		errCount := &dto.Metric{}
+		require.NoError(t, ONTAPRequestErrors.WithLabelValues("cluster-a", "svm_collection_get").Write(errCount))
+		assert.InDelta(t, 1, errCount.GetCounter().GetValue(), 0)
+
+		histogram, err := ONTAPRequestDuration.GetMetricWithLabelValues("cluster-a", "svm_collection_get")
+		require.NoError(t, err)
+		latency := &dto.Metric{}
+		require.NoError(t, histogram.(interface{ Write(*dto.Metric) error }).Write(latency))
+		assert.Equal(t, uint64(1), latency.GetHistogram().GetSampleCount())
+	})
+
+	t.Run("uninstrumented client has unknown cluster", func(t *testing.T) {
+		c := ontapv1.New(&fakeTransport{}, strfmt.Default)
+		assert.Equal(t, UnknownCluster, ClusterName(c))
+		assert.Equal(t, UnknownCluster, ClusterName(nil))
+	})
+}
@@ -0,0 +1,239 @@
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// Redacted replaces every masked value.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched case-insensitively against log keys, struct fields and map keys.
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"token",
+	"privatekey",
+	"private_key",
+}
+
+// Logger returns a logger which masks credential-bearing values before they reach the sink of the given logger.
+func Logger(log logr.Logger) logr.Logger {
+	sink := log.GetSink()
+	if sink == nil {
+		return log
+	}
+	if _, ok := sink.(*redactingSink); ok {
+		return log
+	}
+	return log.WithSink(&redactingSink{delegate: sink})
+}
+
+// redactingSink is a logr.LogSink which masks sensitive key/value pairs.
+type redactingSink struct {
+	delegate logr.LogSink
+}
+
+var (
+	_ logr.LogSink          = &redactingSink{}
+	_ logr.CallDepthLogSink = &redactingSink{}
+)
+
+func (s *redactingSink) Init(info logr.RuntimeInfo) {
+	// account for the additional frame of this sink
+	info.CallDepth++
+	s.delegate.Init(info)
+}
+
+func (s *redactingSink) Enabled(level int) bool {
+	return s.delegate.Enabled(level)
+}
+
+func (s *redactingSink) Info(level int, msg string, keysAndValues ...any) {
+	s.delegate.Info(level, msg, KeysAndValues(keysAndValues)...)
+}
+
+func (s *redactingSink) Error(err error, msg string, keysAndValues ...any) {
+	s.delegate.Error(err, msg, KeysAndValues(keysAndValues)...)
+}
+
+func (s *redactingSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &redactingSink{delegate: s.delegate.WithValues(KeysAndValues(keysAndValues)...)}
+}
+
+func (s *redactingSink) WithName(name string) logr.LogSink {
+	return &redactingSink{delegate: s.delegate.WithName(name)}
+}
+
+func (s *redactingSink) WithCallDepth(depth int) logr.LogSink {
+	if d, ok := s.delegate.(logr.CallDepthLogSink); ok {
+		return &redactingSink{delegate: d.WithCallDepth(depth)}
+	}
+	return s
+}
+
+// KeysAndValues returns a copy of the given logr key/value pairs with all sensitive values masked.
+func KeysAndValues(keysAndValues []any) []any {
+	if len(keysAndValues) == 0 {
+		return keysAndValues
+	}
+
+	result := make([]any, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i += 2 {
+		result[i] = keysAndValues[i]
+		if i+1 >= len(keysAndValues) {
+			break
+		}
+
+		if key, ok := keysAndValues[i].(string); ok && isSensitiveKey(key) {
+			result[i+1] = Redacted
+			continue
+		}
+		result[i+1] = Value(keysAndValues[i+1])
+	}
+	return result
+}
+
+// Value masks credentials contained in the given value.
+// Strings are checked for rendered Secret manifests, structs and maps are masked by their field names.
+func Value(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return Manifest(v)
+	case []byte:
+		return Manifest(string(v))
+	case error, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return value
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return value
+	}
+
+	if !containsSensitiveField(rv.Type(), map[reflect.Type]bool{}) && rv.Kind() != reflect.Map && rv.Kind() != reflect.Slice {
+		return value
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return Redacted
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return Redacted
+	}
+
+	masked, changed := maskGeneric(generic)
+	if !changed {
+		return value
+	}
+	return masked
+}
+
+// Manifest masks the data and stringData values of rendered Secret manifests.
+func Manifest(s string) string {
+	if !strings.Contains(s, "kind: Secret") {
+		return s
+	}
+
+	var (
+		lines       = strings.Split(s, "\n")
+		blockIndent = -1
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if blockIndent >= 0 {
+			if trimmed != "" && indent > blockIndent {
+				if key, _, ok := strings.Cut(line, ":"); ok {
+					lines[i] = key + ": " + Redacted
+				}
+				continue
+			}
+			blockIndent = -1
+		}
+
+		if trimmed == "data:" || trimmed == "stringData:" {
+			blockIndent = indent
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func maskGeneric(value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		changed := false
+		for key, val := range v {
+			if isSensitiveKey(key) {
+				v[key] = Redacted
+				changed = true
+				continue
+			}
+			masked, c := maskGeneric(val)
+			v[key] = masked
+			changed = changed || c
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, val := range v {
+			masked, c := maskGeneric(val)
+			v[i] = masked
+			changed = changed || c
+		}
+		return v, changed
+	case string:
+		masked := Manifest(v)
+		return masked, masked != v
+	}
+	return value, false
+}
+
+func containsSensitiveField(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if isSensitiveKey(field.Name) || isSensitiveKey(field.Tag.Get("json")) {
+			return true
+		}
+		if containsSensitiveField(field.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package redact_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/secrets"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/redact"
+)
+
+func TestLogger(t *testing.T) {
+	const password = "s3cr3t-Pa55"
+
+	secret := secrets.Secrets{
+		Name:      "a-secret",
+		Namespace: "kube-system",
+		Project:   "project-a",
+		Username:  "a-user",
+		Password:  password,
+	}
+	rendered, err := secrets.Parse(secret)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		keysAndValues []any
+		want          []string
+	}{
+		{
+			name:          "sensitive key",
+			keysAndValues: []any{"password", password},
+			want:          []string{redact.Redacted},
+		},
+		{
+			name:          "struct with password field",
+			keysAndValues: []any{"input", secret},
+			want:          []string{"a-user", redact.Redacted},
+		},
+		{
+			name:          "pointer to struct with password field",
+			keysAndValues: []any{"input", &secret},
+			want:          []string{"a-user", redact.Redacted},
+		},
+		{
+			name:          "rendered secret manifest",
+			keysAndValues: []any{"output", rendered},
+			want:          []string{"a-secret", redact.Redacted},
+		},
+		{
+			name:          "map with password key",
+			keysAndValues: []any{"data", map[string]string{"username": "a-user", "password": password}},
+			want:          []string{"a-user", redact.Redacted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			log := redact.Logger(funcr.New(func(prefix, args string) {
+				out.WriteString(args)
+			}, funcr.Options{}))
+
+			log.Info("test", tt.keysAndValues...)
+			log.WithValues(tt.keysAndValues...).Error(nil, "test")
+
+			assert.NotContains(t, out.String(), password)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Secret
+metadata:
+  name: a-secret
+stringData:
+  username: a-user
+  password: a-password
+type: Opaque
+`
+	want := `apiVersion: v1
+kind: Secret
+metadata:
+  name: a-secret
+stringData:
+  username: [REDACTED]
+  password: [REDACTED]
+type: Opaque
+`
+	assert.Equal(t, want, redact.Manifest(manifest))
+
+	notASecret := "kind: ConfigMap\ndata:\n  key: value\n"
+	assert.Equal(t, notASecret, redact.Manifest(notASecret))
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+const (
+	serviceName = "gardener-extension-ontap"
+	tracerName  = "github.com/metal-stack/gardener-extension-ontap"
+)
+
+// Attribute keys used on all spans of the extension.
+const (
+	ClusterKey = attribute.Key("ontap.cluster")
+	SVMKey     = attribute.Key("ontap.svm")
+	ShootKey   = attribute.Key("gardener.shoot.namespace")
+)
+
+// Setup registers a global tracer provider exporting to the configured OTLP collector.
+// If cfg is nil, tracing stays disabled and the returned shutdown func is a no-op.
+func Setup(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	if cfg == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create otlp trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// SetupInMemory registers a global tracer provider which records all spans in memory, it is meant to be used in tests.
+func SetupInMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
+}
+
+// Start starts a span with the given name. Attributes stored in the context with WithAttributes are added to the span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attributesFromContext(ctx), attrs...)
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the given error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type attributesKey struct{}
+
+// WithAttributes returns a context carrying the given attributes, which are added to all spans started from it.
+func WithAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	return context.WithValue(ctx, attributesKey{}, append(attributesFromContext(ctx), attrs...))
+}
+
+func attributesFromContext(ctx context.Context) []attribute.KeyValue {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attributesKey{}).([]attribute.KeyValue)
+	// copy to prevent appending to the slice stored in the context
+	return append([]attribute.KeyValue(nil), attrs...)
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type fakeTransport struct {
+	err error
+}
+
+func (f *fakeTransport) Submit(_ *runtime.ClientOperation) (any, error) {
+	return nil, f.err
+}
+
+func TestInstrumentClient(t *testing.T) {
+	exporter := SetupInMemory()
+
+	c := ontapv1.New(&fakeTransport{err: errors.New("boom")}, strfmt.Default)
+	InstrumentClient(c, "cluster-a")
+
+	ctx := WithAttributes(context.Background(), ShootKey.String("shoot--proj--myshoot"), SVMKey.String("p123"))
+	ctx, parent := Start(ctx, "EnsureSVM")
+
+	_, err := c.Transport.Submit(&runtime.ClientOperation{ID: "svm_create", Method: "POST", PathPattern: "/svm/svms", Context: ctx})
+	require.Error(t, err)
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	request, phase := spans[0], spans[1]
+	assert.Equal(t, "ONTAP svm_create", request.Name)
+	assert.Equal(t, phase.SpanContext.SpanID(), request.Parent.SpanID())
+	assert.Equal(t, codes.Error, request.Status.Code)
+	assert.Subset(t, request.Attributes, []attribute.KeyValue{
+		ClusterKey.String("cluster-a"),
+		SVMKey.String("p123"),
+		ShootKey.String("shoot--proj--myshoot"),
+	})
+
+	assert.Equal(t, "EnsureSVM", phase.Name)
+	assert.Equal(t, codes.Unset, phase.Status.Code)
+}
+
+func TestWithAttributesDoesNotLeak(t *testing.T) {
+	parent := WithAttributes(context.Background(), ShootKey.String("a"))
+	_ = WithAttributes(parent, SVMKey.String("b"))
+	_ = WithAttributes(parent, SVMKey.String("c"))
+
+	assert.Equal(t, []attribute.KeyValue{ShootKey.String("a")}, attributesFromContext(parent))
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/go-openapi/runtime"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// transport wraps an ONTAP client transport and starts a span for every request.
+type transport struct {
+	cluster string
+	next    runtime.ClientTransport
+}
+
+// InstrumentClient replaces the transport of the given client with one that traces every request.
+func InstrumentClient(c *ontapv1.Ontap, cluster string) {
+	c.SetTransport(&transport{cluster: cluster, next: c.Transport})
+}
+
+// Submit implements runtime.ClientTransport.
+func (t *transport) Submit(op *runtime.ClientOperation) (any, error) {
+	ctx := op.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	spanCtx, span := Start(ctx, "ONTAP "+op.ID,
+		ClusterKey.String(t.cluster),
+		attribute.String("http.request.method", op.Method),
+		attribute.String("url.template", op.PathPattern),
+	)
+	// keep a nil context, the runtime applies its default timeout in that case
+	if op.Context != nil {
+		op.Context = spanCtx
+	}
+
+	result, err := t.next.Submit(op)
+	End(span, err)
+
+	return result, err
+}
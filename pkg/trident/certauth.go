@@ -0,0 +1,443 @@
+package trident
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+const (
+	// ClientCertificateKey is the key of the PEM encoded client certificate in the credentials secrets.
+	ClientCertificateKey = "clientCertificate"
+	// ClientPrivateKeyKey is the key of the PEM encoded private key of the client certificate in the credentials secrets.
+	ClientPrivateKeyKey = "clientPrivateKey"
+
+	// clientCASecretName is the name of the secret of the seed-local CA the client certificates are issued from.
+	clientCASecretName = "gardener-extension-ontap-client-ca"
+	clientCACommonName = "gardener-extension-ontap-client-ca"
+
+	authMethodPassword    = "password"
+	authMethodCertificate = "certificate"
+
+	certificateKeySize = 2048
+)
+
+// clientCA is the CA the client certificates of the SVM accounts are issued from.
+type clientCA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *rsa.PrivateKey
+	keyPEM  []byte
+}
+
+// ensureClientCA returns the seed-local client CA, it is generated if it does not exist yet or expires
+// before a client certificate issued now. Certificates of the previous CA are renewed with the next reconciliation.
+func (m *SvmManager) ensureClientCA(ctx context.Context, cfg *config.CertificateAuthenticationConfig, now time.Time) (*clientCA, error) {
+	secret := &corev1.Secret{}
+	err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: cfg.CASecretNamespace, Name: clientCASecretName}, secret)
+	if client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get client CA secret %s/%s: %w", cfg.CASecretNamespace, clientCASecretName, err)
+	}
+
+	found := err == nil
+	if found {
+		ca, parseErr := parseClientCA(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if parseErr == nil && now.Add(cfg.Validity.Duration).Before(ca.cert.NotAfter) {
+			return ca, nil
+		}
+		m.log.Info("Client CA is invalid or expires soon, generating a new one", "secretName", clientCASecretName, "error", parseErr)
+	}
+
+	ca, err := generateClientCA(cfg.CAValidity.Duration, now)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clientCASecretName,
+				Namespace: cfg.CASecretNamespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of":    "gardener-extension-ontap",
+					"app.kubernetes.io/managed-by": "gardener",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{corev1.TLSCertKey: ca.certPEM, corev1.TLSPrivateKeyKey: ca.keyPEM},
+		}
+		if err := m.seedClient.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to create client CA secret %s/%s: %w", cfg.CASecretNamespace, clientCASecretName, err)
+		}
+	} else {
+		secret.Data = map[string][]byte{corev1.TLSCertKey: ca.certPEM, corev1.TLSPrivateKeyKey: ca.keyPEM}
+		if err := m.seedClient.Update(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to update client CA secret %s/%s: %w", cfg.CASecretNamespace, clientCASecretName, err)
+		}
+	}
+
+	m.log.Info("Generated client CA", "secretName", clientCASecretName, "namespace", cfg.CASecretNamespace, "notAfter", ca.cert.NotAfter)
+	return ca, nil
+}
+
+func generateClientCA(validity time.Duration, now time.Time) (*clientCA, error) {
+	key, err := rsa.GenerateKey(rand.Reader, certificateKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate client CA key: %w", err)
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: clientCACommonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client CA certificate: %w", err)
+	}
+	return parseClientCA(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func parseClientCA(certPEM, keyPEM []byte) (*clientCA, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+	return &clientCA{cert: cert, certPEM: certPEM, key: key, keyPEM: keyPEM}, nil
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func randomSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// issue returns a PEM encoded client certificate and private key for the given account, ONTAP maps the
+// certificate to the account by its common name.
+func (ca *clientCA) issue(username string, validity time.Duration, now time.Time) (certPEM, keyPEM []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, certificateKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate client certificate key: %w", err)
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: username},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create client certificate for %s: %w", username, err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
+
+// needsRenewal returns whether the given client certificate is missing, was not issued by the CA for the account
+// or expires within renewBefore.
+func (ca *clientCA) needsRenewal(certPEM, keyPEM []byte, username string, renewBefore time.Duration, now time.Time) bool {
+	if len(keyPEM) == 0 {
+		return true
+	}
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return true
+	}
+	if cert.CheckSignatureFrom(ca.cert) != nil || cert.Subject.CommonName != username {
+		return true
+	}
+	return !now.Add(renewBefore).Before(cert.NotAfter)
+}
+
+// ensureCertificateUserState ensures the account of the shoot authenticates with a client certificate issued
+// from the seed-local CA and the certificate is stored in the seed secret, the secret does not contain a password.
+func (m *SvmManager) ensureCertificateUserState(ctx context.Context, ontapClient *ontapv1.Ontap, username, secretName string, userExists bool, opts userAndSecretOptions) error {
+	now := time.Now()
+	ca, err := m.ensureClientCA(ctx, opts.certificateAuthentication, now)
+	if err != nil {
+		return err
+	}
+
+	if err := m.ensureClientCAOnSVM(ctx, ontapClient, ca, opts); err != nil {
+		return err
+	}
+	if err := m.ensureClientAuthenticationEnabled(ctx, ontapClient, opts); err != nil {
+		return err
+	}
+
+	if userExists {
+		if err := m.ensureAccountAuthMethod(ctx, ontapClient, username, authMethodCertificate, opts); err != nil {
+			return err
+		}
+	} else {
+		ontapOpts := ontapUserOptions{
+			username:         username,
+			svmName:          opts.projectID,
+			kubeSeedSecretNs: opts.svmSeedSecretNamespace,
+			svmUUID:          opts.svmUUID,
+			comment:          NewOwnership(opts.seed, opts.shootNamespace, opts.projectID).Comment(),
+			authMethod:       authMethodCertificate,
+			role:             opts.accountRoleName(),
+		}
+		if _, err := m.attemptUserCreation(ctx, ontapClient, ontapOpts, ""); err != nil {
+			return fmt.Errorf("failed to create ONTAP user with certificate authentication: %w", err)
+		}
+		m.recorder.Normal(ctx, events.ReasonAccountCreated, events.ActionCreate, "account %s with certificate authentication created on SVM %s", username, opts.projectID)
+	}
+
+	return m.ensureClientCertificateInSeed(ctx, ca, username, secretName, false, opts)
+}
+
+// ensureClientCertificateInSeed issues a new client certificate for the account if the certificate in the seed secret
+// needs to be renewed or renewal is forced.
+func (m *SvmManager) ensureClientCertificateInSeed(ctx context.Context, ca *clientCA, username, secretName string, force bool, opts userAndSecretOptions) error {
+	var (
+		now      = time.Now()
+		cfg      = opts.certificateAuthentication
+		existing = &corev1.Secret{}
+	)
+	err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: opts.svmSeedSecretNamespace, Name: secretName}, existing)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %w", opts.svmSeedSecretNamespace, secretName, err)
+	}
+	if err == nil && !force && !ca.needsRenewal(existing.Data[ClientCertificateKey], existing.Data[ClientPrivateKeyKey], username, cfg.RenewBefore.Duration, now) {
+		return nil
+	}
+
+	certPEM, keyPEM, err := ca.issue(username, cfg.Validity.Duration, now)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{
+		"username":           []byte(username),
+		ClientCertificateKey: certPEM,
+		ClientPrivateKeyKey:  keyPEM,
+	}
+	if existing.Name == "" {
+		secret := buildSecret(secretName, opts.svmSeedSecretNamespace, username, "", opts.projectID)
+		secret.StringData = nil
+		secret.Data = data
+		if err := m.protectSeedSecret(secret, opts); err != nil {
+			return err
+		}
+		if err := m.seedClient.Create(ctx, secret); err != nil {
+			return fmt.Errorf("creating secret in seed failed: %w", err)
+		}
+	} else {
+		existing.Data = data
+		existing.StringData = nil
+		if err := m.protectSeedSecret(existing, opts); err != nil {
+			return err
+		}
+		if err := m.seedClient.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update secret in seed: %w", err)
+		}
+	}
+
+	m.log.Info("Issued client certificate", "svm", opts.projectID, "user", username, "secretName", secretName)
+	m.recorder.Normal(ctx, events.ReasonCertificateIssued, events.ActionCreate, "client certificate for account %s on SVM %s issued", username, opts.projectID)
+	return nil
+}
+
+// ensureClientCAOnSVM installs the client CA on the SVM, so ONTAP accepts the client certificates issued from it.
+// Previously installed CAs are kept, certificates issued from them stay valid until they are renewed.
+func (m *SvmManager) ensureClientCAOnSVM(ctx context.Context, ontapClient *ontapv1.Ontap, ca *clientCA, opts userAndSecretOptions) error {
+	params := security.NewSecurityCertificateCollectionGetParamsWithContext(ctx)
+	params.SetSvmUUID(&opts.svmUUID)
+	params.SetType(new(models.SecurityCertificateTypeClientCa))
+	params.SetCommonName(new(clientCACommonName))
+	params.SetFields([]string{"public_certificate"})
+
+	result, err := ontapClient.Security.SecurityCertificateCollectionGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query client CAs of SVM %s: %w", opts.projectID, err)
+	}
+	if result.Payload != nil {
+		for _, installed := range result.Payload.SecurityCertificateResponseInlineRecords {
+			if installed.PublicCertificate == nil {
+				continue
+			}
+			if cert, err := parseCertificate([]byte(*installed.PublicCertificate)); err == nil && bytes.Equal(cert.Raw, ca.cert.Raw) {
+				return nil
+			}
+		}
+	}
+
+	createParams := security.NewSecurityCertificateCreateParamsWithContext(ctx)
+	createParams.SetInfo(&models.SecurityCertificate{
+		Type:              new(models.SecurityCertificateTypeClientCa),
+		PublicCertificate: new(string(ca.certPEM)),
+		Svm:               &models.SecurityCertificateInlineSvm{UUID: new(opts.svmUUID)},
+	})
+	if _, err := ontapClient.Security.SecurityCertificateCreate(createParams, nil); err != nil {
+		return fmt.Errorf("failed to install client CA on SVM %s: %w", opts.projectID, err)
+	}
+
+	m.log.Info("Installed client CA on SVM", "svm", opts.projectID)
+	return nil
+}
+
+// ensureClientAuthenticationEnabled enables client certificate authentication of the web services of the SVM.
+func (m *SvmManager) ensureClientAuthenticationEnabled(ctx context.Context, ontapClient *ontapv1.Ontap, opts userAndSecretOptions) error {
+	params := s_vm.NewWebSvmGetParamsWithContext(ctx)
+	params.SetSvmUUID(opts.svmUUID)
+	params.SetFields([]string{"client_enabled"})
+
+	result, err := ontapClient.SVM.WebSvmGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get web services of SVM %s: %w", opts.projectID, err)
+	}
+	if result.Payload != nil && result.Payload.ClientEnabled != nil && *result.Payload.ClientEnabled {
+		return nil
+	}
+
+	modifyParams := s_vm.NewWebSvmModifyParamsWithContext(ctx)
+	modifyParams.SetSvmUUID(opts.svmUUID)
+	modifyParams.SetInfo(&models.WebSvm{ClientEnabled: new(true)})
+	if _, _, err := ontapClient.SVM.WebSvmModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to enable client authentication on SVM %s: %w", opts.projectID, err)
+	}
+
+	m.log.Info("Enabled client authentication on SVM", "svm", opts.projectID)
+	return nil
+}
+
+// ensureAccountAuthMethod switches the http application of the account to the given authentication method,
+// e.g. when certificate authentication was enabled or disabled for existing accounts.
+func (m *SvmManager) ensureAccountAuthMethod(ctx context.Context, ontapClient *ontapv1.Ontap, username, authMethod string, opts userAndSecretOptions) error {
+	params := security.NewAccountCollectionGetParamsWithContext(ctx)
+	params.SetOwnerUUID(&opts.svmUUID)
+	params.SetName(&username)
+	params.SetFields([]string{"applications"})
+
+	result, err := ontapClient.Security.AccountCollectionGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query ONTAP users: %w", err)
+	}
+	if result.Payload == nil || len(result.Payload.AccountResponseInlineRecords) == 0 {
+		return fmt.Errorf("account %s not found on SVM %s", username, opts.projectID)
+	}
+
+	for _, app := range result.Payload.AccountResponseInlineRecords[0].AccountInlineApplications {
+		if app.Application == nil || *app.Application != models.AccountApplicationApplicationHTTP {
+			continue
+		}
+		if slices.ContainsFunc(app.AuthenticationMethods, func(m *string) bool { return m != nil && *m == authMethod }) {
+			return nil
+		}
+	}
+
+	modifyParams := security.NewAccountModifyParamsWithContext(ctx)
+	modifyParams.SetOwnerUUID(opts.svmUUID)
+	modifyParams.SetName(username)
+	modifyParams.SetInfo(&models.Account{
+		AccountInlineApplications: []*models.AccountApplication{
+			{
+				Application:           new(models.AccountApplicationApplicationHTTP),
+				AuthenticationMethods: []*string{new(authMethod)},
+			},
+		},
+	})
+	if _, err := ontapClient.Security.AccountModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to switch account %s to %s authentication: %w", username, authMethod, err)
+	}
+
+	m.log.Info("Switched authentication method of account", "svm", opts.projectID, "user", username, "authMethod", authMethod)
+	return nil
+}
+
+// checkClientCertificateInSeed returns ErrAlreadyExists if the seed secret contains a client certificate
+// and ErrSeedSecretMissing otherwise.
+func (m *SvmManager) checkClientCertificateInSeed(ctx context.Context, secretName, namespace string) error {
+	secret := &corev1.Secret{}
+	if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrSeedSecretMissing
+		}
+		return fmt.Errorf("failed to get secret %s from namespace %s: %w", secretName, namespace, err)
+	}
+	if len(secret.Data[ClientCertificateKey]) == 0 || len(secret.Data[ClientPrivateKeyKey]) == 0 {
+		return ErrSeedSecretMissing
+	}
+	return ErrAlreadyExists
+}
+
+// renewClientCertificate issues a new client certificate for the account of the shoot, regardless of the
+// expiration of the current one.
+func (m *SvmManager) renewClientCertificate(ctx context.Context, username string, opts CreateSVMOptions) error {
+	ca, err := m.ensureClientCA(ctx, opts.CertificateAuthentication, time.Now())
+	if err != nil {
+		return err
+	}
+	return m.ensureClientCertificateInSeed(ctx, ca, username, opts.seedSecretName(), true, userAndSecretOptions{
+		projectID:                 opts.ProjectID,
+		shootNamespace:            opts.ShootNamespace,
+		svmSeedSecretNamespace:    opts.SvmSeedSecretNamespace,
+		secretOwner:               opts.Owner,
+		seed:                      opts.Seed,
+		certificateAuthentication: opts.CertificateAuthentication,
+		accountRole:               opts.AccountRole,
+		naming:                    opts.Naming,
+		svmAliases:                opts.SVMAliases,
+	})
+}
@@ -0,0 +1,163 @@
+package trident
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+func TestClientCertificateRenewal(t *testing.T) {
+	now := time.Now()
+	ca, err := generateClientCA(365*24*time.Hour, now)
+	require.NoError(t, err)
+	otherCA, err := generateClientCA(365*24*time.Hour, now)
+	require.NoError(t, err)
+
+	certPEM, keyPEM, err := ca.issue("myshoot", 90*24*time.Hour, now)
+	require.NoError(t, err)
+	otherCertPEM, otherKeyPEM, err := otherCA.issue("myshoot", 90*24*time.Hour, now)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		certPEM  []byte
+		keyPEM   []byte
+		username string
+		now      time.Time
+		want     bool
+	}{
+		{name: "valid certificate", certPEM: certPEM, keyPEM: keyPEM, username: "myshoot", now: now},
+		{name: "missing certificate", username: "myshoot", now: now, want: true},
+		{name: "other account", certPEM: certPEM, keyPEM: keyPEM, username: "othershoot", now: now, want: true},
+		{name: "issued by other CA", certPEM: otherCertPEM, keyPEM: otherKeyPEM, username: "myshoot", now: now, want: true},
+		{name: "expires soon", certPEM: certPEM, keyPEM: keyPEM, username: "myshoot", now: now.Add(70 * 24 * time.Hour), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ca.needsRenewal(tt.certPEM, tt.keyPEM, tt.username, 30*24*time.Hour, tt.now))
+		})
+	}
+
+	t.Run("certificate does not outlive the CA", func(t *testing.T) {
+		certPEM, _, err := ca.issue("myshoot", 2*365*24*time.Hour, now)
+		require.NoError(t, err)
+		cert, err := parseCertificate(certPEM)
+		require.NoError(t, err)
+		assert.Equal(t, ca.cert.NotAfter, cert.NotAfter)
+	})
+}
+
+func TestEnsureCertificateUserState(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	var (
+		secretKey = client.ObjectKey{Namespace: "shoot--proj--myshoot", Name: "proj-1-proj--myshoot-credentials"}
+		certAuth  = &config.CertificateAuthenticationConfig{
+			CASecretNamespace: "garden",
+			CAValidity:        metav1.Duration{Duration: 365 * 24 * time.Hour},
+			Validity:          metav1.Duration{Duration: 90 * 24 * time.Hour},
+			RenewBefore:       metav1.Duration{Duration: 30 * 24 * time.Hour},
+		}
+		opts = func(k8s client.Client) userAndSecretOptions {
+			return userAndSecretOptions{
+				projectID:                 "proj-1",
+				shootNamespace:            "shoot--proj--myshoot",
+				svmSeedSecretNamespace:    "shoot--proj--myshoot",
+				seedClient:                k8s,
+				svmUUID:                   "svm-uuid-1",
+				certificateAuthentication: certAuth,
+			}
+		}
+		mockCertificateSetup = func(mc *mockOntapClient) {
+			mc.security.On("SecurityCertificateCollectionGet", mock.Anything, mock.Anything).
+				Return(&security.SecurityCertificateCollectionGetOK{Payload: &models.SecurityCertificateResponse{}}, nil)
+			mc.security.On("SecurityCertificateCreate", mock.Anything, mock.Anything).
+				Return(&security.SecurityCertificateCreateCreated{}, nil)
+			mc.svm.On("WebSvmGet", mock.Anything, mock.Anything).
+				Return(&s_vm.WebSvmGetOK{Payload: &models.WebSvm{ClientEnabled: new(false)}}, nil)
+			mc.svm.On("WebSvmModify", mock.Anything, mock.Anything).
+				Return(&s_vm.WebSvmModifyOK{}, nil, nil)
+		}
+	)
+
+	t.Run("account with certificate authentication is created", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mockCertificateSetup(mc)
+		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{}}, nil)
+		mc.security.On("AccountCreate", mock.Anything, mock.Anything).
+			Return(&security.AccountCreateCreated{}, nil)
+
+		k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		require.NoError(t, m.validateAndEnsureCompleteUserState(ctx, mc.client, opts(k8s)))
+
+		mc.security.AssertCalled(t, "AccountCreate", mock.MatchedBy(func(p *security.AccountCreateParams) bool {
+			methods := p.Info.AccountInlineApplications[0].AuthenticationMethods
+			return p.Info.Password == nil && len(methods) == 1 && *methods[0] == authMethodCertificate
+		}), mock.Anything)
+		mc.security.AssertCalled(t, "SecurityCertificateCreate", mock.Anything, mock.Anything)
+		mc.svm.AssertCalled(t, "WebSvmModify", mock.Anything, mock.Anything)
+
+		caSecret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, client.ObjectKey{Namespace: "garden", Name: clientCASecretName}, caSecret))
+		ca, err := parseClientCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+		require.NoError(t, err)
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, secretKey, secret))
+		assert.NotContains(t, secret.Data, "password")
+		assert.False(t, ca.needsRenewal(secret.Data[ClientCertificateKey], secret.Data[ClientPrivateKeyKey], "myshoot", certAuth.RenewBefore.Duration, time.Now()))
+	})
+
+	t.Run("existing password account is switched to certificate authentication", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mockCertificateSetup(mc)
+		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+				AccountResponseInlineRecords: []*models.Account{{
+					Name:                      new("myshoot"),
+					Comment:                   new(NewOwnership("", "shoot--proj--myshoot", "proj-1").Comment()),
+					AccountInlineApplications: []*models.AccountApplication{{Application: new("http"), AuthenticationMethods: []*string{new("password")}}},
+				}},
+			}}, nil)
+		mc.security.On("AccountModify", mock.Anything, mock.Anything).Return(&security.AccountModifyOK{}, nil)
+
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("old-pw")},
+		}).Build()
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		require.NoError(t, m.validateAndEnsureCompleteUserState(ctx, mc.client, opts(k8s)))
+
+		mc.security.AssertCalled(t, "AccountModify", mock.MatchedBy(func(p *security.AccountModifyParams) bool {
+			return len(p.Info.AccountInlineApplications) == 1 && *p.Info.AccountInlineApplications[0].AuthenticationMethods[0] == authMethodCertificate
+		}), mock.Anything)
+		mc.security.AssertNotCalled(t, "AccountCreate", mock.Anything, mock.Anything)
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, secretKey, secret))
+		assert.NotContains(t, secret.Data, "password")
+		assert.NotEmpty(t, secret.Data[ClientCertificateKey])
+		assert.NotEmpty(t, secret.Data[ClientPrivateKeyKey])
+	})
+}
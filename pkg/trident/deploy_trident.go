@@ -1,20 +1,30 @@
 package trident
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"text/template"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
 
 	"github.com/gardener/gardener/pkg/utils/managedresources"
 	"github.com/go-logr/logr"
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
 	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/cwnps"
 	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/secrets"
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/snapshots"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
 	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,14 +36,16 @@ const (
 	// Constants for directory names
 	backendConfigFilename  = "backend-config.yaml"
 	svmShootSecretFilename = "svm-shoot-secret.yaml"
+	replicaSecretFilename  = "svm-shoot-secret-replica.yaml"
 	cwnpFileName           = "cwnp.yaml"
+	snapshotsFilename      = "snapshots.yaml"
 
-	tridentCRDsName        string = "trident-crds"
-	tridentInitMR          string = "trident-init"
-	tridentBackendsMR      string = "trident-backends"
-	tridentSvmSecret       string = "trident-svm-secret"
-	tridentCwnp            string = "trident-cwnp"
-	svmSeedSecretNamespace string = "kube-system"
+	tridentCRDsName   string = "trident-crds"
+	tridentInitMR     string = "trident-init"
+	tridentBackendsMR string = "trident-backends"
+	tridentSvmSecret  string = "trident-svm-secret"
+	tridentCwnp       string = "trident-cwnp"
+	tridentSnapshots  string = "trident-snapshots"
 
 	defaultChartPath = "charts/trident"
 )
@@ -46,25 +58,72 @@ var (
 	backendPath     = filepath.Join(resourcesPath, "backends")
 	svmSecretsPath  = filepath.Join(resourcesPath, "secrets")
 	cwnpPath        = filepath.Join(resourcesPath, "cwnps")
+	snapshotsPath   = filepath.Join(resourcesPath, "snapshots")
 
 	tridentResources = []tridentResource{
 		{name: tridentCRDsName, path: crdPath, waitForHealthy: true, keepObjects: true},
 		{name: tridentBackendsMR, path: backendPath, waitForHealthy: false, keepObjects: true},
 		{name: tridentSvmSecret, path: svmSecretsPath, waitForHealthy: false, keepObjects: false},
 		{name: tridentCwnp, path: cwnpPath, waitForHealthy: false, keepObjects: false},
+		{name: tridentSnapshots, path: snapshotsPath, waitForHealthy: false, keepObjects: false},
 		{name: tridentInitMR, path: tridentInitPath, waitForHealthy: false, keepObjects: true},
 	}
 )
 
 type DeployTridentValues struct {
-	Namespace        string
-	ProjectId        string
-	SeedsecretName   *string
-	SvmIpAddresses   ontapv1alpha1.SvmIpaddresses
-	Username         string
-	Password         string
-	WebhookNamespace string
-	WebhookCABundle  string
+	Namespace string
+	ProjectId string
+	// BackendName is the name of the Trident backend, defaults to ontap-<ProjectId>
+	BackendName string
+	// BackendConfigName is the name of the TridentBackendConfig, defaults to ontap-<ProjectId>-backend
+	BackendConfigName string
+	SeedsecretName    *string
+	SvmIpAddresses    ontapv1alpha1.SvmIpaddresses
+	// SVMName is the name of the SVM of the partner site after a MetroCluster switchover, the backends are re-pointed
+	// to it as its name differs from the SVM Trident derived from the management LIF before
+	SVMName string
+	// StoragePrefix is the prefix of the volumes of the shoot, the default of Trident is not rendered
+	StoragePrefix string
+	// Protocols select the TridentBackendConfigs, StorageClasses and CWNP ports, defaults to NVMe
+	Protocols []ontapv1alpha1.Protocol
+	// StorageClasses are the StorageClasses of the shoot, the built-in StorageClasses are deployed if empty
+	StorageClasses []ontapv1alpha1.StorageClass
+	// QoSTiers are the QoS tiers the StorageClasses reference, their QoS policy groups are named after the SVM
+	QoSTiers []config.QoSTier
+	// Snapshots configure the snapshot policies of the backends and the VolumeSnapshotClass, which is only deployed if set
+	Snapshots *ontapv1alpha1.SnapshotConfig
+	// KubernetesVersion is the version of the shoot, it selects the version of the groupsnapshot API
+	KubernetesVersion string
+	// ExportPolicy is the NFS export policy of the shoot, it is managed by EnsureExportPolicy
+	ExportPolicy string
+	Username     string
+	Password     string
+	// ClientCertificate and ClientPrivateKey are PEM encoded, they replace the password with certificate authentication
+	ClientCertificate string
+	ClientPrivateKey  string
+	WebhookNamespace  string
+	WebhookCABundle   string
+	// Replica are the values of the backends of the secondary SVM volumes are mirrored to, they are only deployed if set
+	Replica *ReplicaValues
+}
+
+// ReplicaValues are the values of the backends of the secondary SVM of the project.
+type ReplicaValues struct {
+	SeedsecretName string
+	SvmIpAddresses ontapv1alpha1.SvmIpaddresses
+	// StorageClass is the name of the StorageClass of the NVMe backend, the StorageClass of the iSCSI backend gets
+	// the suffix -iscsi
+	StorageClass string
+	Username     string
+	Password     string
+	// ClientCertificate and ClientPrivateKey are PEM encoded, they replace the password with certificate authentication
+	ClientCertificate string
+	ClientPrivateKey  string
+}
+
+// tridentInit are the values the bundle of the Trident operator is rendered with.
+type tridentInit struct {
+	GroupSnapshots bool
 }
 
 type tridentResource struct {
@@ -74,8 +133,44 @@ type tridentResource struct {
 	keepObjects    bool
 }
 
-// DeployTrident deploys Trident using the provided values and resources.
-func DeployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client, tridentValues DeployTridentValues) error {
+// DeployTrident deploys Trident using the provided values and resources, it returns whether any of the ManagedResources
+// changed.
+func DeployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client, tridentValues DeployTridentValues) (bool, error) {
+	before, err := managedResourceSecrets(ctx, k8sClient, tridentValues.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if err := deployTrident(ctx, log, k8sClient, tridentValues); err != nil {
+		return false, err
+	}
+	after, err := managedResourceSecrets(ctx, k8sClient, tridentValues.Namespace)
+	if err != nil {
+		return false, err
+	}
+	return !maps.EqualFunc(before, after, slices.Equal), nil
+}
+
+// managedResourceSecrets returns the secrets referenced by the ManagedResources of Trident in the given namespace. The
+// names of the secrets contain a hash of their data, they change with the deployed resources.
+func managedResourceSecrets(ctx context.Context, k8sClient client.Client, namespace string) (map[string][]string, error) {
+	refs := map[string][]string{}
+	for _, resource := range tridentResources {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: resource.name}, mr); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get managed resource %s: %w", resource.name, err)
+		}
+		for _, ref := range mr.Spec.SecretRefs {
+			refs[resource.name] = append(refs[resource.name], ref.Name)
+		}
+	}
+	return refs, nil
+}
+
+// deployTrident deploys the ManagedResources of Trident.
+func deployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client, tridentValues DeployTridentValues) error {
 	for _, resource := range tridentResources {
 		log.Info("loading YAML files for resource", "resource", resource.name)
 		yamlBytes, err := loadYAMLFiles(resource.path)
@@ -84,37 +179,39 @@ func DeployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client
 		}
 		log.Info("before switch case in deploy trident", "resourcename", resource.name)
 
-		// FIXME Will be changed to go template
 		switch resource.name {
 		case tridentBackendsMR:
-			key := backendConfigFilename
-			config, ok := yamlBytes[key]
-			if !ok {
-				return fmt.Errorf("backend config file '%s' not found in loaded YAMLs for %s", key, resource.name)
+			backendsData := tridentBackends(tridentValues)
+			rendered, err := backends.Parse(backendsData)
+			if err != nil {
+				return err
+			}
+			resourceToDeploy := map[string][]byte{
+				backendConfigFilename: []byte(rendered),
+			}
+			log.Info("templated backends", "resource", resource.name, "input", backendsData, "output", rendered)
+			err = deployResources(ctx, log, k8sClient, tridentValues.Namespace, resource.name, resourceToDeploy, resource.waitForHealthy, resource.keepObjects)
+			if err != nil {
+				return err
 			}
-			configStr := string(config)
-			configStr = strings.ReplaceAll(configStr, "${PROJECT_ID}", tridentValues.ProjectId)
-			configStr = strings.ReplaceAll(configStr, "${SECRET_NAME}", *tridentValues.SeedsecretName)
-			configStr = strings.ReplaceAll(configStr, "${MANAGEMENT_LIF_IP}", tridentValues.SvmIpAddresses.ManagementLif)
-			yamlBytes[key] = []byte(configStr)
-			log.Info("Templated backend config", "resource", resource.name)
+			continue
 
 		case tridentSvmSecret:
-			secretsData := secrets.Secrets{
-				Name:      *tridentValues.SeedsecretName,
-				Namespace: "kube-system",
-				Project:   tridentValues.ProjectId,
-				Username:  tridentValues.Username,
-				Password:  tridentValues.Password,
-			}
-			rendered, err := secrets.Parse(secretsData)
+			rendered, err := svmSecret(*tridentValues.SeedsecretName, tridentValues.ProjectId, tridentValues.Username, tridentValues.Password, tridentValues.ClientCertificate, tridentValues.ClientPrivateKey)
 			if err != nil {
 				return err
 			}
 			resourceToDeploy := map[string][]byte{
 				svmShootSecretFilename: []byte(rendered),
 			}
-			log.Info("templated secret", "resource", resource.name, "input", secretsData, "output", rendered)
+			if r := tridentValues.Replica; r != nil {
+				replica, err := svmSecret(r.SeedsecretName, tridentValues.ProjectId, r.Username, r.Password, r.ClientCertificate, r.ClientPrivateKey)
+				if err != nil {
+					return err
+				}
+				resourceToDeploy[replicaSecretFilename] = []byte(replica)
+			}
+			log.Info("templated secrets", "resource", resource.name, "secrets", len(resourceToDeploy))
 			err = deployResources(ctx, log, k8sClient, tridentValues.Namespace, resource.name, resourceToDeploy, resource.waitForHealthy, resource.keepObjects)
 			if err != nil {
 				return err
@@ -135,6 +232,11 @@ func DeployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client
 			cwnp := cwnps.CWNP{
 				ManagementLif: tridentValues.SvmIpAddresses.ManagementLif,
 				DataLifs:      tridentValues.SvmIpAddresses.DataLifs,
+				DataPorts:     dataPorts(tridentValues.Protocols),
+			}
+			if r := tridentValues.Replica; r != nil {
+				cwnp.ReplicaManagementLif = r.SvmIpAddresses.ManagementLif
+				cwnp.DataLifs = append(slices.Clone(cwnp.DataLifs), r.SvmIpAddresses.DataLifs...)
 			}
 			rendered, err := cwnps.ParseCWNP(cwnp)
 			if err != nil {
@@ -150,6 +252,53 @@ func DeployTrident(ctx context.Context, log logr.Logger, k8sClient client.Client
 			}
 			continue
 
+		case tridentInitMR:
+			// the TridentOrchestrator only enables group snapshots on request
+			initData := tridentInit{GroupSnapshots: tridentValues.Snapshots != nil && tridentValues.Snapshots.GroupSnapshots}
+			rendered, err := renderTemplates(yamlBytes, initData)
+			if err != nil {
+				return err
+			}
+			err = deployResources(ctx, log, k8sClient, tridentValues.Namespace, resource.name, rendered, resource.waitForHealthy, resource.keepObjects)
+			if err != nil {
+				return err
+			}
+			continue
+
+		case tridentSnapshots:
+			// the snapshot CRDs are not present in all shoots, the VolumeSnapshotClass is only deployed on request
+			if tridentValues.Snapshots == nil {
+				if err := managedresources.Delete(ctx, k8sClient, tridentValues.Namespace, resource.name, false); err != nil {
+					return fmt.Errorf("failed to delete managed resource %s: %w", resource.name, err)
+				}
+				continue
+			}
+			snapshotsData := snapshots.Snapshots{
+				VolumeSnapshotClass: tridentValues.Snapshots.VolumeSnapshotClass,
+				DeletionPolicy:      tridentValues.Snapshots.DeletionPolicy,
+				InPlaceRestore:      tridentValues.Snapshots.InPlaceRestore,
+			}
+			if tridentValues.Snapshots.GroupSnapshots {
+				apiVersion, err := groupSnapshotAPIVersion(tridentValues.KubernetesVersion)
+				if err != nil {
+					return err
+				}
+				snapshotsData.VolumeGroupSnapshotClass = tridentValues.Snapshots.VolumeGroupSnapshotClass
+				snapshotsData.GroupSnapshotAPIVersion = apiVersion
+			}
+			rendered, err := snapshots.Parse(snapshotsData)
+			if err != nil {
+				return err
+			}
+			resourceToDeploy := map[string][]byte{
+				snapshotsFilename: []byte(rendered),
+			}
+			log.Info("templated snapshot classes", "resource", resource.name, "input", snapshotsData, "output", rendered)
+			err = deployResources(ctx, log, k8sClient, tridentValues.Namespace, resource.name, resourceToDeploy, resource.waitForHealthy, resource.keepObjects)
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
 		err = deployResources(ctx, log, k8sClient, tridentValues.Namespace, resource.name, yamlBytes, resource.waitForHealthy, resource.keepObjects)
@@ -182,6 +331,23 @@ func DeleteManagedResources(ctx context.Context, log logr.Logger, client client.
 	return nil
 }
 
+// svmSecret renders the credentials secret of the backends of an SVM in the shoot.
+func svmSecret(name, projectID, username, password, clientCertificate, clientPrivateKey string) (string, error) {
+	secretsData := secrets.Secrets{
+		Name:      name,
+		Namespace: "kube-system",
+		Project:   projectID,
+		Username:  username,
+		Password:  password,
+	}
+	if clientCertificate != "" {
+		// trident expects the client certificate and key base64 encoded
+		secretsData.ClientCertificate = base64.StdEncoding.EncodeToString([]byte(clientCertificate))
+		secretsData.ClientPrivateKey = base64.StdEncoding.EncodeToString([]byte(clientPrivateKey))
+	}
+	return secrets.Parse(secretsData)
+}
+
 // loadYAMLFiles walks the given directory path and reads all YAML files,
 // returning them in a map where the key is the relative path with '/' replaced by '.'.
 func loadYAMLFiles(dirPath string) (map[string][]byte, error) {
@@ -224,6 +390,23 @@ func loadYAMLFiles(dirPath string) (map[string][]byte, error) {
 	return result, nil
 }
 
+// renderTemplates executes each of the given files as a template with the given values.
+func renderTemplates(files map[string][]byte, values any) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(files))
+	for name, data := range files {
+		tmpl, err := template.New(name).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, values); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		result[name] = rendered.Bytes()
+	}
+	return result, nil
+}
+
 // deployResources deploys the provided YAML data as a managed resource.
 func deployResources(
 	ctx context.Context,
@@ -256,7 +439,10 @@ func deployResources(
 		return fmt.Errorf("failed to create managed resource %s: %w", resourceName, err)
 	}
 	if waitForHealthy {
-		if err := managedresources.WaitUntilHealthyAndNotProgressing(ctx, k8sClient, namespace, resourceName); err != nil {
+		waitCtx, span := tracing.Start(ctx, "WaitForManagedResource", attribute.String("managedresource", resourceName))
+		err := managedresources.WaitUntilHealthyAndNotProgressing(waitCtx, k8sClient, namespace, resourceName)
+		tracing.End(span, err)
+		if err != nil {
 			return fmt.Errorf("failed while waiting for Trident CRDs managed resource: %w", err)
 		}
 	}
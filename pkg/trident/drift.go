@@ -0,0 +1,389 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// DriftType describes in which way an SVM differs from its desired state.
+type DriftType string
+
+const (
+	// DriftSVMMissing means no SVM exists for the project.
+	DriftSVMMissing DriftType = "SVMMissing"
+	// DriftSVMNotRunning means the SVM exists but is not running.
+	DriftSVMNotRunning DriftType = "SVMNotRunning"
+	// DriftNVMeDisabled means the NVMe protocol of the SVM is disabled.
+	DriftNVMeDisabled DriftType = "NVMeDisabled"
+	// DriftProtocolDisabled means another protocol of the TridentConfig is disabled on the SVM.
+	DriftProtocolDisabled DriftType = "ProtocolDisabled"
+	// DriftLIFMissing means a data or management LIF is missing.
+	DriftLIFMissing DriftType = "LIFMissing"
+	// DriftLIFIPMismatch means a LIF exists with a different IP address, this drift is never repaired automatically.
+	DriftLIFIPMismatch DriftType = "LIFIPMismatch"
+	// DriftAccountMissing means the SVM account of the shoot is missing.
+	DriftAccountMissing DriftType = "AccountMissing"
+	// DriftAccountLocked means the SVM account of the shoot is locked.
+	DriftAccountLocked DriftType = "AccountLocked"
+	// DriftSeedSecretMissing means the credentials secret in the seed is missing.
+	DriftSeedSecretMissing DriftType = "SeedSecretMissing"
+	// DriftNotOwned means the SVM or account was not created by the extension in this seed, it is never modified.
+	DriftNotOwned DriftType = "NotOwned"
+)
+
+// Drift is a single difference between the actual and the desired state of an SVM.
+type Drift struct {
+	Type DriftType
+	// Object is the name of the drifted object, e.g. the LIF or account name
+	Object string
+	// Message describes the drift
+	Message string
+}
+
+// Repairable returns whether the drift can be repaired automatically.
+func (d Drift) Repairable() bool {
+	return d.Type != DriftLIFIPMismatch && d.Type != DriftNotOwned
+}
+
+// DriftReport is the result of a drift detection for one shoot.
+type DriftReport struct {
+	SVMName string
+	Drifts  []Drift
+
+	svmUUID     string
+	ontapClient *ontapv1.Ontap
+}
+
+// DetectDrift compares the actual state of the SVM, its LIFs and the account of the shoot with the desired state.
+// In contrast to EnsureCompleteSVM it does not modify anything.
+func (m *SvmManager) DetectDrift(ctx context.Context, opts CreateSVMOptions) (_ *DriftReport, err error) {
+	ctx, span := tracing.Start(ctx, "DetectDrift")
+	defer func() { tracing.End(span, err) }()
+
+	report := &DriftReport{SVMName: opts.ProjectID}
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		if !errors.Is(err, ErrSvmNotFound) {
+			return nil, fmt.Errorf("failed to check existing SVM: %w", err)
+		}
+
+		candidate, err := m.findNotRunningSVM(ctx, opts.ProjectID, opts.SVMAliases...)
+		if err != nil {
+			return nil, err
+		}
+		if candidate == nil {
+			report.Drifts = append(report.Drifts, Drift{
+				Type:    DriftSVMMissing,
+				Object:  opts.ProjectID,
+				Message: fmt.Sprintf("SVM %s does not exist", opts.ProjectID),
+			})
+			return report, nil
+		}
+
+		report.svmUUID = candidate.uuid
+		report.ontapClient = candidate.client
+		if _, err := checkSVMOwnership(opts.ProjectID, candidate.comment, opts.svmNames()...); err != nil {
+			report.Drifts = append(report.Drifts, Drift{Type: DriftNotOwned, Object: opts.ProjectID, Message: err.Error()})
+			return report, nil
+		}
+		report.Drifts = append(report.Drifts, Drift{
+			Type:    DriftSVMNotRunning,
+			Object:  opts.ProjectID,
+			Message: fmt.Sprintf("SVM %s is in state %s", opts.ProjectID, candidate.state),
+		})
+		return report, nil
+	}
+
+	report.svmUUID = *svmUUID
+	report.ontapClient = ontapClient
+
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(*svmUUID)
+	svmInfo, err := ontapClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM details: %w", err)
+	}
+	if svmInfo.Payload != nil {
+		if _, err := checkSVMOwnership(opts.ProjectID, svmInfo.Payload.Comment, opts.svmNames()...); err != nil {
+			report.Drifts = append(report.Drifts, Drift{Type: DriftNotOwned, Object: opts.ProjectID, Message: err.Error()})
+			return report, nil
+		}
+	}
+	for _, p := range disabledProtocols(svmInfo.Payload, opts.svmProtocols()) {
+		if p == ontapv1alpha1.ProtocolNVMe {
+			report.Drifts = append(report.Drifts, Drift{
+				Type:    DriftNVMeDisabled,
+				Object:  opts.ProjectID,
+				Message: fmt.Sprintf("NVMe is not enabled on SVM %s", opts.ProjectID),
+			})
+			continue
+		}
+		report.Drifts = append(report.Drifts, Drift{
+			Type:    DriftProtocolDisabled,
+			Object:  string(p),
+			Message: fmt.Sprintf("%s is not enabled on SVM %s", p, opts.ProjectID),
+		})
+	}
+
+	interfaces, err := m.getExistingNetworkInterfaces(ctx, ontapClient, *svmUUID)
+	if err != nil {
+		return nil, err
+	}
+	managementLIF := opts.Naming.ManagementLIFName(opts.ProjectID)
+	expectedLIFs := map[string]string{managementLIF: opts.SvmIpaddresses.ManagementLif}
+	lifAliases := map[string][]string{managementLIF: opts.Naming.ManagementLIFAliases(opts.ProjectID)}
+	for i, ip := range opts.SvmIpaddresses.DataLifs {
+		name := opts.Naming.DataLIFName(opts.ProjectID, i)
+		expectedLIFs[name] = ip
+		lifAliases[name] = opts.Naming.DataLIFAliases(opts.ProjectID, i)
+	}
+	for _, lifName := range slices.Sorted(maps.Keys(expectedLIFs)) {
+		expectedIP := expectedLIFs[lifName]
+		_, existingIP, ok := lookupLIF(interfaces, lifName, lifAliases[lifName])
+		switch {
+		case !ok:
+			report.Drifts = append(report.Drifts, Drift{
+				Type:    DriftLIFMissing,
+				Object:  lifName,
+				Message: fmt.Sprintf("LIF %s with ip %s is missing", lifName, expectedIP),
+			})
+		case existingIP != expectedIP:
+			report.Drifts = append(report.Drifts, Drift{
+				Type:    DriftLIFIPMismatch,
+				Object:  lifName,
+				Message: fmt.Sprintf("LIF %s has ip %s instead of %s", lifName, existingIP, expectedIP),
+			})
+		}
+	}
+
+	username, err := getClusterUsername(opts.ShootNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate cluster username: %w", err)
+	}
+	accountParams := security.NewAccountCollectionGetParamsWithContext(ctx)
+	accountParams.SetOwnerUUID(svmUUID)
+	accountParams.SetName(&username)
+	accountParams.SetFields([]string{"name", "locked", "comment"})
+	accounts, err := ontapClient.Security.AccountCollectionGet(accountParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ONTAP users: %w", err)
+	}
+	if accounts.Payload == nil || len(accounts.Payload.AccountResponseInlineRecords) == 0 {
+		report.Drifts = append(report.Drifts, Drift{
+			Type:    DriftAccountMissing,
+			Object:  username,
+			Message: fmt.Sprintf("account %s is missing on SVM %s", username, opts.ProjectID),
+		})
+	} else {
+		account := accounts.Payload.AccountResponseInlineRecords[0]
+		if _, err := checkOwnership("account", username, account.Comment, opts.Seed); err != nil {
+			report.Drifts = append(report.Drifts, Drift{Type: DriftNotOwned, Object: username, Message: err.Error()})
+		} else if account.Locked != nil && *account.Locked {
+			report.Drifts = append(report.Drifts, Drift{
+				Type:    DriftAccountLocked,
+				Object:  username,
+				Message: fmt.Sprintf("account %s is locked on SVM %s", username, opts.ProjectID),
+			})
+		}
+	}
+
+	secretName := opts.seedSecretName()
+	if opts.CertificateAuthentication != nil {
+		err = m.checkClientCertificateInSeed(ctx, secretName, opts.SvmSeedSecretNamespace)
+	} else {
+		_, err = m.checkIfAccountExistsForSvm(ctx, secretName, opts.SvmSeedSecretNamespace)
+	}
+	if !errors.Is(err, ErrAlreadyExists) {
+		if !errors.Is(err, ErrSeedSecretMissing) {
+			return nil, err
+		}
+		report.Drifts = append(report.Drifts, Drift{
+			Type:    DriftSeedSecretMissing,
+			Object:  secretName,
+			Message: fmt.Sprintf("seed secret %s/%s is missing", opts.SvmSeedSecretNamespace, secretName),
+		})
+	}
+
+	return report, nil
+}
+
+// RepairDrift repairs all repairable drifts of the given report, nothing is repaired if the SVM or account is not owned by the extension.
+// Missing objects are recreated with EnsureCompleteSVM, a stopped SVM, a disabled protocol and a locked account are modified in place.
+func (m *SvmManager) RepairDrift(ctx context.Context, opts CreateSVMOptions, report *DriftReport) (err error) {
+	ctx, span := tracing.Start(ctx, "RepairDrift")
+	defer func() { tracing.End(span, err) }()
+
+	for _, drift := range report.Drifts {
+		if drift.Type == DriftNotOwned {
+			return fmt.Errorf("not repairing drift of SVM %s: %s: %w", report.SVMName, drift.Message, ErrNotOwned)
+		}
+	}
+
+	var (
+		errs         []error
+		ensureDrifts []Drift
+	)
+	for _, drift := range report.Drifts {
+		var repairErr error
+		switch drift.Type {
+		case DriftSVMNotRunning:
+			// the SVM of a site whose SVMs were switched over to its partner site must not be started before the
+			// switchback, further drift of the started SVM is detected and repaired in the next run
+			repairErr = m.startSVM(ctx, report)
+		case DriftNVMeDisabled:
+			repairErr = m.modifySVM(ctx, report, &models.Svm{Nvme: &models.SvmInlineNvme{Enabled: new(true)}})
+		case DriftProtocolDisabled:
+			repairErr = m.modifySVM(ctx, report, svmProtocols([]ontapv1alpha1.Protocol{ontapv1alpha1.Protocol(drift.Object)}))
+		case DriftAccountLocked:
+			repairErr = m.unlockAccount(ctx, report, drift.Object)
+		case DriftSVMMissing, DriftLIFMissing, DriftAccountMissing, DriftSeedSecretMissing:
+			ensureDrifts = append(ensureDrifts, drift)
+			continue
+		default:
+			m.log.Info("Not repairing drift", "type", drift.Type, "object", drift.Object, "message", drift.Message)
+			continue
+		}
+		m.recordRepair(ctx, drift, repairErr)
+		if repairErr != nil {
+			errs = append(errs, repairErr)
+		}
+	}
+
+	if len(ensureDrifts) > 0 {
+		ensureErr := m.EnsureCompleteSVM(ctx, opts)
+		if ensureErr != nil {
+			ensureErr = fmt.Errorf("failed to ensure complete SVM %s: %w", opts.ProjectID, ensureErr)
+			errs = append(errs, ensureErr)
+		}
+		for _, drift := range ensureDrifts {
+			m.recordRepair(ctx, drift, ensureErr)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *SvmManager) recordRepair(ctx context.Context, drift Drift, err error) {
+	if err != nil {
+		metrics.SVMDriftRepairs.WithLabelValues(string(drift.Type), "failure").Inc()
+		m.log.Error(err, "failed to repair drift", "type", drift.Type, "object", drift.Object)
+		return
+	}
+	metrics.SVMDriftRepairs.WithLabelValues(string(drift.Type), "success").Inc()
+	m.log.Info("Repaired drift", "type", drift.Type, "object", drift.Object)
+	m.recorder.Normal(ctx, events.ReasonDriftRepaired, events.ActionRepair, "repaired drift %s: %s", drift.Type, drift.Message)
+}
+
+// modifySVM patches the SVM of the report with the given fields.
+func (m *SvmManager) modifySVM(ctx context.Context, report *DriftReport, info *models.Svm) error {
+	if report.ontapClient == nil {
+		return fmt.Errorf("SVM %s was not found", report.SVMName)
+	}
+
+	params := s_vm.NewSvmModifyParamsWithContext(ctx)
+	params.SetUUID(report.svmUUID)
+	params.SetInfo(info)
+
+	if _, _, err := report.ontapClient.SVM.SvmModify(params, nil); err != nil {
+		return fmt.Errorf("failed to modify SVM %s: %w", report.SVMName, err)
+	}
+	return nil
+}
+
+// startSVM starts the SVM of the report unless its site is in a MetroCluster switchover.
+func (m *SvmManager) startSVM(ctx context.Context, report *DriftReport) error {
+	mode, err := metroclusterMode(ctx, report.ontapClient)
+	if err != nil {
+		return err
+	}
+	if isSwitchover(mode) {
+		return fmt.Errorf("not starting SVM %s on site in metrocluster mode %s: %w", report.SVMName, mode, ErrSwitchover)
+	}
+	return m.modifySVM(ctx, report, &models.Svm{State: new(models.SvmStateRunning)})
+}
+
+// unlockAccount unlocks the given account of the SVM of the report.
+func (m *SvmManager) unlockAccount(ctx context.Context, report *DriftReport, username string) error {
+	if report.ontapClient == nil {
+		return fmt.Errorf("SVM %s was not found", report.SVMName)
+	}
+
+	params := security.NewAccountModifyParamsWithContext(ctx)
+	params.SetOwnerUUID(report.svmUUID)
+	params.SetName(username)
+	params.SetInfo(&models.Account{Locked: new(false)})
+
+	if _, err := report.ontapClient.Security.AccountModify(params, nil); err != nil {
+		return fmt.Errorf("failed to unlock account %s on SVM %s: %w", username, report.SVMName, err)
+	}
+	return nil
+}
+
+type svmCandidate struct {
+	uuid    string
+	client  *ontapv1.Ontap
+	state   string
+	comment *string
+}
+
+// findNotRunningSVM searches all clients for an SVM matching svmName (or svmName-mc) regardless of its state, the
+// aliases are tried in the same way after svmName. Like GetSVMByName it also searches excluded clients, the DR SVM is
+// located on one after a failover. It returns nil if no SVM was found.
+func (m *SvmManager) findNotRunningSVM(ctx context.Context, svmName string, aliases ...string) (*svmCandidate, error) {
+	var (
+		errs       []error
+		candidates = map[string]*svmCandidate{}
+	)
+	for _, rc := range m.clients {
+		if rc == nil || rc.SVM == nil {
+			continue
+		}
+
+		params := s_vm.NewSvmCollectionGetParamsWithContext(ctx)
+		params.SetFields([]string{"name", "state", "comment"})
+		svms, err := rc.SVM.SvmCollectionGet(params, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, svm := range svms.Payload.SvmResponseInlineRecords {
+			if svm.Name == nil || svm.UUID == nil {
+				continue
+			}
+			if _, ok := candidates[*svm.Name]; ok {
+				continue
+			}
+			state := "unknown"
+			if svm.State != nil {
+				state = *svm.State
+			}
+			candidates[*svm.Name] = &svmCandidate{uuid: *svm.UUID, client: rc, state: state, comment: svm.Comment}
+		}
+	}
+
+	for _, name := range svmNameCandidates(svmName, aliases...) {
+		if c, ok := candidates[name]; ok {
+			return c, nil
+		}
+	}
+
+	if len(errs) > 0 && len(errs) == len(m.clients) {
+		return nil, fmt.Errorf("failed to list SVMs on all clusters: %w", errors.Join(errs...))
+	}
+	return nil, nil
+}
@@ -0,0 +1,219 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestDetectDrift(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	opts := CreateSVMOptions{
+		ProjectID:      "proj-1",
+		ShootNamespace: "shoot--proj--myshoot",
+		SvmIpaddresses: ontapv1alpha1.SvmIpaddresses{
+			ManagementLif: "10.0.0.1",
+			DataLifs:      []string{"10.0.0.2", "10.0.0.3"},
+		},
+		SvmSeedSecretNamespace: "kube-system",
+	}
+	seedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proj-1-proj--myshoot-credentials", Namespace: "kube-system"},
+		Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("existing-pw")},
+	}
+
+	svmCollection := &s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+		SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+	}}
+	lifs := func(ips map[string]string) *networking.NetworkIPInterfacesGetOK {
+		resp := &networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{}}
+		for name, ip := range ips {
+			address := models.IPAddress(ip)
+			resp.Payload.IPInterfaceResponseInlineRecords = append(resp.Payload.IPInterfaceResponseInlineRecords,
+				&models.IPInterface{Name: new(name), IP: &models.IPInfo{Address: &address}})
+		}
+		return resp
+	}
+	accounts := func(accounts ...*models.Account) *security.AccountCollectionGetOK {
+		return &security.AccountCollectionGetOK{Payload: &models.AccountResponse{AccountResponseInlineRecords: accounts}}
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(mc *mockOntapClient)
+		aliases []string
+		objects []client.Object
+		want    []Drift
+	}{
+		{
+			name: "no drift",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).Return(svmCollection, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running"), Nvme: &models.SvmInlineNvme{Enabled: new(true)}}}, nil)
+				mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+					Return(lifs(map[string]string{"managementlif": "10.0.0.1", "datalif+0": "10.0.0.2", "datalif+1": "10.0.0.3"}), nil)
+				mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+					Return(accounts(&models.Account{Name: new("myshoot"), Locked: new(false)}), nil)
+			},
+			objects: []client.Object{seedSecret},
+			want:    nil,
+		},
+		{
+			name: "missing lif, ip mismatch, locked account and missing secret",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).Return(svmCollection, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running"), Nvme: &models.SvmInlineNvme{Enabled: new(false)}}}, nil)
+				mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+					Return(lifs(map[string]string{"managementlif": "10.0.0.1", "datalif+0": "10.0.0.99"}), nil)
+				mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+					Return(accounts(&models.Account{Name: new("myshoot"), Locked: new(true)}), nil)
+			},
+			want: []Drift{
+				{Type: DriftNVMeDisabled, Object: "proj-1", Message: "NVMe is not enabled on SVM proj-1"},
+				{Type: DriftLIFIPMismatch, Object: "datalif+0", Message: "LIF datalif+0 has ip 10.0.0.99 instead of 10.0.0.2"},
+				{Type: DriftLIFMissing, Object: "datalif+1", Message: "LIF datalif+1 with ip 10.0.0.3 is missing"},
+				{Type: DriftAccountLocked, Object: "myshoot", Message: "account myshoot is locked on SVM proj-1"},
+				{Type: DriftSeedSecretMissing, Object: "proj-1-proj--myshoot-credentials", Message: "seed secret kube-system/proj-1-proj--myshoot-credentials is missing"},
+			},
+		},
+		{
+			name: "missing account",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).Return(svmCollection, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running"), Nvme: &models.SvmInlineNvme{Enabled: new(true)}}}, nil)
+				mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+					Return(lifs(map[string]string{"managementlif": "10.0.0.1", "datalif+0": "10.0.0.2", "datalif+1": "10.0.0.3"}), nil)
+				mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).Return(accounts(), nil)
+			},
+			objects: []client.Object{seedSecret},
+			want: []Drift{
+				{Type: DriftAccountMissing, Object: "myshoot", Message: "account myshoot is missing on SVM proj-1"},
+			},
+		},
+		{
+			name: "svm not created by the extension",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).Return(svmCollection, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running"), Comment: new("storage team test svm")}}, nil)
+			},
+			want: []Drift{
+				{Type: DriftNotOwned, Object: "proj-1", Message: "SVM proj-1 was not created by gardener-extension-ontap: NotOwned"},
+			},
+		},
+		{
+			name: "stopped svm",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+						SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid"), State: new("stopped")}},
+					}}, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("stopped")}}, nil)
+			},
+			want: []Drift{
+				{Type: DriftSVMNotRunning, Object: "proj-1", Message: "SVM proj-1 is in state stopped"},
+			},
+		},
+		{
+			name: "stopped svm with an alias",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+						SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-old-mc"), UUID: new("svm-uuid"), State: new("stopped")}},
+					}}, nil)
+				mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("stopped")}}, nil)
+			},
+			aliases: []string{"proj-old"},
+			want: []Drift{
+				{Type: DriftSVMNotRunning, Object: "proj-1", Message: "SVM proj-1 is in state stopped"},
+			},
+		},
+		{
+			name: "missing svm",
+			mock: func(mc *mockOntapClient) {
+				mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+					Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{}}, nil)
+			},
+			want: []Drift{
+				{Type: DriftSVMMissing, Object: "proj-1", Message: "SVM proj-1 does not exist"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newMockOntapClient()
+			tt.mock(mc)
+			k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+			svmOpts := opts
+			svmOpts.SVMAliases = tt.aliases
+			report, err := m.DetectDrift(ctx, svmOpts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, report.Drifts)
+
+			// detection must never modify anything
+			mc.svm.AssertNotCalled(t, "SvmModify", mock.Anything, mock.Anything)
+			mc.security.AssertNotCalled(t, "AccountModify", mock.Anything, mock.Anything)
+			mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRepairDrift(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newMockOntapClient()
+	mc.svm.On("SvmModify", mock.Anything, mock.Anything).Return(&s_vm.SvmModifyOK{}, nil, nil)
+	mc.security.On("AccountModify", mock.Anything, mock.Anything).Return(&security.AccountModifyOK{}, nil)
+
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+	report := &DriftReport{
+		SVMName: "proj-1",
+		Drifts: []Drift{
+			{Type: DriftNVMeDisabled, Object: "proj-1"},
+			{Type: DriftLIFIPMismatch, Object: "datalif+0"},
+			{Type: DriftAccountLocked, Object: "myshoot"},
+		},
+		svmUUID:     "svm-uuid",
+		ontapClient: mc.client,
+	}
+
+	err := m.RepairDrift(ctx, CreateSVMOptions{ProjectID: "proj-1"}, report)
+	require.NoError(t, err)
+
+	mc.svm.AssertCalled(t, "SvmModify", mock.MatchedBy(func(p *s_vm.SvmModifyParams) bool {
+		return p.UUID == "svm-uuid" && p.Info.Nvme != nil && *p.Info.Nvme.Enabled
+	}), mock.Anything)
+	mc.security.AssertCalled(t, "AccountModify", mock.MatchedBy(func(p *security.AccountModifyParams) bool {
+		return p.Name == "myshoot" && p.OwnerUUID == "svm-uuid" && !*p.Info.Locked
+	}), mock.Anything)
+	// ip mismatches are never repaired
+	mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
+	mc.networking.AssertNotCalled(t, "NetworkIPInterfaceModify", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,113 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/metal-stack/ontap-go/api/client/n_a_s"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// ExportPolicyName returns the name of the NFS export policy of the shoot on the SVM of its project.
+func ExportPolicyName(shootNamespace string) string {
+	return shootNamespace
+}
+
+// exportRules returns a rule for each node network which allows NFS access with AUTH_SYS, root is not squashed
+// because the volumes are mounted by the kubelet.
+func exportRules(nodeCIDRs []string) []*models.ExportRules {
+	var rules []*models.ExportRules
+	for _, cidr := range nodeCIDRs {
+		sys := new(models.ExportAuthenticationFlavorSys)
+		rules = append(rules, &models.ExportRules{
+			ExportRulesInlineClients:   []*models.ExportClients{{Match: new(cidr)}},
+			Protocols:                  []*string{new("nfs")},
+			ExportRulesInlineRoRule:    []*models.ExportAuthenticationFlavor{sys},
+			ExportRulesInlineRwRule:    []*models.ExportAuthenticationFlavor{sys},
+			ExportRulesInlineSuperuser: []*models.ExportAuthenticationFlavor{sys},
+		})
+	}
+	return rules
+}
+
+// exportRuleClients returns the sorted client matches of the rules.
+func exportRuleClients(rules []*models.ExportRules) []string {
+	var clients []string
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		for _, c := range rule.ExportRulesInlineClients {
+			if c != nil && c.Match != nil {
+				clients = append(clients, *c.Match)
+			}
+		}
+	}
+	slices.Sort(clients)
+	return clients
+}
+
+// EnsureExportPolicy creates the NFS export policy of the shoot on its SVM and replaces its rules if the node networks
+// of the shoot changed. Volumes of the ontap-nas backend of the shoot are only exported to its nodes.
+func (m *SvmManager) EnsureExportPolicy(ctx context.Context, opts CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureExportPolicy")
+	defer func() { tracing.End(span, err) }()
+
+	if len(opts.NodeCIDRs) == 0 {
+		return fmt.Errorf("shoot %s has no node network, its NFS exports cannot be restricted", opts.ShootNamespace)
+	}
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	name := ExportPolicyName(opts.ShootNamespace)
+	getParams := n_a_s.NewExportPolicyCollectionGetParamsWithContext(ctx)
+	getParams.SetSvmUUID(svmUUID)
+	getParams.SetName(&name)
+	getParams.SetFields([]string{"id", "name", "rules"})
+
+	result, err := ontapClient.Nas.ExportPolicyCollectionGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get export policy %s: %w", name, err)
+	}
+
+	if result.Payload == nil || len(result.Payload.ExportPolicyResponseInlineRecords) == 0 {
+		m.log.Info("Creating export policy", "svm", opts.ProjectID, "policy", name, "clients", opts.NodeCIDRs)
+		createParams := n_a_s.NewExportPolicyCreateParamsWithContext(ctx)
+		createParams.SetInfo(&models.ExportPolicy{
+			Name:                    new(name),
+			Svm:                     &models.ExportPolicyInlineSvm{UUID: svmUUID},
+			ExportPolicyInlineRules: exportRules(opts.NodeCIDRs),
+		})
+		if _, err := ontapClient.Nas.ExportPolicyCreate(createParams, nil); err != nil {
+			return fmt.Errorf("failed to create export policy %s on SVM %s: %w", name, opts.ProjectID, err)
+		}
+		m.recorder.Normal(ctx, events.ReasonExportPolicyCreated, events.ActionCreate, "export policy %s for %v created on SVM %s", name, opts.NodeCIDRs, opts.ProjectID)
+		return nil
+	}
+
+	policy := result.Payload.ExportPolicyResponseInlineRecords[0]
+	want := slices.Sorted(slices.Values(opts.NodeCIDRs))
+	if slices.Equal(exportRuleClients(policy.ExportPolicyInlineRules), want) {
+		return nil
+	}
+	if policy.ID == nil {
+		return fmt.Errorf("export policy %s on SVM %s has no id", name, opts.ProjectID)
+	}
+
+	m.log.Info("Replacing rules of export policy", "svm", opts.ProjectID, "policy", name, "clients", opts.NodeCIDRs)
+	modifyParams := n_a_s.NewExportPolicyModifyParamsWithContext(ctx)
+	modifyParams.SetID(*policy.ID)
+	modifyParams.SetInfo(&models.ExportPolicy{ExportPolicyInlineRules: exportRules(opts.NodeCIDRs)})
+	if _, err := ontapClient.Nas.ExportPolicyModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to update export policy %s on SVM %s: %w", name, opts.ProjectID, err)
+	}
+	m.recorder.Normal(ctx, events.ReasonExportPolicyUpdated, events.ActionUpdate, "export policy %s on SVM %s changed to %v", name, opts.ProjectID, opts.NodeCIDRs)
+	return nil
+}
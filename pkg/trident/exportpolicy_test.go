@@ -0,0 +1,78 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/n_a_s"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureExportPolicy(t *testing.T) {
+	ctx := context.Background()
+	opts := CreateSVMOptions{
+		ProjectID:      "proj-1",
+		ShootNamespace: "shoot--proj--myshoot",
+		NodeCIDRs:      []string{"10.0.0.0/16", "fd00::/64"},
+	}
+
+	newClient := func(policies ...*models.ExportPolicy) *mockOntapClient {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		mc.nas.On("ExportPolicyCollectionGet", mock.MatchedBy(func(p *n_a_s.ExportPolicyCollectionGetParams) bool {
+			return *p.Name == "shoot--proj--myshoot" && *p.SvmUUID == "svm-uuid"
+		}), mock.Anything).
+			Return(&n_a_s.ExportPolicyCollectionGetOK{Payload: &models.ExportPolicyResponse{ExportPolicyResponseInlineRecords: policies}}, nil)
+		return mc
+	}
+
+	t.Run("creates missing policy", func(t *testing.T) {
+		mc := newClient()
+		mc.nas.On("ExportPolicyCreate", mock.Anything, mock.Anything).Return(&n_a_s.ExportPolicyCreateCreated{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureExportPolicy(ctx, opts))
+
+		p := mc.nas.Calls[1].Arguments[0].(*n_a_s.ExportPolicyCreateParams)
+		assert.Equal(t, "shoot--proj--myshoot", *p.Info.Name)
+		assert.Equal(t, "svm-uuid", *p.Info.Svm.UUID)
+		assert.Equal(t, []string{"10.0.0.0/16", "fd00::/64"}, exportRuleClients(p.Info.ExportPolicyInlineRules))
+	})
+
+	t.Run("no-op when the rules match", func(t *testing.T) {
+		mc := newClient(&models.ExportPolicy{ID: new(int64(42)), ExportPolicyInlineRules: exportRules([]string{"fd00::/64", "10.0.0.0/16"})})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureExportPolicy(ctx, opts))
+		mc.nas.AssertNotCalled(t, "ExportPolicyCreate", mock.Anything, mock.Anything)
+		mc.nas.AssertNotCalled(t, "ExportPolicyModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("replaces rules of changed node networks", func(t *testing.T) {
+		mc := newClient(&models.ExportPolicy{ID: new(int64(42)), ExportPolicyInlineRules: exportRules([]string{"10.1.0.0/16"})})
+		mc.nas.On("ExportPolicyModify", mock.Anything, mock.Anything).Return(&n_a_s.ExportPolicyModifyOK{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureExportPolicy(ctx, opts))
+
+		p := mc.nas.Calls[1].Arguments[0].(*n_a_s.ExportPolicyModifyParams)
+		assert.Equal(t, int64(42), p.ID)
+		assert.Equal(t, []string{"10.0.0.0/16", "fd00::/64"}, exportRuleClients(p.Info.ExportPolicyInlineRules))
+	})
+
+	t.Run("fails without node networks", func(t *testing.T) {
+		m := NewSvmManager(logr.Discard(), nil, nil, nil)
+		require.Error(t, m.EnsureExportPolicy(ctx, CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot"}))
+	})
+}
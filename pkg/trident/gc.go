@@ -0,0 +1,205 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// builtinSVMAccount is created by ONTAP for every SVM and never managed by the extension.
+const builtinSVMAccount = "vsadmin"
+
+// projectSVMNameRegex matches the names of SVMs created by the extension, "p" followed by the project id without dashes.
+var projectSVMNameRegex = regexp.MustCompile(`^p[0-9a-f]{32}(-mc)?$`)
+
+// IsProjectSVMName returns whether the given SVM name follows the naming scheme of SVMs created by the extension.
+func IsProjectSVMName(name string) bool {
+	return projectSVMNameRegex.MatchString(name)
+}
+
+// ProjectSVMName returns the name of the project SVM, the -mc suffix of metrocluster SVMs is removed.
+func ProjectSVMName(name string) string {
+	return strings.TrimSuffix(name, "-mc")
+}
+
+// ManagedSVM is an SVM on one of the clusters which follows the naming scheme of the extension.
+type ManagedSVM struct {
+	Name    string
+	UUID    string
+	Cluster string
+	State   string
+	// Ownership is parsed from the SVM comment, it is nil for SVMs created before ownership was recorded
+	Ownership *Ownership
+	// Accounts are the accounts of the SVM, the builtin vsadmin account is omitted
+	Accounts []ManagedAccount
+
+	ontapClient *ontapv1.Ontap
+}
+
+// ManagedAccount is an account of a ManagedSVM.
+type ManagedAccount struct {
+	Name string
+	// Ownership is parsed from the account comment, it is nil for accounts created before ownership was recorded
+	Ownership *Ownership
+}
+
+// OwnedBy returns whether the ownership of the SVM was recorded by the extension in the given seed.
+func (s *ManagedSVM) OwnedBy(seed string) bool {
+	return s.Ownership != nil && seed != "" && s.Ownership.Seed == seed
+}
+
+// OwnedBy returns whether the ownership of the account was recorded by the extension in the given seed.
+// Accounts created before ownership was recorded are owned by the owner of their SVM.
+func (a ManagedAccount) OwnedBy(seed string, svm *ManagedSVM) bool {
+	if a.Ownership == nil {
+		return svm.OwnedBy(seed)
+	}
+	return seed != "" && a.Ownership.Seed == seed
+}
+
+// Running returns whether the SVM is running, only running SVMs can be modified.
+func (s *ManagedSVM) Running() bool {
+	return s.State == "running"
+}
+
+// ListManagedSVMs returns all SVMs on all clusters which follow the naming scheme of the extension or record its ownership,
+// including their accounts.
+func (m *SvmManager) ListManagedSVMs(ctx context.Context) (_ []*ManagedSVM, err error) {
+	ctx, span := tracing.Start(ctx, "ListManagedSVMs")
+	defer func() { tracing.End(span, err) }()
+
+	var result []*ManagedSVM
+	for _, rc := range m.clients {
+		if rc == nil || rc.SVM == nil {
+			continue
+		}
+		cluster := m.ClusterName(rc)
+
+		params := s_vm.NewSvmCollectionGetParamsWithContext(ctx)
+		params.SetFields([]string{"name", "state", "comment"})
+		svms, err := rc.SVM.SvmCollectionGet(params, nil)
+		if err != nil {
+			// an incomplete list must not be used to decide about orphans
+			return nil, fmt.Errorf("failed to list SVMs on cluster %s: %w", cluster, err)
+		}
+
+		for _, svm := range svms.Payload.SvmResponseInlineRecords {
+			if svm.Name == nil || svm.UUID == nil {
+				continue
+			}
+			// SVMs named with a naming template are only recognized by their recorded ownership
+			var ownership *Ownership
+			if svm.Comment != nil {
+				ownership, _ = ParseOwnership(*svm.Comment)
+			}
+			if !IsProjectSVMName(*svm.Name) && ownership == nil {
+				continue
+			}
+
+			managed := &ManagedSVM{
+				Name:        *svm.Name,
+				UUID:        *svm.UUID,
+				Cluster:     cluster,
+				ontapClient: rc,
+			}
+			if svm.State != nil {
+				managed.State = *svm.State
+			}
+			managed.Ownership = ownership
+
+			accountParams := security.NewAccountCollectionGetParamsWithContext(ctx)
+			accountParams.SetOwnerUUID(svm.UUID)
+			accountParams.SetFields([]string{"name", "comment"})
+			accounts, err := rc.Security.AccountCollectionGet(accountParams, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list accounts of SVM %s on cluster %s: %w", *svm.Name, cluster, err)
+			}
+			if accounts.Payload != nil {
+				for _, account := range accounts.Payload.AccountResponseInlineRecords {
+					if account.Name == nil || *account.Name == builtinSVMAccount {
+						continue
+					}
+					managedAccount := ManagedAccount{Name: *account.Name}
+					if account.Comment != nil {
+						managedAccount.Ownership, _ = ParseOwnership(*account.Comment)
+					}
+					managed.Accounts = append(managed.Accounts, managedAccount)
+				}
+			}
+
+			result = append(result, managed)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteAccount deletes the given account of the SVM.
+func (m *SvmManager) DeleteAccount(ctx context.Context, svm *ManagedSVM, username string) error {
+	params := security.NewAccountDeleteParamsWithContext(ctx)
+	params.SetOwnerUUID(svm.UUID)
+	params.SetName(username)
+
+	if _, err := svm.ontapClient.Security.AccountDelete(params, nil); err != nil {
+		return fmt.Errorf("failed to delete account %s of SVM %s: %w", username, svm.Name, err)
+	}
+
+	m.log.Info("Deleted account", "svm", svm.Name, "cluster", svm.Cluster, "user", username)
+	return nil
+}
+
+// DeleteSVMIfEmpty deletes the LIFs and the SVM itself if the SVM does not contain any volumes.
+func (m *SvmManager) DeleteSVMIfEmpty(ctx context.Context, svm *ManagedSVM) (err error) {
+	ctx, span := tracing.Start(ctx, "DeleteSVM", tracing.SVMKey.String(svm.Name))
+	defer func() { tracing.End(span, err) }()
+
+	volumeParams := storage.NewVolumeCollectionGetParamsWithContext(ctx)
+	volumeParams.SetSvmUUID(&svm.UUID)
+	volumeParams.SetFields([]string{"name"})
+	volumes, err := svm.ontapClient.Storage.VolumeCollectionGet(volumeParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes of SVM %s: %w", svm.Name, err)
+	}
+	if volumes.Payload != nil && len(volumes.Payload.VolumeResponseInlineRecords) > 0 {
+		return fmt.Errorf("SVM %s still contains %d volumes: %w", svm.Name, len(volumes.Payload.VolumeResponseInlineRecords), ErrSvmNotEmpty)
+	}
+
+	lifParams := networking.NewNetworkIPInterfacesGetParamsWithContext(ctx)
+	lifParams.SetSvmUUID(&svm.UUID)
+	lifParams.SetFields([]string{"uuid", "name"})
+	lifs, err := svm.ontapClient.Networking.NetworkIPInterfacesGet(lifParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list network interfaces of SVM %s: %w", svm.Name, err)
+	}
+	if lifs.Payload != nil {
+		for _, lif := range lifs.Payload.IPInterfaceResponseInlineRecords {
+			if lif.UUID == nil {
+				continue
+			}
+			deleteParams := networking.NewNetworkIPInterfaceDeleteParamsWithContext(ctx)
+			deleteParams.SetUUID(*lif.UUID)
+			if _, err := svm.ontapClient.Networking.NetworkIPInterfaceDelete(deleteParams, nil); err != nil {
+				return fmt.Errorf("failed to delete network interface %s of SVM %s: %w", *lif.UUID, svm.Name, err)
+			}
+			m.log.Info("Deleted network interface", "svm", svm.Name, "cluster", svm.Cluster, "uuid", *lif.UUID)
+		}
+	}
+
+	svmParams := s_vm.NewSvmDeleteParamsWithContext(ctx)
+	svmParams.SetUUID(svm.UUID)
+	if _, _, err := svm.ontapClient.SVM.SvmDelete(svmParams, nil); err != nil {
+		return fmt.Errorf("failed to delete SVM %s: %w", svm.Name, err)
+	}
+
+	m.log.Info("Deleted SVM", "svm", svm.Name, "cluster", svm.Cluster, "uuid", svm.UUID)
+	return nil
+}
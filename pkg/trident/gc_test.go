@@ -0,0 +1,107 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const projectSVM = "p0123456789abcdef0123456789abcdef"
+
+func TestIsProjectSVMName(t *testing.T) {
+	assert.True(t, IsProjectSVMName(projectSVM))
+	assert.True(t, IsProjectSVMName(projectSVM+"-mc"))
+	assert.False(t, IsProjectSVMName("svm0"))
+	assert.False(t, IsProjectSVMName("p0123"))
+	assert.Equal(t, projectSVM, ProjectSVMName(projectSVM+"-mc"))
+}
+
+func TestListManagedSVMs(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newMockOntapClient()
+	mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+			SvmResponseInlineRecords: []*models.Svm{
+				{Name: new(projectSVM), UUID: new("uuid-1"), State: new("running"), Comment: new(NewOwnership("seed-a", "shoot--proj--myshoot", projectSVM).Comment())},
+				{Name: new("svm0"), UUID: new("uuid-2"), State: new("running")},
+			},
+		}}, nil)
+	mc.security.On("AccountCollectionGet", mock.MatchedBy(func(p *security.AccountCollectionGetParams) bool {
+		return p.OwnerUUID != nil && *p.OwnerUUID == "uuid-1"
+	}), mock.Anything).
+		Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+			AccountResponseInlineRecords: []*models.Account{
+				{Name: new("vsadmin")},
+				{Name: new("myshoot")},
+				{Name: new("other"), Comment: new("created by hand")},
+			},
+		}}, nil)
+
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+	svms, err := m.ListManagedSVMs(ctx)
+	require.NoError(t, err)
+	require.Len(t, svms, 1)
+	assert.Equal(t, projectSVM, svms[0].Name)
+	assert.Equal(t, "uuid-1", svms[0].UUID)
+	assert.True(t, svms[0].Running())
+	assert.True(t, svms[0].OwnedBy("seed-a"))
+	assert.False(t, svms[0].OwnedBy("seed-b"))
+	require.Len(t, svms[0].Accounts, 2)
+	assert.Equal(t, "myshoot", svms[0].Accounts[0].Name)
+	// legacy accounts belong to the owner of their svm
+	assert.True(t, svms[0].Accounts[0].OwnedBy("seed-a", svms[0]))
+	assert.Nil(t, svms[0].Accounts[1].Ownership)
+}
+
+func TestDeleteSVMIfEmpty(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("refuses to delete svm with volumes", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.storage.On("VolumeCollectionGet", mock.Anything, mock.Anything).
+			Return(&storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{
+				VolumeResponseInlineRecords: []*models.Volume{{Name: new("trident_pvc")}},
+			}}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.DeleteSVMIfEmpty(ctx, &ManagedSVM{Name: projectSVM, UUID: "uuid-1", ontapClient: mc.client})
+		require.ErrorIs(t, err, ErrSvmNotEmpty)
+		mc.networking.AssertNotCalled(t, "NetworkIPInterfaceDelete", mock.Anything, mock.Anything)
+		mc.svm.AssertNotCalled(t, "SvmDelete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deletes lifs and empty svm", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.storage.On("VolumeCollectionGet", mock.Anything, mock.Anything).
+			Return(&storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{}}, nil)
+		mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+			Return(&networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{
+				IPInterfaceResponseInlineRecords: []*models.IPInterface{
+					{Name: new("datalif+0"), UUID: new("lif-1")},
+					{Name: new("managementlif"), UUID: new("lif-2")},
+				},
+			}}, nil)
+		mc.networking.On("NetworkIPInterfaceDelete", mock.Anything, mock.Anything).
+			Return(&networking.NetworkIPInterfaceDeleteOK{}, nil)
+		mc.svm.On("SvmDelete", mock.Anything, mock.Anything).Return(&s_vm.SvmDeleteOK{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.DeleteSVMIfEmpty(ctx, &ManagedSVM{Name: projectSVM, UUID: "uuid-1", ontapClient: mc.client})
+		require.NoError(t, err)
+		mc.networking.AssertNumberOfCalls(t, "NetworkIPInterfaceDelete", 2)
+		mc.svm.AssertCalled(t, "SvmDelete", mock.MatchedBy(func(p *s_vm.SvmDeleteParams) bool {
+			return p.UUID == "uuid-1"
+		}), mock.Anything)
+	})
+}
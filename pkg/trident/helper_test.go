@@ -3,9 +3,11 @@ package trident
 import (
 	ontapv1 "github.com/metal-stack/ontap-go/api/client"
 	mockcluster "github.com/metal-stack/ontap-go/test/mocks/cluster"
+	mocknas "github.com/metal-stack/ontap-go/test/mocks/n_a_s"
 	mocknetworking "github.com/metal-stack/ontap-go/test/mocks/networking"
 	mocksecurity "github.com/metal-stack/ontap-go/test/mocks/security"
 	mocksvm "github.com/metal-stack/ontap-go/test/mocks/s_vm"
+	mocksnapmirror "github.com/metal-stack/ontap-go/test/mocks/snap_mirror"
 	mockstorage "github.com/metal-stack/ontap-go/test/mocks/storage"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -18,6 +20,8 @@ type mockOntapClient struct {
 	cluster    *mockcluster.ClientService
 	networking *mocknetworking.ClientService
 	security   *mocksecurity.ClientService
+	nas        *mocknas.ClientService
+	snapmirror *mocksnapmirror.ClientService
 	k8sClient  client.Client
 }
 
@@ -27,14 +31,18 @@ func newMockOntapClient() *mockOntapClient {
 	cl := &mockcluster.ClientService{}
 	n := &mocknetworking.ClientService{}
 	sec := &mocksecurity.ClientService{}
+	nas := &mocknas.ClientService{}
+	sm := &mocksnapmirror.ClientService{}
 	k8s := fake.NewClientBuilder().Build()
 	return &mockOntapClient{
-		client:     &ontapv1.Ontap{SVM: s, Storage: st, Cluster: cl, Networking: n, Security: sec},
+		client:     &ontapv1.Ontap{SVM: s, Storage: st, Cluster: cl, Networking: n, Security: sec, Nas: nas, SnapMirror: sm},
 		svm:        s,
 		storage:    st,
 		cluster:    cl,
 		networking: n,
 		security:   sec,
+		nas:        nas,
+		snapmirror: sm,
 		k8sClient:  k8s,
 	}
 }
@@ -0,0 +1,186 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// unlimitedVolumes is the max_volumes value of SVMs without volume limit.
+const unlimitedVolumes = "unlimited"
+
+// SVMLimits returns the limits of the SVM, the limits of the shoot override the defaults field by field.
+func SVMLimits(shoot, defaults *ontapv1alpha1.SVMLimits) ontapv1alpha1.SVMLimits {
+	var limits ontapv1alpha1.SVMLimits
+	if defaults != nil {
+		limits = *defaults.DeepCopy()
+	}
+	if shoot == nil {
+		return limits
+	}
+	if shoot.StorageLimit != nil {
+		limits.StorageLimit = new(shoot.StorageLimit.DeepCopy())
+	}
+	if shoot.MaxVolumes != nil {
+		limits.MaxVolumes = new(*shoot.MaxVolumes)
+	}
+	return limits
+}
+
+// ProjectSVMLimits returns the limits of the SVM shared by the shoots of a project with the given provider configs. Each
+// limit is the largest limit of the shoots and unlimited if it is unlimited for any shoot, so the shoots do not
+// rewrite the limits of each other.
+func ProjectSVMLimits(configs []*ontapv1alpha1.TridentConfig, defaults *ontapv1alpha1.SVMLimits) ontapv1alpha1.SVMLimits {
+	if len(configs) == 0 {
+		return SVMLimits(nil, defaults)
+	}
+
+	var limits ontapv1alpha1.SVMLimits
+	for i, config := range configs {
+		shoot := SVMLimits(config.SVMLimits, defaults)
+		if i == 0 {
+			limits = shoot
+			continue
+		}
+		if limits.StorageLimit != nil && (shoot.StorageLimit == nil || shoot.StorageLimit.Cmp(*limits.StorageLimit) > 0) {
+			limits.StorageLimit = shoot.StorageLimit
+		}
+		if limits.MaxVolumes != nil && (shoot.MaxVolumes == nil || *shoot.MaxVolumes > *limits.MaxVolumes) {
+			limits.MaxVolumes = shoot.MaxVolumes
+		}
+	}
+	return limits
+}
+
+// svmLimits returns the storage limit and max volumes settings of an SVM with the given limits, a storage limit of
+// zero removes the limit.
+func svmLimits(limits ontapv1alpha1.SVMLimits) (storageLimit int64, maxVolumes string) {
+	if limits.StorageLimit != nil {
+		storageLimit = limits.StorageLimit.Value()
+	}
+	maxVolumes = unlimitedVolumes
+	if limits.MaxVolumes != nil {
+		maxVolumes = strconv.FormatInt(*limits.MaxVolumes, 10)
+	}
+	return storageLimit, maxVolumes
+}
+
+// ensureSVMLimits converges the storage limit and the maximum number of volumes of the SVM to the configured limits.
+func (m *SvmManager) ensureSVMLimits(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, limits ontapv1alpha1.SVMLimits) error {
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(svmUUID)
+	getParams.SetFields([]string{"storage.limit", "max_volumes"})
+
+	svmInfo, err := ontapClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM limits: %w", err)
+	}
+
+	var (
+		storageLimit, maxVolumes = svmLimits(limits)
+		actualLimit              int64
+		actualMaxVolumes         = unlimitedVolumes
+	)
+	if svm := svmInfo.Payload; svm != nil {
+		if svm.Storage != nil && svm.Storage.Limit != nil {
+			actualLimit = *svm.Storage.Limit
+		}
+		if svm.MaxVolumes != nil {
+			actualMaxVolumes = *svm.MaxVolumes
+		}
+	}
+	if actualLimit == storageLimit && actualMaxVolumes == maxVolumes {
+		return nil
+	}
+
+	m.log.Info("Changing SVM limits", "svmName", svmName, "storageLimit", storageLimit, "maxVolumes", maxVolumes)
+	modifyParams := s_vm.NewSvmModifyParamsWithContext(ctx)
+	modifyParams.SetUUID(svmUUID)
+	modifyParams.SetInfo(&models.Svm{
+		Storage:    &models.SvmInlineStorage{Limit: new(storageLimit)},
+		MaxVolumes: new(maxVolumes),
+	})
+	if _, _, err := ontapClient.SVM.SvmModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to change limits of SVM %s: %w", svmName, err)
+	}
+	m.recorder.Normal(ctx, events.ReasonSVMLimitsUpdated, events.ActionUpdate, "limits of SVM %s changed to storage limit %d bytes and %s volumes", svmName, storageLimit, maxVolumes)
+	return nil
+}
+
+// SVMUsage returns the usage of the SVM of the shoot against its limits and records it as metrics.
+func (m *SvmManager) SVMUsage(ctx context.Context, opts CreateSVMOptions) (_ *ontapv1alpha1.UsageStatus, err error) {
+	ctx, span := tracing.Start(ctx, "SVMUsage")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(*svmUUID)
+	getParams.SetFields([]string{"storage.limit", "storage.allocated", "max_volumes"})
+	svmInfo, err := ontapClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage of SVM %s: %w", opts.ProjectID, err)
+	}
+
+	volumeParams := storage.NewVolumeCollectionGetParamsWithContext(ctx)
+	volumeParams.SetSvmUUID(svmUUID)
+	volumeParams.SetIsSvmRoot(new(false))
+	volumeParams.SetReturnRecords(new(false))
+	volumes, err := ontapClient.Storage.VolumeCollectionGet(volumeParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count volumes of SVM %s: %w", opts.ProjectID, err)
+	}
+
+	usage := &ontapv1alpha1.UsageStatus{}
+	if volumes.Payload != nil && volumes.Payload.NumRecords != nil {
+		usage.Volumes = *volumes.Payload.NumRecords
+	}
+	if svm := svmInfo.Payload; svm != nil {
+		if svm.Storage != nil && svm.Storage.Allocated != nil {
+			usage.StorageAllocated = *resource.NewQuantity(*svm.Storage.Allocated, resource.BinarySI)
+		}
+		if svm.Storage != nil && svm.Storage.Limit != nil && *svm.Storage.Limit > 0 {
+			usage.StorageLimit = resource.NewQuantity(*svm.Storage.Limit, resource.BinarySI)
+		}
+		if svm.MaxVolumes != nil && *svm.MaxVolumes != unlimitedVolumes {
+			maxVolumes, err := strconv.ParseInt(*svm.MaxVolumes, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("SVM %s has an invalid max volumes setting %q: %w", opts.ProjectID, *svm.MaxVolumes, err)
+			}
+			usage.MaxVolumes = &maxVolumes
+		}
+	}
+
+	recordUsageMetrics(metrics.ClusterName(ontapClient), opts.ProjectID, usage)
+	return usage, nil
+}
+
+// recordUsageMetrics updates the usage metrics of an SVM, the limit metrics are removed for unlimited SVMs.
+func recordUsageMetrics(cluster, svmName string, usage *ontapv1alpha1.UsageStatus) {
+	metrics.SVMStorageAllocatedBytes.WithLabelValues(cluster, svmName).Set(float64(usage.StorageAllocated.Value()))
+	metrics.SVMVolumes.WithLabelValues(cluster, svmName).Set(float64(usage.Volumes))
+	if usage.StorageLimit != nil {
+		metrics.SVMStorageLimitBytes.WithLabelValues(cluster, svmName).Set(float64(usage.StorageLimit.Value()))
+	} else {
+		metrics.SVMStorageLimitBytes.DeleteLabelValues(cluster, svmName)
+	}
+	if usage.MaxVolumes != nil {
+		metrics.SVMMaxVolumes.WithLabelValues(cluster, svmName).Set(float64(*usage.MaxVolumes))
+	} else {
+		metrics.SVMMaxVolumes.DeleteLabelValues(cluster, svmName)
+	}
+}
@@ -0,0 +1,134 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestSVMLimits(t *testing.T) {
+	defaults := &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(100))}
+
+	assert.Equal(t, ontapv1alpha1.SVMLimits{}, SVMLimits(nil, nil))
+	assert.Equal(t, *defaults, SVMLimits(nil, defaults))
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(10))},
+		SVMLimits(&ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(10))}, defaults),
+	)
+
+	storageLimit, maxVolumes := svmLimits(ontapv1alpha1.SVMLimits{})
+	assert.Equal(t, int64(0), storageLimit)
+	assert.Equal(t, "unlimited", maxVolumes)
+	storageLimit, maxVolumes = svmLimits(*defaults)
+	assert.Equal(t, int64(1<<40), storageLimit)
+	assert.Equal(t, "100", maxVolumes)
+}
+
+func TestProjectSVMLimits(t *testing.T) {
+	defaults := &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Ti")), MaxVolumes: new(int64(100))}
+
+	assert.Equal(t, *defaults, ProjectSVMLimits(nil, defaults))
+	assert.Equal(t, *defaults, ProjectSVMLimits([]*ontapv1alpha1.TridentConfig{{}}, defaults))
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("2Ti")), MaxVolumes: new(int64(100))},
+		ProjectSVMLimits([]*ontapv1alpha1.TridentConfig{
+			{SVMLimits: &ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(10))}},
+			{SVMLimits: &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("2Ti"))}},
+		}, defaults),
+	)
+	// the order of the shoots does not matter
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("2Ti")), MaxVolumes: new(int64(100))},
+		ProjectSVMLimits([]*ontapv1alpha1.TridentConfig{
+			{SVMLimits: &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("2Ti"))}},
+			{SVMLimits: &ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(10))}},
+		}, defaults),
+	)
+	// a shoot without limits lifts the limits of the SVM
+	assert.Equal(t,
+		ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(10))},
+		ProjectSVMLimits([]*ontapv1alpha1.TridentConfig{
+			{SVMLimits: &ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Gi")), MaxVolumes: new(int64(10))}},
+			{SVMLimits: &ontapv1alpha1.SVMLimits{MaxVolumes: new(int64(5))}},
+		}, nil),
+	)
+}
+
+func TestEnsureSVMLimits(t *testing.T) {
+	ctx := context.Background()
+	limits := ontapv1alpha1.SVMLimits{StorageLimit: new(resource.MustParse("1Gi")), MaxVolumes: new(int64(10))}
+
+	newClient := func(svm *models.Svm) *mockOntapClient {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).Return(&s_vm.SvmGetOK{Payload: svm}, nil)
+		return mc
+	}
+
+	t.Run("no-op when the limits match", func(t *testing.T) {
+		mc := newClient(&models.Svm{Storage: &models.SvmInlineStorage{Limit: new(int64(1 << 30))}, MaxVolumes: new("10")})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.ensureSVMLimits(ctx, mc.client, "svm-uuid", "proj-1", limits))
+		mc.svm.AssertNotCalled(t, "SvmModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no-op for unlimited SVMs", func(t *testing.T) {
+		mc := newClient(&models.Svm{MaxVolumes: new("unlimited")})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.ensureSVMLimits(ctx, mc.client, "svm-uuid", "proj-1", ontapv1alpha1.SVMLimits{}))
+		mc.svm.AssertNotCalled(t, "SvmModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("changes the limits", func(t *testing.T) {
+		mc := newClient(&models.Svm{MaxVolumes: new("unlimited")})
+		mc.svm.On("SvmModify", mock.Anything, mock.Anything).Return(&s_vm.SvmModifyOK{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.ensureSVMLimits(ctx, mc.client, "svm-uuid", "proj-1", limits))
+
+		p := mc.svm.Calls[1].Arguments[0].(*s_vm.SvmModifyParams)
+		assert.Equal(t, "svm-uuid", p.UUID)
+		assert.Equal(t, int64(1<<30), *p.Info.Storage.Limit)
+		assert.Equal(t, "10", *p.Info.MaxVolumes)
+	})
+}
+
+func TestSVMUsage(t *testing.T) {
+	mc := newMockOntapClient()
+	mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+			SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+		}}, nil)
+	mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmGetOK{Payload: &models.Svm{
+			State:      new("running"),
+			Storage:    &models.SvmInlineStorage{Limit: new(int64(1 << 30)), Allocated: new(int64(1 << 20))},
+			MaxVolumes: new("10"),
+		}}, nil)
+	mc.storage.On("VolumeCollectionGet", mock.MatchedBy(func(p *storage.VolumeCollectionGetParams) bool {
+		return *p.SvmUUID == "svm-uuid" && !*p.IsSvmRoot
+	}), mock.Anything).
+		Return(&storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{NumRecords: new(int64(3))}}, nil)
+
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+	usage, err := m.SVMUsage(context.Background(), CreateSVMOptions{ProjectID: "proj-1"})
+	require.NoError(t, err)
+	assert.Equal(t, &ontapv1alpha1.UsageStatus{
+		StorageLimit:     resource.NewQuantity(1<<30, resource.BinarySI),
+		StorageAllocated: *resource.NewQuantity(1<<20, resource.BinarySI),
+		MaxVolumes:       new(int64(10)),
+		Volumes:          3,
+	}, usage)
+}
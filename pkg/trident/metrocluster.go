@@ -0,0 +1,143 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/cluster"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+const (
+	// mcSuffix is the suffix of the SVM of the partner site which takes over the SVM after a MetroCluster switchover
+	mcSuffix = "-mc"
+
+	// metroclusterNotConfigured is the mode of clusters which are not part of a MetroCluster
+	metroclusterNotConfigured = "not_configured"
+)
+
+// switchoverModes are the MetroCluster modes of a site which runs the SVMs of its partner site or whose SVMs were
+// switched over to its partner site, no SVMs are created on it until the switchback finished.
+var switchoverModes = []string{"switchover", "partial_switchover", "partial_switchback", "waiting_for_switchback"}
+
+// ErrSwitchover is returned if an SVM would be modified on a site of a MetroCluster in switchover.
+var ErrSwitchover = errors.New("MetroClusterSwitchover")
+
+// metroclusterMode returns the MetroCluster mode of operation of the cluster of the client and records whether it is
+// in switchover. The mode is not_configured if the cluster is not part of a MetroCluster.
+func metroclusterMode(ctx context.Context, ontapClient *ontapv1.Ontap) (string, error) {
+	params := cluster.NewMetroclusterGetParamsWithContext(ctx)
+	params.SetFields([]string{"local.mode"})
+
+	result, err := ontapClient.Cluster.MetroclusterGet(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get metrocluster state of cluster %s: %w", metrics.ClusterName(ontapClient), err)
+	}
+
+	mode := metroclusterNotConfigured
+	if result.Payload != nil && result.Payload.Local != nil && result.Payload.Local.Mode != nil {
+		mode = *result.Payload.Local.Mode
+	}
+
+	switchover := 0.0
+	if isSwitchover(mode) {
+		switchover = 1
+	}
+	metrics.MetroClusterSwitchover.WithLabelValues(metrics.ClusterName(ontapClient)).Set(switchover)
+	return mode, nil
+}
+
+// isSwitchover returns whether a site in the given MetroCluster mode is in switchover.
+func isSwitchover(mode string) bool {
+	return slices.Contains(switchoverModes, mode)
+}
+
+// isSwitchedOverSVM returns whether the SVM is the SVM of the partner site which runs after a MetroCluster
+// switchover, its configuration is replicated from the disaster site.
+func isSwitchedOverSVM(svmName string) bool {
+	return strings.HasSuffix(svmName, mcSuffix)
+}
+
+// MetroClusterState returns the site of the MetroCluster the SVM of the shoot is active on, it is nil if the cluster
+// of the SVM is not part of a MetroCluster.
+func (m *SvmManager) MetroClusterState(ctx context.Context, opts CreateSVMOptions) (_ *ontapv1alpha1.MetroClusterStatus, err error) {
+	ctx, span := tracing.Start(ctx, "MetroClusterState")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+	mode, err := metroclusterMode(ctx, ontapClient)
+	if err != nil {
+		return nil, err
+	}
+	if mode == metroclusterNotConfigured {
+		return nil, nil
+	}
+	svmName, err := m.svmName(ctx, ontapClient, *svmUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ontapv1alpha1.MetroClusterStatus{
+		ActiveSite:   m.ClusterName(ontapClient),
+		SVM:          svmName,
+		Mode:         mode,
+		SwitchedOver: isSwitchedOverSVM(svmName),
+	}, nil
+}
+
+// validateSwitchedOverSVM validates the SVM of the partner site after a MetroCluster switchover. Its configuration is
+// replicated from the disaster site, missing LIFs are reported instead of being created on the nodes of the partner
+// site and the ownership, protocols and limits are not modified until the switchback.
+func (m *SvmManager) validateSwitchedOverSVM(ctx context.Context, activeClient *ontapv1.Ontap, svmUUID, svmName string, opts CreateSVMOptions) error {
+	m.log.Info("Validating SVM of the partner site after a MetroCluster switchover", "svmName", svmName, "uuid", svmUUID)
+
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(svmUUID)
+	getParams.SetFields([]string{"comment"})
+	svmInfo, err := activeClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM comment: %w", err)
+	}
+	if svmInfo.Payload != nil {
+		// SVMs without ownership are adopted after the switchback
+		if _, err := checkSVMOwnership(svmName, svmInfo.Payload.Comment, opts.svmNames()...); err != nil {
+			return err
+		}
+	}
+
+	if err := m.validateSVMRunningState(ctx, activeClient, svmUUID, svmName, opts.svmProtocols()); err != nil {
+		return err
+	}
+
+	existingInterfaces, err := m.getExistingNetworkInterfaces(ctx, activeClient, svmUUID)
+	if err != nil {
+		return err
+	}
+	// the LIFs keep the names of the disaster site, IP mismatches are reported by the drift detection
+	var missing []string
+	for i, ip := range opts.SvmIpaddresses.DataLifs {
+		if _, _, exists := lookupLIF(existingInterfaces, opts.Naming.DataLIFName(opts.ProjectID, i), opts.Naming.DataLIFAliases(opts.ProjectID, i)); !exists {
+			missing = append(missing, ip)
+		}
+	}
+	if _, _, exists := lookupLIF(existingInterfaces, opts.Naming.ManagementLIFName(opts.ProjectID), opts.Naming.ManagementLIFAliases(opts.ProjectID)); !exists {
+		missing = append(missing, opts.SvmIpaddresses.ManagementLif)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("LIFs with ips %v are missing on SVM %s after the switchover: %w", missing, svmName, ErrSwitchover)
+	}
+
+	m.recordLIFMetrics(ctx, activeClient, svmUUID, svmName)
+	return nil
+}
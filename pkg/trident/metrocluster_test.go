@@ -0,0 +1,141 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/cluster"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func withMetroClusterMode(mc *mockOntapClient, mode string) {
+	mc.cluster.On("MetroclusterGet", mock.Anything, mock.Anything).
+		Return(&cluster.MetroclusterGetOK{Payload: &models.Metrocluster{Local: &models.MetroclusterInlineLocal{Mode: new(mode)}}}, nil)
+}
+
+func withRunningSVM(mc *mockOntapClient, name string) {
+	mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+			SvmResponseInlineRecords: []*models.Svm{{Name: new(name), UUID: new("svm-uuid")}},
+		}}, nil)
+	mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmGetOK{Payload: &models.Svm{
+			Name: new(name), UUID: new("svm-uuid"), State: new("running"), Nvme: &models.SvmInlineNvme{Enabled: new(true)},
+		}}, nil)
+}
+
+func TestGetWriteClient_Switchover(t *testing.T) {
+	ctx := context.Background()
+
+	mc1, mc2 := newMockOntapClient(), newMockOntapClient()
+	withMetroClusterMode(mc1, "switchover")
+	withMetroClusterMode(mc2, "normal")
+	mc2.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
+		Return(&storage.AggregateCollectionGetOK{Payload: &models.AggregateResponse{
+			AggregateResponseInlineRecords: []*models.Aggregate{
+				{Name: new("a2"), UUID: new("u2"), VolumeCount: new(int64(50))},
+			},
+		}}, nil)
+
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
+	got, err := m.getWriteClient(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, mc2.client, got)
+	mc1.storage.AssertNotCalled(t, "AggregateCollectionGet", mock.Anything, mock.Anything)
+
+	m = NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client}, nil, nil)
+	_, err = m.getWriteClient(ctx)
+	require.Error(t, err)
+
+	// clients with an unknown metrocluster state are skipped instead of failing the selection
+	mc3 := newMockOntapClient()
+	mc3.cluster.On("MetroclusterGet", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	m = NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc3.client, mc2.client}, nil, nil)
+	got, err = m.getWriteClient(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, mc2.client, got)
+	mc3.storage.AssertNotCalled(t, "AggregateCollectionGet", mock.Anything, mock.Anything)
+
+	// the errors of the skipped clients are returned if no client qualifies
+	m = NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc3.client}, nil, nil)
+	_, err = m.getWriteClient(ctx)
+	require.ErrorContains(t, err, "connection refused")
+}
+
+func TestMetroClusterState(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mode    string
+		svmName string
+		want    *ontapv1alpha1.MetroClusterStatus
+	}{
+		{name: "no metrocluster", mode: metroclusterNotConfigured, svmName: "proj-1"},
+		{name: "normal operation", mode: "normal", svmName: "proj-1", want: &ontapv1alpha1.MetroClusterStatus{ActiveSite: "site-a", SVM: "proj-1", Mode: "normal"}},
+		{name: "switched over to the partner site", mode: "switchover", svmName: "proj-1-mc", want: &ontapv1alpha1.MetroClusterStatus{ActiveSite: "site-a", SVM: "proj-1-mc", Mode: "switchover", SwitchedOver: true}},
+		{name: "partner site switched over", mode: "switchover", svmName: "proj-1", want: &ontapv1alpha1.MetroClusterStatus{ActiveSite: "site-a", SVM: "proj-1", Mode: "switchover"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newMockOntapClient()
+			withMetroClusterMode(mc, tt.mode)
+			withRunningSVM(mc, tt.svmName)
+
+			m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil).
+				WithClusterNames(map[*ontapv1.Ontap]string{mc.client: "site-a"})
+			got, err := m.MetroClusterState(ctx, CreateSVMOptions{ProjectID: "proj-1"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateSwitchedOverSVM(t *testing.T) {
+	ctx := context.Background()
+	opts := CreateSVMOptions{
+		ProjectID:      "proj-1",
+		SvmIpaddresses: ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.0.1", DataLifs: []string{"10.0.0.2"}},
+	}
+	interfaces := func(mc *mockOntapClient, lifs map[string]string) {
+		var records []*models.IPInterface
+		for name, ip := range lifs {
+			records = append(records, &models.IPInterface{Name: new(name), IP: &models.IPInfo{Address: new(models.IPAddress(ip))}})
+		}
+		mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+			Return(&networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{IPInterfaceResponseInlineRecords: records}}, nil)
+	}
+
+	t.Run("accepts the replicated LIFs", func(t *testing.T) {
+		mc := newMockOntapClient()
+		withRunningSVM(mc, "proj-1-mc")
+		interfaces(mc, map[string]string{opts.Naming.DataLIFName("proj-1", 0): "10.0.0.2", opts.Naming.ManagementLIFName("proj-1"): "10.0.0.1"})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.validateSwitchedOverSVM(ctx, mc.client, "svm-uuid", "proj-1-mc", opts))
+		mc.svm.AssertNotCalled(t, "SvmModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports missing LIFs without creating them", func(t *testing.T) {
+		mc := newMockOntapClient()
+		withRunningSVM(mc, "proj-1-mc")
+		interfaces(mc, map[string]string{opts.Naming.ManagementLIFName("proj-1"): "10.0.0.1"})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.validateSwitchedOverSVM(ctx, mc.client, "svm-uuid", "proj-1-mc", opts)
+		require.ErrorIs(t, err, ErrSwitchover)
+		assert.Contains(t, err.Error(), "10.0.0.2")
+		mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,298 @@
+package trident
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+const (
+	// legacySVMTemplate, legacyDataLIFTemplate, legacyManagementLIFTemplate, legacyBackendTemplate and
+	// legacySecretTemplate render the names of earlier versions, they are used for empty templates and as aliases.
+	legacySVMTemplate           = `p{{ .ProjectID }}`
+	legacyDataLIFTemplate       = dataLifTag + `+{{ .Index }}`
+	legacyManagementLIFTemplate = managementLifTag
+	legacyBackendTemplate       = `ontap-{{ .SVMName }}`
+	legacySecretTemplate        = `{{ .SVMName }}-{{ trimPrefix "shoot--" .ShootNamespace }}-credentials`
+
+	// maxSVMNameLength is the maximum length of an SVM name in ONTAP minus the length of the -mc suffix
+	// of the MetroCluster partner SVM.
+	maxSVMNameLength = 47 - len("-mc")
+	// maxLIFNameLength is the maximum length of a network interface name in ONTAP.
+	maxLIFNameLength = 238
+	// maxBackendNameLength keeps the Trident backend name usable as label value.
+	maxBackendNameLength = validation.LabelValueMaxLength
+)
+
+var (
+	svmNameRegex     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+	lifNameRegex     = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.+:-]*$`)
+	backendNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$`)
+
+	namingFuncs = template.FuncMap{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"trunc": func(n int, s string) string {
+			if len(s) > n {
+				return s[:n]
+			}
+			return s
+		},
+	}
+
+	// sampleNameData has the longest values the templates can be rendered with: project ids are UUIDs without dashes
+	// which may start with a digit and gardener limits the project and shoot name to 21 characters.
+	sampleNameData = NameData{
+		ProjectID:      strings.Repeat("0123456789abcdef", 2),
+		Index:          99,
+		ShootNamespace: "shoot--abcdefghij--abcdefghijk",
+	}
+)
+
+// NameData are the values the naming templates are rendered with.
+type NameData struct {
+	// ProjectID is the project id without dashes
+	ProjectID string
+	// SVMName is the name of the SVM
+	SVMName string
+	// Index is the index of the data LIF
+	Index int
+	// ShootNamespace is the namespace of the shoot in the seed
+	ShootNamespace string
+}
+
+// Naming renders the names of the objects created by the extension from the templates of the ControllerConfiguration.
+// A nil Naming renders the names of earlier versions.
+type Naming struct {
+	svm, dataLIF, managementLIF, backend, secret *template.Template
+}
+
+// legacyNaming renders the names of earlier versions.
+var legacyNaming = mustNaming(nil)
+
+// NewNaming parses and validates the templates, they are rendered with the longest possible values and the results are
+// checked against the character and length limits of ONTAP, Trident and Kubernetes.
+func NewNaming(cfg *config.NamingConfig) (*Naming, error) {
+	if cfg == nil {
+		cfg = &config.NamingConfig{}
+	}
+
+	var (
+		n    = &Naming{}
+		errs []error
+	)
+	for _, t := range []struct {
+		name     string
+		text     string
+		fallback string
+		target   **template.Template
+	}{
+		{name: "svm", text: cfg.SVM, fallback: legacySVMTemplate, target: &n.svm},
+		{name: "dataLIF", text: cfg.DataLIF, fallback: legacyDataLIFTemplate, target: &n.dataLIF},
+		{name: "managementLIF", text: cfg.ManagementLIF, fallback: legacyManagementLIFTemplate, target: &n.managementLIF},
+		{name: "backend", text: cfg.Backend, fallback: legacyBackendTemplate, target: &n.backend},
+		{name: "secret", text: cfg.Secret, fallback: legacySecretTemplate, target: &n.secret},
+	} {
+		text := t.text
+		if text == "" {
+			text = t.fallback
+		}
+		parsed, err := template.New(t.name).Funcs(namingFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s name template: %w", t.name, err))
+			continue
+		}
+		*t.target = parsed
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := n.validate(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func mustNaming(cfg *config.NamingConfig) *Naming {
+	n, err := NewNaming(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// validate renders all templates with the sample values and checks the results.
+func (n *Naming) validate() error {
+	data := sampleNameData
+
+	svmName, err := render(n.svm, data)
+	if err != nil {
+		return fmt.Errorf("invalid svm name template: %w", err)
+	}
+	data.SVMName = svmName
+
+	var errs []error
+	if err := checkName(svmName, maxSVMNameLength, svmNameRegex); err != nil {
+		errs = append(errs, fmt.Errorf("invalid svm name %q: %w", svmName, err))
+	}
+
+	for _, t := range []struct {
+		name   string
+		tmpl   *template.Template
+		maxLen int
+		regex  *regexp.Regexp
+	}{
+		{name: "data LIF", tmpl: n.dataLIF, maxLen: maxLIFNameLength, regex: lifNameRegex},
+		{name: "management LIF", tmpl: n.managementLIF, maxLen: maxLIFNameLength, regex: lifNameRegex},
+		{name: "backend", tmpl: n.backend, maxLen: maxBackendNameLength, regex: backendNameRegex},
+	} {
+		name, err := render(t.tmpl, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s name template: %w", t.name, err))
+			continue
+		}
+		if err := checkName(name, t.maxLen, t.regex); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s name %q: %w", t.name, name, err))
+		}
+	}
+
+	if dataLIF, err := render(n.dataLIF, data); err == nil {
+		data.Index = 0
+		if first, _ := render(n.dataLIF, data); first == dataLIF {
+			errs = append(errs, errors.New("data LIF name template must contain .Index"))
+		}
+		data.Index = sampleNameData.Index
+	}
+
+	secretName, err := render(n.secret, data)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid secret name template: %w", err))
+	} else if msgs := validation.IsDNS1123Subdomain(secretName); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid secret name %q: %s", secretName, strings.Join(msgs, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+func checkName(name string, maxLen int, regex *regexp.Regexp) error {
+	if len(name) > maxLen {
+		return fmt.Errorf("longer than %d characters", maxLen)
+	}
+	if !regex.MatchString(name) {
+		return fmt.Errorf("must match %s", regex)
+	}
+	return nil
+}
+
+func render(t *template.Template, data NameData) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// mustRender renders a validated template, rendering can only fail for values the validation did not cover, the
+// empty name is then rejected by ONTAP or Kubernetes.
+func mustRender(t *template.Template, data NameData) string {
+	name, err := render(t, data)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+func (n *Naming) orLegacy() *Naming {
+	if n == nil {
+		return legacyNaming
+	}
+	return n
+}
+
+// SVMName returns the SVM name of the project, the project id must not contain dashes.
+func (n *Naming) SVMName(projectID string) string {
+	n = n.orLegacy()
+	return mustRender(n.svm, NameData{ProjectID: projectID})
+}
+
+// DataLIFName returns the name of the data LIF with the given index of the SVM.
+func (n *Naming) DataLIFName(svmName string, index int) string {
+	n = n.orLegacy()
+	return mustRender(n.dataLIF, NameData{SVMName: svmName, Index: index})
+}
+
+// ManagementLIFName returns the name of the management LIF of the SVM.
+func (n *Naming) ManagementLIFName(svmName string) string {
+	n = n.orLegacy()
+	return mustRender(n.managementLIF, NameData{SVMName: svmName})
+}
+
+// BackendName returns the name of the Trident backend of a shoot using the SVM.
+func (n *Naming) BackendName(svmName string) string {
+	n = n.orLegacy()
+	return mustRender(n.backend, NameData{SVMName: svmName})
+}
+
+// SecretName returns the name of the credentials secret of the shoot for the SVM.
+func (n *Naming) SecretName(svmName, shootNamespace string) string {
+	n = n.orLegacy()
+	return mustRender(n.secret, NameData{SVMName: svmName, ShootNamespace: shootNamespace})
+}
+
+// SVMAliases returns the SVM names of earlier versions of the project which differ from the current name.
+func (n *Naming) SVMAliases(projectID string) []string {
+	return aliases(n.SVMName(projectID), legacyNaming.SVMName(projectID))
+}
+
+// DataLIFAliases returns the data LIF names of earlier versions which differ from the current name.
+func (n *Naming) DataLIFAliases(svmName string, index int) []string {
+	return aliases(n.DataLIFName(svmName, index), legacyNaming.DataLIFName(svmName, index))
+}
+
+// ManagementLIFAliases returns the management LIF names of earlier versions which differ from the current name.
+func (n *Naming) ManagementLIFAliases(svmName string) []string {
+	return aliases(n.ManagementLIFName(svmName), legacyNaming.ManagementLIFName(svmName))
+}
+
+// SecretAliases returns the secret names of earlier versions for the SVM and its aliases which differ from the
+// current name.
+func (n *Naming) SecretAliases(svmName string, svmAliases []string, shootNamespace string) []string {
+	var legacy []string
+	for _, name := range append([]string{svmName}, svmAliases...) {
+		legacy = append(legacy, legacyNaming.SecretName(name, shootNamespace))
+	}
+	return aliases(n.SecretName(svmName, shootNamespace), legacy...)
+}
+
+// BackendConfigName returns the name of the TridentBackendConfig in the shoot. It is derived from the oldest name of
+// the SVM and does not follow the naming templates, a new name would make Trident create a new backend instead of
+// updating the backend the volumes were provisioned from.
+func BackendConfigName(svmName string, svmAliases []string) string {
+	if len(svmAliases) > 0 {
+		svmName = svmAliases[len(svmAliases)-1]
+	}
+	return fmt.Sprintf("ontap-%s-backend", svmName)
+}
+
+// aliases returns the distinct candidates which differ from the current name.
+func aliases(current string, candidates ...string) []string {
+	var result []string
+	for _, c := range candidates {
+		if c != current && c != "" && !slices.Contains(result, c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
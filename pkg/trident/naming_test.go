@@ -0,0 +1,80 @@
+package trident
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+func TestNaming(t *testing.T) {
+	const (
+		projectID      = "0123456789abcdef0123456789abcdef"
+		shootNamespace = "shoot--proj--myshoot"
+	)
+
+	t.Run("empty templates keep the names of earlier versions", func(t *testing.T) {
+		for _, n := range []*Naming{nil, mustNaming(&config.NamingConfig{})} {
+			svm := n.SVMName(projectID)
+			assert.Equal(t, "p"+projectID, svm)
+			assert.Equal(t, "datalif+1", n.DataLIFName(svm, 1))
+			assert.Equal(t, "managementlif", n.ManagementLIFName(svm))
+			assert.Equal(t, "ontap-"+svm, n.BackendName(svm))
+			assert.Equal(t, SeedSecretName(svm, shootNamespace), n.SecretName(svm, shootNamespace))
+			assert.Empty(t, n.SVMAliases(projectID))
+			assert.Empty(t, n.DataLIFAliases(svm, 1))
+			assert.Empty(t, n.ManagementLIFAliases(svm))
+			assert.Empty(t, n.SecretAliases(svm, nil, shootNamespace))
+		}
+	})
+
+	t.Run("templates with aliases of earlier versions", func(t *testing.T) {
+		n, err := NewNaming(&config.NamingConfig{
+			SVM:           "svm_{{ trunc 12 .ProjectID }}",
+			DataLIF:       "{{ .SVMName }}_data{{ .Index }}",
+			ManagementLIF: "{{ .SVMName }}_mgmt",
+			Backend:       "{{ .SVMName | replace \"_\" \"-\" }}",
+			Secret:        "{{ .SVMName | replace \"_\" \"-\" }}-{{ trimPrefix \"shoot--\" .ShootNamespace }}",
+		})
+		require.NoError(t, err)
+
+		svm := n.SVMName(projectID)
+		assert.Equal(t, "svm_0123456789ab", svm)
+		assert.Equal(t, []string{"p" + projectID}, n.SVMAliases(projectID))
+		assert.Equal(t, "svm_0123456789ab_data0", n.DataLIFName(svm, 0))
+		assert.Equal(t, []string{"datalif+0"}, n.DataLIFAliases(svm, 0))
+		assert.Equal(t, "svm_0123456789ab_mgmt", n.ManagementLIFName(svm))
+		assert.Equal(t, []string{"managementlif"}, n.ManagementLIFAliases(svm))
+		assert.Equal(t, "svm-0123456789ab", n.BackendName(svm))
+		assert.Equal(t, "svm-0123456789ab-proj--myshoot", n.SecretName(svm, shootNamespace))
+		assert.Equal(t, []string{
+			"svm_0123456789ab-proj--myshoot-credentials",
+			"p" + projectID + "-proj--myshoot-credentials",
+		}, n.SecretAliases(svm, n.SVMAliases(projectID), shootNamespace))
+
+		assert.Equal(t, "ontap-p"+projectID+"-backend", BackendConfigName(svm, n.SVMAliases(projectID)))
+		assert.Equal(t, "ontap-"+svm+"-backend", BackendConfigName(svm, nil))
+	})
+
+	tests := []struct {
+		name    string
+		config  config.NamingConfig
+		wantErr string
+	}{
+		{name: "unparsable template", config: config.NamingConfig{SVM: "p{{ .ProjectID "}, wantErr: "invalid svm name template"},
+		{name: "unknown field", config: config.NamingConfig{Backend: "{{ .Project }}"}, wantErr: "invalid backend name template"},
+		{name: "svm name too long", config: config.NamingConfig{SVM: "project-{{ .ProjectID }}-svm01"}, wantErr: "longer than 44 characters"},
+		{name: "svm name starts with digit", config: config.NamingConfig{SVM: "{{ .ProjectID }}"}, wantErr: "invalid svm name"},
+		{name: "lif name with spaces", config: config.NamingConfig{ManagementLIF: "management lif"}, wantErr: "invalid management LIF name"},
+		{name: "data lif without index", config: config.NamingConfig{DataLIF: "datalif"}, wantErr: "must contain .Index"},
+		{name: "secret name with upper case", config: config.NamingConfig{Secret: "{{ upper .SVMName }}"}, wantErr: "invalid secret name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNaming(&tt.config)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package trident
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/version"
+)
+
+const (
+	// ownershipManager identifies ONTAP objects created by the extension.
+	ownershipManager = "gardener-extension-ontap"
+	// maxCommentLength is the maximum length of SVM and account comments in ONTAP.
+	maxCommentLength = 255
+)
+
+// ErrNotOwned is returned if an ONTAP object should be modified which was not created by this extension in this seed.
+var ErrNotOwned = errors.New("NotOwned")
+
+// Ownership describes which seed, shoot and project created an ONTAP object.
+// It is stored in the comment of SVMs and accounts. LIFs do not carry a comment in the ONTAP REST API
+// and are owned by the owner of their SVM.
+type Ownership struct {
+	Seed             string
+	ShootNamespace   string
+	ProjectID        string
+	ExtensionVersion string
+	CreatedAt        time.Time
+}
+
+// NewOwnership returns the ownership for an object created now by the running extension.
+func NewOwnership(seed, shootNamespace, projectID string) Ownership {
+	return Ownership{
+		Seed:             seed,
+		ShootNamespace:   shootNamespace,
+		ProjectID:        projectID,
+		ExtensionVersion: version.Version,
+		CreatedAt:        time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Comment renders the ownership as ONTAP comment, e.g.
+// "managed-by=gardener-extension-ontap;seed=a-seed;shoot=shoot--proj--a;project=p123;version=v0.1.0;created=2025-01-01T00:00:00Z"
+func (o Ownership) Comment() string {
+	fields := []string{
+		"managed-by=" + ownershipManager,
+		"seed=" + o.Seed,
+		"shoot=" + o.ShootNamespace,
+		"project=" + o.ProjectID,
+		"version=" + o.ExtensionVersion,
+		"created=" + o.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	comment := strings.Join(fields, ";")
+	if len(comment) > maxCommentLength {
+		// the version is the least relevant information for forensics
+		comment = strings.Join(append(fields[:4], fields[5]), ";")
+	}
+	return comment
+}
+
+// ParseOwnership parses an ONTAP comment written by Comment.
+// It returns false if the comment was not written by the extension.
+func ParseOwnership(comment string) (*Ownership, bool) {
+	values := map[string]string{}
+	for field := range strings.SplitSeq(comment, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, false
+		}
+		values[key] = value
+	}
+	if values["managed-by"] != ownershipManager {
+		return nil, false
+	}
+
+	o := &Ownership{
+		Seed:             values["seed"],
+		ShootNamespace:   values["shoot"],
+		ProjectID:        values["project"],
+		ExtensionVersion: values["version"],
+	}
+	if created, err := time.Parse(time.RFC3339, values["created"]); err == nil {
+		o.CreatedAt = created
+	}
+	return o, true
+}
+
+// checkOwnership verifies that an object with the given comment may be modified by the extension in the given seed.
+// Objects without a comment were created before ownership was recorded and are adopted, adopt reports whether
+// the ownership should be written to the object.
+func checkOwnership(kind, name string, comment *string, seed string) (adopt bool, err error) {
+	if comment == nil || *comment == "" {
+		return true, nil
+	}
+
+	o, ok := ParseOwnership(*comment)
+	if !ok {
+		return false, fmt.Errorf("%s %s was not created by %s: %w", kind, name, ownershipManager, ErrNotOwned)
+	}
+	if seed != "" && o.Seed != "" && o.Seed != seed {
+		return false, fmt.Errorf("%s %s is owned by seed %s: %w", kind, name, o.Seed, ErrNotOwned)
+	}
+	return false, nil
+}
+
+// checkSVMOwnership verifies that an SVM with the given comment may be modified by the extension. Project SVMs are
+// shared by the shoots of all seeds, only the recorded project has to be one of the given names of the SVM. SVMs
+// without a comment are adopted like in checkOwnership.
+func checkSVMOwnership(name string, comment *string, projects ...string) (adopt bool, err error) {
+	if comment == nil || *comment == "" {
+		return true, nil
+	}
+
+	o, ok := ParseOwnership(*comment)
+	if !ok {
+		return false, fmt.Errorf("SVM %s was not created by %s: %w", name, ownershipManager, ErrNotOwned)
+	}
+	if o.ProjectID != "" && !slices.Contains(projects, o.ProjectID) {
+		return false, fmt.Errorf("SVM %s is owned by project %s: %w", name, o.ProjectID, ErrNotOwned)
+	}
+	return false, nil
+}
+
+// ownedByShoot returns whether the comment records the given shoot as owner.
+func ownedByShoot(comment *string, shootNamespace string) bool {
+	if comment == nil {
+		return false
+	}
+	o, ok := ParseOwnership(*comment)
+	return ok && o.ShootNamespace == shootNamespace
+}
@@ -0,0 +1,102 @@
+package trident
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnershipComment(t *testing.T) {
+	o := Ownership{
+		Seed:             "seed-a",
+		ShootNamespace:   "shoot--proj--myshoot",
+		ProjectID:        projectSVM,
+		ExtensionVersion: "v0.1.0",
+		CreatedAt:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	comment := o.Comment()
+	assert.Equal(t, "managed-by=gardener-extension-ontap;seed=seed-a;shoot=shoot--proj--myshoot;project="+projectSVM+";version=v0.1.0;created=2025-01-01T00:00:00Z", comment)
+
+	parsed, ok := ParseOwnership(comment)
+	require.True(t, ok)
+	assert.Equal(t, o, *parsed)
+
+	t.Run("version is dropped if the comment is too long", func(t *testing.T) {
+		long := o
+		long.ExtensionVersion = strings.Repeat("v", maxCommentLength)
+
+		parsed, ok := ParseOwnership(long.Comment())
+		require.True(t, ok)
+		assert.Empty(t, parsed.ExtensionVersion)
+		assert.Equal(t, o.Seed, parsed.Seed)
+		assert.Equal(t, o.CreatedAt, parsed.CreatedAt)
+	})
+
+	t.Run("foreign comments are not parsed", func(t *testing.T) {
+		_, ok := ParseOwnership("storage team test svm")
+		assert.False(t, ok)
+		_, ok = ParseOwnership("managed-by=someone-else;seed=seed-a")
+		assert.False(t, ok)
+	})
+}
+
+func TestCheckOwnership(t *testing.T) {
+	owned := NewOwnership("seed-a", "shoot--proj--myshoot", projectSVM).Comment()
+
+	tests := []struct {
+		name      string
+		comment   *string
+		seed      string
+		wantAdopt bool
+		wantErr   bool
+	}{
+		{name: "legacy object without comment is adopted", comment: nil, seed: "seed-a", wantAdopt: true},
+		{name: "empty comment is adopted", comment: new(""), seed: "seed-a", wantAdopt: true},
+		{name: "owned by this seed", comment: new(owned), seed: "seed-a"},
+		{name: "seed unknown", comment: new(owned), seed: ""},
+		{name: "owned by another seed", comment: new(owned), seed: "seed-b", wantErr: true},
+		{name: "created by hand", comment: new("storage team test svm"), seed: "seed-a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adopt, err := checkOwnership("SVM", projectSVM, tt.comment, tt.seed)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrNotOwned)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAdopt, adopt)
+		})
+	}
+}
+
+func TestCheckSVMOwnership(t *testing.T) {
+	tests := []struct {
+		name      string
+		comment   *string
+		wantAdopt bool
+		wantErr   bool
+	}{
+		{name: "legacy SVM without comment is adopted", comment: nil, wantAdopt: true},
+		{name: "created by a shoot of this seed", comment: new(NewOwnership("seed-a", "shoot--proj--a", projectSVM).Comment())},
+		{name: "created by a shoot of another seed", comment: new(NewOwnership("seed-b", "shoot--proj--b", projectSVM).Comment())},
+		{name: "adopted under its legacy name", comment: new(NewOwnership("seed-b", "shoot--proj--b", "proj-old").Comment())},
+		{name: "owned by another project", comment: new(NewOwnership("seed-a", "shoot--other--a", "p-other").Comment()), wantErr: true},
+		{name: "created by hand", comment: new("storage team test svm"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adopt, err := checkSVMOwnership(projectSVM, tt.comment, projectSVM, "proj-old")
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrNotOwned)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAdopt, adopt)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package trident
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+const (
+	lowercaseLetters = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits           = "0123456789"
+)
+
+// defaultPasswordPolicy is used if no password policy is given, it matches the defaults of the ControllerConfiguration.
+var defaultPasswordPolicy = config.PasswordPolicyConfig{
+	Length:    24,
+	Digits:    2,
+	Symbols:   2,
+	Uppercase: 2,
+	Lowercase: 2,
+	SymbolSet: "!#%+-.:=@^_",
+}
+
+// generatePassword returns a random password with at least the minimum number of characters of every class of the policy,
+// the remaining characters are drawn from all classes.
+func generatePassword(policy *config.PasswordPolicyConfig) (string, error) {
+	if policy == nil {
+		policy = &defaultPasswordPolicy
+	}
+
+	classes := []struct {
+		chars string
+		min   int
+	}{
+		{chars: lowercaseLetters, min: policy.Lowercase},
+		{chars: uppercaseLetters, min: policy.Uppercase},
+		{chars: digits, min: policy.Digits},
+		{chars: policy.SymbolSet, min: policy.Symbols},
+	}
+
+	var (
+		password = make([]byte, 0, policy.Length)
+		all      strings.Builder
+	)
+	for _, class := range classes {
+		if class.chars == "" {
+			continue
+		}
+		all.WriteString(class.chars)
+		for range class.min {
+			c, err := randomChar(class.chars)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+	}
+	for len(password) < policy.Length {
+		c, err := randomChar(all.String())
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// shuffle, otherwise the character classes would be predictable by position
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("unable to create a random password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create a random password: %w", err)
+	}
+	return chars[i.Int64()], nil
+}
+
+// PasswordRules are the password rules of a role as configured with "security login role config".
+type PasswordRules struct {
+	MinLength    int  `json:"passwd_minlength"`
+	MinDigits    int  `json:"passwd_min_digits"`
+	MinSymbols   int  `json:"passwd_min_special_chars"`
+	MinUppercase int  `json:"passwd_min_uppercase_chars"`
+	MinLowercase int  `json:"passwd_min_lowercase_chars"`
+	Alphanumeric bool `json:"-"`
+}
+
+// roleConfigRecord is a record of the private CLI endpoint of "security login role config show".
+type roleConfigRecord struct {
+	Vserver string `json:"vserver"`
+	PasswordRules
+	PasswdAlphanum string `json:"passwd_alphanum"`
+}
+
+// FetchPasswordRules reads the strictest password rules of the given role over all SVMs of the cluster.
+// The rules are not part of the REST API, they are read with the CLI passthrough.
+func FetchPasswordRules(ctx context.Context, ontapClient *ontapv1.Ontap, role string) (*PasswordRules, error) {
+	if ontapClient.Transport == nil {
+		return nil, errors.New("ontap client has no transport")
+	}
+
+	result, err := ontapClient.Transport.Submit(&runtime.ClientOperation{
+		ID:                 "security_login_role_config_get",
+		Method:             http.MethodGet,
+		PathPattern:        "/private/cli/security/login/role/config",
+		ProducesMediaTypes: []string{"application/json", "application/hal+json"},
+		ConsumesMediaTypes: []string{"application/json", "application/hal+json"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetQueryParam("role", role); err != nil {
+				return err
+			}
+			return r.SetQueryParam("fields", "passwd-minlength,passwd-min-digits,passwd-min-special-chars,passwd-min-uppercase-chars,passwd-min-lowercase-chars,passwd-alphanum")
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
+			if response.Code() != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %d: %s", response.Code(), response.Message())
+			}
+			var records struct {
+				Records []roleConfigRecord `json:"records"`
+			}
+			if err := consumer.Consume(response.Body(), &records); err != nil {
+				return nil, err
+			}
+			return records.Records, nil
+		}),
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password rules of role %s: %w", role, err)
+	}
+
+	records := result.([]roleConfigRecord)
+	// the rules of a role which does not exist yet would pass every policy
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no password rules of role %s found, the role does not exist", role)
+	}
+
+	rules := &PasswordRules{}
+	for _, r := range records {
+		rules.MinLength = max(rules.MinLength, r.MinLength)
+		rules.MinDigits = max(rules.MinDigits, r.MinDigits)
+		rules.MinSymbols = max(rules.MinSymbols, r.MinSymbols)
+		rules.MinUppercase = max(rules.MinUppercase, r.MinUppercase)
+		rules.MinLowercase = max(rules.MinLowercase, r.MinLowercase)
+		rules.Alphanumeric = rules.Alphanumeric || r.PasswdAlphanum == "enabled"
+	}
+	return rules, nil
+}
+
+// ValidatePasswordPolicy returns an error if passwords generated with the policy may violate the rules.
+func ValidatePasswordPolicy(policy *config.PasswordPolicyConfig, role string, rules *PasswordRules) error {
+	if policy == nil {
+		policy = &defaultPasswordPolicy
+	}
+
+	var errs []error
+	if policy.Length < rules.MinLength {
+		errs = append(errs, fmt.Errorf("length %d is shorter than the required %d", policy.Length, rules.MinLength))
+	}
+	if policy.Digits < rules.MinDigits {
+		errs = append(errs, fmt.Errorf("%d digits are less than the required %d", policy.Digits, rules.MinDigits))
+	}
+	if policy.Symbols < rules.MinSymbols {
+		errs = append(errs, fmt.Errorf("%d symbols are less than the required %d", policy.Symbols, rules.MinSymbols))
+	}
+	if policy.Uppercase < rules.MinUppercase {
+		errs = append(errs, fmt.Errorf("%d upper case letters are less than the required %d", policy.Uppercase, rules.MinUppercase))
+	}
+	if policy.Lowercase < rules.MinLowercase {
+		errs = append(errs, fmt.Errorf("%d lower case letters are less than the required %d", policy.Lowercase, rules.MinLowercase))
+	}
+	if rules.Alphanumeric && (policy.Digits == 0 || policy.Uppercase+policy.Lowercase == 0) {
+		errs = append(errs, errors.New("at least one digit and one letter are required"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("password policy violates the password rules of role %s: %w", role, errors.Join(errs...))
+	}
+	return nil
+}
+
+// PasswordPolicyValidator validates the password policy against the password rules of the account role of a cluster
+// before the first password is generated for it. Only successful validations are remembered, the rules of clusters
+// which are unreachable or reject the policy are read again on the next attempt.
+type PasswordPolicyValidator struct {
+	policy *config.PasswordPolicyConfig
+	role   string
+
+	mu        sync.Mutex
+	validated map[*ontapv1.Ontap]bool
+}
+
+// NewPasswordPolicyValidator returns a PasswordPolicyValidator of the given policy and account role.
+func NewPasswordPolicyValidator(policy *config.PasswordPolicyConfig, role string) *PasswordPolicyValidator {
+	return &PasswordPolicyValidator{
+		policy:    policy,
+		role:      role,
+		validated: map[*ontapv1.Ontap]bool{},
+	}
+}
+
+// Validate returns an error if passwords generated with the policy may be rejected by the cluster of the client.
+func (v *PasswordPolicyValidator) Validate(ctx context.Context, ontapClient *ontapv1.Ontap) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.validated[ontapClient] {
+		return nil
+	}
+	rules, err := FetchPasswordRules(ctx, ontapClient, v.role)
+	if err != nil {
+		return err
+	}
+	if err := ValidatePasswordPolicy(v.policy, v.role, rules); err != nil {
+		return err
+	}
+	v.validated[ontapClient] = true
+	return nil
+}
@@ -0,0 +1,150 @@
+package trident
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode"
+
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *config.PasswordPolicyConfig
+	}{
+		{name: "default policy"},
+		{name: "custom policy", policy: &config.PasswordPolicyConfig{Length: 12, Digits: 3, Symbols: 4, Uppercase: 1, SymbolSet: "#@"}},
+		{name: "no symbols", policy: &config.PasswordPolicyConfig{Length: 16, Digits: 2, Lowercase: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			if policy == nil {
+				policy = &defaultPasswordPolicy
+			}
+
+			for range 50 {
+				password, err := generatePassword(tt.policy)
+				require.NoError(t, err)
+				require.Len(t, password, policy.Length)
+
+				var lower, upper, digit, symbol int
+				for _, c := range password {
+					switch {
+					case unicode.IsLower(c):
+						lower++
+					case unicode.IsUpper(c):
+						upper++
+					case unicode.IsDigit(c):
+						digit++
+					default:
+						require.True(t, strings.ContainsRune(policy.SymbolSet, c), "unexpected symbol %q", c)
+						symbol++
+					}
+				}
+				assert.GreaterOrEqual(t, lower, policy.Lowercase)
+				assert.GreaterOrEqual(t, upper, policy.Uppercase)
+				assert.GreaterOrEqual(t, digit, policy.Digits)
+				assert.GreaterOrEqual(t, symbol, policy.Symbols)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *config.PasswordPolicyConfig
+		rules   PasswordRules
+		wantErr string
+	}{
+		{name: "ontap defaults", rules: PasswordRules{MinLength: 8, Alphanumeric: true}},
+		{name: "default policy is too short", rules: PasswordRules{MinLength: 32}, wantErr: "length 24 is shorter than the required 32"},
+		{name: "too few symbols", policy: &config.PasswordPolicyConfig{Length: 24, Symbols: 1, SymbolSet: "#"}, rules: PasswordRules{MinSymbols: 2}, wantErr: "1 symbols are less than the required 2"},
+		{name: "alphanumeric requires digits", policy: &config.PasswordPolicyConfig{Length: 24, Lowercase: 2}, rules: PasswordRules{Alphanumeric: true}, wantErr: "at least one digit and one letter are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePasswordPolicy(tt.policy, vsadminRoleName, &tt.rules)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFetchPasswordRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/private/cli/security/login/role/config", r.URL.Path)
+		assert.Equal(t, TridentRoleName, r.URL.Query().Get("role"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"records":[
+			{"vserver":"svm-1","passwd_minlength":8,"passwd_min_digits":1,"passwd_alphanum":"enabled"},
+			{"vserver":"svm-2","passwd_minlength":12,"passwd_min_special_chars":2,"passwd_alphanum":"disabled"}
+		]}`))
+	}))
+	defer server.Close()
+
+	transport := httptransport.New(strings.TrimPrefix(server.URL, "http://"), "/api", []string{"http"})
+	rules, err := FetchPasswordRules(context.Background(), ontapv1.New(transport, strfmt.Default), TridentRoleName)
+	require.NoError(t, err)
+	assert.Equal(t, &PasswordRules{MinLength: 12, MinDigits: 1, MinSymbols: 2, Alphanumeric: true}, rules)
+}
+
+func TestFetchPasswordRulesOfMissingRole(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"records":[],"num_records":0}`))
+	}))
+	defer server.Close()
+
+	transport := httptransport.New(strings.TrimPrefix(server.URL, "http://"), "/api", []string{"http"})
+	_, err := FetchPasswordRules(context.Background(), ontapv1.New(transport, strfmt.Default), TridentRoleName)
+	require.ErrorContains(t, err, "the role does not exist")
+}
+
+func TestPasswordPolicyValidator(t *testing.T) {
+	var (
+		requests int
+		status   = http.StatusServiceUnavailable
+		records  = `{"records":[{"vserver":"svm-1","passwd_minlength":8}]}`
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(records))
+	}))
+	defer server.Close()
+
+	var (
+		ctx       = context.Background()
+		transport = httptransport.New(strings.TrimPrefix(server.URL, "http://"), "/api", []string{"http"})
+		c         = ontapv1.New(transport, strfmt.Default)
+		v         = NewPasswordPolicyValidator(&config.PasswordPolicyConfig{Length: 12}, TridentRoleName)
+	)
+
+	// an unreachable cluster is validated again on the next attempt
+	require.Error(t, v.Validate(ctx, c))
+
+	status = http.StatusOK
+	records = `{"records":[{"vserver":"svm-1","passwd_minlength":16}]}`
+	require.ErrorContains(t, v.Validate(ctx, c), "length 12 is shorter than the required 16")
+
+	records = `{"records":[{"vserver":"svm-1","passwd_minlength":8}]}`
+	require.NoError(t, v.Validate(ctx, c))
+	require.NoError(t, v.Validate(ctx, c))
+	assert.Equal(t, 3, requests)
+}
@@ -0,0 +1,398 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+// supportedProtocols are the protocols of the TridentConfig in the order the data LIFs of shoots with different
+// protocols are assigned to them.
+var supportedProtocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI, ontapv1alpha1.ProtocolNFS}
+
+// managementServicePolicy is the built-in service policy of the management LIF.
+const managementServicePolicy = "default-management"
+
+// dataServicePolicies are the built-in service policies of the data LIFs for each protocol.
+var dataServicePolicies = map[ontapv1alpha1.Protocol]string{
+	ontapv1alpha1.ProtocolNVMe:  "default-data-nvme-tcp",
+	ontapv1alpha1.ProtocolISCSI: "default-data-blocks",
+	ontapv1alpha1.ProtocolNFS:   "default-data-files",
+}
+
+// protocolPorts are the TCP ports of the data LIFs for each protocol, NFS includes portmapper, mountd, NLM and NSM of NFSv3.
+var protocolPorts = map[ontapv1alpha1.Protocol][]int{
+	ontapv1alpha1.ProtocolNVMe:  {4420},
+	ontapv1alpha1.ProtocolISCSI: {3260},
+	ontapv1alpha1.ProtocolNFS:   {111, 635, 2049, 4045, 4046},
+}
+
+// protocolsOrDefault returns the given protocols or the default protocols if none are given.
+func protocolsOrDefault(protocols []ontapv1alpha1.Protocol) []ontapv1alpha1.Protocol {
+	if len(protocols) == 0 {
+		return ontapv1alpha1.DefaultProtocols
+	}
+	return protocols
+}
+
+// dataLIFProtocol returns the protocol the data LIF with the given index serves, the data LIFs are assigned to the
+// protocols in turn.
+func dataLIFProtocol(protocols []ontapv1alpha1.Protocol, index int) ontapv1alpha1.Protocol {
+	protocols = protocolsOrDefault(protocols)
+	return protocols[index%len(protocols)]
+}
+
+// ProjectProtocols returns the protocols of the SVM shared by the shoots with the given provider configs. If all shoots
+// configure the same protocols their order is kept, otherwise the union of the protocols is ordered like
+// supportedProtocols, so every shoot of the project derives the same assignment of the data LIFs.
+func ProjectProtocols(configs []*ontapv1alpha1.TridentConfig) []ontapv1alpha1.Protocol {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	first := protocolsOrDefault(configs[0].Protocols)
+	same := true
+	used := map[ontapv1alpha1.Protocol]bool{}
+	for _, config := range configs {
+		protocols := protocolsOrDefault(config.Protocols)
+		same = same && slices.Equal(protocols, first)
+		for _, p := range protocols {
+			used[p] = true
+		}
+	}
+	if same {
+		return first
+	}
+
+	var protocols []ontapv1alpha1.Protocol
+	for _, p := range supportedProtocols {
+		if used[p] {
+			protocols = append(protocols, p)
+		}
+	}
+	return protocols
+}
+
+// dataLIFProtocols returns the protocols the data LIFs serve, current are the protocols the LIFs serve at the moment
+// and empty for missing LIFs or LIFs with an unknown service policy. LIFs keep their protocol as long as a shoot of
+// the project uses it, only LIFs of unused protocols are reassigned. A protocol without LIF takes a LIF of a block
+// protocol which has more than one, the LIFs of NFS are never taken because Trident binds the NAS backends to a
+// single LIF. The returned protocols are empty for LIFs which are not assigned to any protocol, protocols without
+// LIF are returned as unserved.
+func dataLIFProtocols(current, protocols []ontapv1alpha1.Protocol) (assigned, unserved []ontapv1alpha1.Protocol) {
+	protocols = protocolsOrDefault(protocols)
+
+	assigned = make([]ontapv1alpha1.Protocol, len(current))
+	served := map[ontapv1alpha1.Protocol]int{}
+	for i, p := range current {
+		if slices.Contains(protocols, p) {
+			assigned[i] = p
+			served[p]++
+		}
+	}
+	for _, p := range protocols {
+		if served[p] == 0 {
+			unserved = append(unserved, p)
+		}
+	}
+
+	// free LIFs serve the protocols without LIF first and the protocols in turn afterwards
+	for i := range assigned {
+		if assigned[i] != "" {
+			continue
+		}
+		p := dataLIFProtocol(protocols, i)
+		if len(unserved) > 0 {
+			p, unserved = unserved[0], unserved[1:]
+		}
+		assigned[i] = p
+		served[p]++
+	}
+
+	// the LIF with the highest index whose protocol keeps another LIF is taken, a LIF which is assigned to the
+	// protocol in turn is preferred
+	for len(unserved) > 0 {
+		p := unserved[0]
+		taken := -1
+		for i := len(assigned) - 1; i >= 0; i-- {
+			if assigned[i] == ontapv1alpha1.ProtocolNFS || served[assigned[i]] < 2 {
+				continue
+			}
+			if taken < 0 || dataLIFProtocol(protocols, i) == p && dataLIFProtocol(protocols, taken) != p {
+				taken = i
+			}
+		}
+		if taken < 0 {
+			break
+		}
+		served[assigned[taken]]--
+		assigned[taken] = p
+		served[p]++
+		unserved = unserved[1:]
+	}
+	return assigned, unserved
+}
+
+// dataLIFProtocolOf returns the protocol the data LIF with the given service policy serves, it is empty for unknown
+// service policies.
+func dataLIFProtocolOf(servicePolicy string) ontapv1alpha1.Protocol {
+	for p, policy := range dataServicePolicies {
+		if policy == servicePolicy {
+			return p
+		}
+	}
+	return ""
+}
+
+// dataPorts returns the TCP ports of the data LIFs for the given protocols.
+func dataPorts(protocols []ontapv1alpha1.Protocol) []int {
+	var ports []int
+	for _, p := range protocolsOrDefault(protocols) {
+		ports = append(ports, protocolPorts[p]...)
+	}
+	return ports
+}
+
+// tridentBackends returns a TridentBackendConfig for each protocol and the StorageClasses of the catalog. The NVMe
+// backend keeps the names of earlier versions, the names of the other backends are suffixed with the protocol.
+func tridentBackends(values DeployTridentValues) backends.Backends {
+	backendName := values.BackendName
+	if backendName == "" {
+		backendName = legacyNaming.BackendName(values.ProjectId)
+	}
+	backendConfigName := values.BackendConfigName
+	if backendConfigName == "" {
+		backendConfigName = BackendConfigName(values.ProjectId, nil)
+	}
+
+	protocols := protocolsOrDefault(values.Protocols)
+	classes, builtin := values.StorageClasses, false
+	if len(classes) == 0 {
+		classes, builtin = builtinStorageClasses(protocols), true
+	}
+
+	result := backends.Backends{
+		ManagementLif: values.SvmIpAddresses.ManagementLif,
+		SecretName:    *values.SeedsecretName,
+	}
+	if values.StoragePrefix != LegacyStoragePrefix {
+		result.StoragePrefix = values.StoragePrefix
+	}
+	for _, p := range protocols {
+		opts := newPoolOptions(p, protocols[0], classes, values.ProjectId, values.QoSTiers)
+		opts.snapshotPolicies = tierSnapshotPolicies(values.Namespace, values.Snapshots)
+		backend := backends.Backend{
+			ConfigName:      backendConfigName + backendSuffix(p),
+			Name:            backendName + backendSuffix(p),
+			Pools:           storagePools(opts, classes),
+			SnapshotReserve: snapshotReserve(values.Snapshots),
+			SVM:             values.SVMName,
+		}
+		if values.Snapshots != nil && values.Snapshots.Policy != nil {
+			backend.SnapshotPolicy = SnapshotPolicyName(values.Namespace, "")
+		}
+		switch p {
+		case ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI:
+			backend.StorageDriverName = "ontap-san"
+			backend.SANType = string(p)
+		case ontapv1alpha1.ProtocolNFS:
+			backend.StorageDriverName = "ontap-nas"
+			backend.ExportPolicy = values.ExportPolicy
+		}
+		result.Backends = append(result.Backends, backend)
+	}
+	for _, sc := range classes {
+		p := storageClassProtocol(sc, protocols[0])
+		if !slices.Contains(protocols, p) {
+			continue
+		}
+		rendered := storageClass(sc, newPoolOptions(p, protocols[0], classes, values.ProjectId, values.QoSTiers))
+		rendered.NoCleanup = builtin && sc.Name == noCleanupStorageClass
+		result.StorageClasses = append(result.StorageClasses, rendered)
+	}
+	if values.Replica != nil {
+		for _, p := range replicaProtocols(protocols) {
+			result.Backends = append(result.Backends, replicaBackend(p, backendConfigName, backendName, values.Replica))
+			result.StorageClasses = append(result.StorageClasses, replicaStorageClass(p, values.Replica))
+		}
+	}
+	return result
+}
+
+// BackendConfigNames returns the names of the TridentBackendConfigs of the SVM of the project, one per protocol. The
+// backends of the secondary SVM are omitted.
+func BackendConfigNames(values DeployTridentValues) []string {
+	var names []string
+	for _, backend := range tridentBackends(values).Backends {
+		if backend.ManagementLif == "" {
+			names = append(names, backend.ConfigName)
+		}
+	}
+	return names
+}
+
+// backendSuffix returns the suffix of the backend names of the protocol.
+func backendSuffix(p ontapv1alpha1.Protocol) string {
+	if p == ontapv1alpha1.ProtocolNVMe {
+		return ""
+	}
+	return "-" + string(p)
+}
+
+// servicePolicy returns the service policy of the LIF.
+func (o networkInterfaceOptions) servicePolicy() string {
+	if !o.isDataLif {
+		return managementServicePolicy
+	}
+	if policy, ok := dataServicePolicies[o.protocol]; ok {
+		return policy
+	}
+	return dataServicePolicies[ontapv1alpha1.ProtocolNVMe]
+}
+
+// svmProtocols returns the protocol settings of an SVM which has all given protocols enabled.
+func svmProtocols(protocols []ontapv1alpha1.Protocol) *models.Svm {
+	svm := &models.Svm{}
+	for _, p := range protocolsOrDefault(protocols) {
+		switch p {
+		case ontapv1alpha1.ProtocolNVMe:
+			svm.Nvme = &models.SvmInlineNvme{Enabled: new(true), Allowed: new(true)}
+		case ontapv1alpha1.ProtocolISCSI:
+			svm.Iscsi = &models.SvmInlineIscsi{Enabled: new(true), Allowed: new(true)}
+		case ontapv1alpha1.ProtocolNFS:
+			svm.Nfs = &models.SvmInlineNfs{Enabled: new(true), Allowed: new(true)}
+		}
+	}
+	return svm
+}
+
+// protocolEnabled returns whether the given protocol is enabled on the SVM.
+func protocolEnabled(svm *models.Svm, p ontapv1alpha1.Protocol) bool {
+	if svm == nil {
+		return false
+	}
+	var enabled *bool
+	switch p {
+	case ontapv1alpha1.ProtocolNVMe:
+		if svm.Nvme != nil {
+			enabled = svm.Nvme.Enabled
+		}
+	case ontapv1alpha1.ProtocolISCSI:
+		if svm.Iscsi != nil {
+			enabled = svm.Iscsi.Enabled
+		}
+	case ontapv1alpha1.ProtocolNFS:
+		if svm.Nfs != nil {
+			enabled = svm.Nfs.Enabled
+		}
+	}
+	return enabled != nil && *enabled
+}
+
+// disabledProtocols returns the given protocols which are not enabled on the SVM.
+func disabledProtocols(svm *models.Svm, protocols []ontapv1alpha1.Protocol) []ontapv1alpha1.Protocol {
+	var disabled []ontapv1alpha1.Protocol
+	for _, p := range protocolsOrDefault(protocols) {
+		if !protocolEnabled(svm, p) {
+			disabled = append(disabled, p)
+		}
+	}
+	return disabled
+}
+
+// ensureSVMProtocols enables the protocols which were added to the TridentConfig after the SVM was created.
+func (m *SvmManager) ensureSVMProtocols(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, protocols []ontapv1alpha1.Protocol) error {
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(svmUUID)
+	getParams.SetFields([]string{"nvme.enabled", "iscsi.enabled", "nfs.enabled"})
+
+	svmInfo, err := ontapClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM protocols: %w", err)
+	}
+
+	disabled := disabledProtocols(svmInfo.Payload, protocols)
+	if len(disabled) == 0 {
+		return nil
+	}
+
+	m.log.Info("Enabling protocols on SVM", "svmName", svmName, "protocols", disabled)
+	modifyParams := s_vm.NewSvmModifyParamsWithContext(ctx)
+	modifyParams.SetUUID(svmUUID)
+	modifyParams.SetInfo(svmProtocols(disabled))
+	if _, _, err := ontapClient.SVM.SvmModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to enable protocols %v on SVM %s: %w", disabled, svmName, err)
+	}
+	m.recorder.Normal(ctx, events.ReasonSVMProtocolsEnabled, events.ActionUpdate, "protocols %v enabled on SVM %s", disabled, svmName)
+	return nil
+}
+
+// ensureDataLIFServicePolicies moves existing data LIFs to the service policy of their protocol, the assignment of the
+// data LIFs changes if protocols are added to or removed from the shoots of the project. The given protocols are the
+// protocols of all shoots sharing the SVM, LIFs of protocols which are still in use are never moved.
+func (m *SvmManager) ensureDataLIFServicePolicies(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, naming *Naming, expectedDataLifs []string, protocols []ontapv1alpha1.Protocol) error {
+	params := networking.NewNetworkIPInterfacesGetParamsWithContext(ctx)
+	params.SetSvmUUID(&svmUUID)
+	params.SetFields([]string{"name", "uuid", "service_policy.name"})
+
+	result, err := ontapClient.Networking.NetworkIPInterfacesGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get network interfaces: %w", err)
+	}
+
+	interfaces := map[string]*models.IPInterface{}
+	if result.Payload != nil {
+		for _, intf := range result.Payload.IPInterfaceResponseInlineRecords {
+			if intf.Name != nil {
+				interfaces[*intf.Name] = intf
+			}
+		}
+	}
+
+	lifs := make([]*models.IPInterface, len(expectedDataLifs))
+	current := make([]ontapv1alpha1.Protocol, len(expectedDataLifs))
+	for i := range expectedDataLifs {
+		for _, name := range append([]string{naming.DataLIFName(svmName, i)}, naming.DataLIFAliases(svmName, i)...) {
+			if found, ok := interfaces[name]; ok {
+				lifs[i] = found
+				break
+			}
+		}
+		// missing LIFs are created with the right policy, a missing policy is not reported by all ONTAP versions
+		if intf := lifs[i]; intf == nil || intf.UUID == nil || intf.ServicePolicy == nil || intf.ServicePolicy.Name == nil {
+			lifs[i] = nil
+			continue
+		}
+		current[i] = dataLIFProtocolOf(*lifs[i].ServicePolicy.Name)
+	}
+
+	assigned, unserved := dataLIFProtocols(current, protocols)
+	for i, intf := range lifs {
+		if intf == nil || assigned[i] == current[i] {
+			continue
+		}
+		policy := networkInterfaceOptions{isDataLif: true, protocol: assigned[i]}.servicePolicy()
+
+		m.log.Info("Changing service policy of data LIF", "lifName", *intf.Name, "from", *intf.ServicePolicy.Name, "to", policy)
+		modifyParams := networking.NewNetworkIPInterfaceModifyParamsWithContext(ctx)
+		modifyParams.SetUUID(*intf.UUID)
+		modifyParams.SetInfo(&models.IPInterface{ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new(policy)}})
+		if _, err := ontapClient.Networking.NetworkIPInterfaceModify(modifyParams, nil); err != nil {
+			return fmt.Errorf("failed to change service policy of data LIF %s: %w", *intf.Name, err)
+		}
+		m.recorder.Normal(ctx, events.ReasonLIFUpdated, events.ActionUpdate, "service policy of data LIF %s on SVM %s changed to %s", *intf.Name, svmName, policy)
+	}
+	if len(unserved) > 0 {
+		return fmt.Errorf("no data LIF of SVM %s is left for the protocols %v, the shoots of the project need at least one data LIF per protocol", svmName, unserved)
+	}
+	return nil
+}
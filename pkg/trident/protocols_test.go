@@ -0,0 +1,283 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestDataLIFProtocol(t *testing.T) {
+	protocols := []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolNFS}
+
+	var policies []string
+	for i := range 4 {
+		policies = append(policies, networkInterfaceOptions{isDataLif: true, protocol: dataLIFProtocol(protocols, i)}.servicePolicy())
+	}
+	assert.Equal(t, []string{"default-data-nvme-tcp", "default-data-files", "default-data-nvme-tcp", "default-data-files"}, policies)
+
+	assert.Equal(t, ontapv1alpha1.ProtocolNVMe, dataLIFProtocol(nil, 3))
+	assert.Equal(t, "default-management", networkInterfaceOptions{protocol: ontapv1alpha1.ProtocolNFS}.servicePolicy())
+	assert.Equal(t, []int{4420, 3260}, dataPorts([]ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI}))
+}
+
+func TestProjectProtocols(t *testing.T) {
+	var (
+		nvme  = ontapv1alpha1.ProtocolNVMe
+		iscsi = ontapv1alpha1.ProtocolISCSI
+		nfs   = ontapv1alpha1.ProtocolNFS
+	)
+
+	assert.Nil(t, ProjectProtocols(nil))
+	assert.Equal(t, ontapv1alpha1.DefaultProtocols, ProjectProtocols([]*ontapv1alpha1.TridentConfig{{}}))
+	// the configured order is kept if the shoots agree
+	assert.Equal(t, []ontapv1alpha1.Protocol{nfs, nvme}, ProjectProtocols([]*ontapv1alpha1.TridentConfig{
+		{Protocols: []ontapv1alpha1.Protocol{nfs, nvme}},
+		{Protocols: []ontapv1alpha1.Protocol{nfs, nvme}},
+	}))
+	// the union does not depend on the order of the shoots
+	for _, configs := range [][]*ontapv1alpha1.TridentConfig{
+		{{Protocols: []ontapv1alpha1.Protocol{nfs}}, {Protocols: []ontapv1alpha1.Protocol{iscsi, nvme}}},
+		{{Protocols: []ontapv1alpha1.Protocol{iscsi, nvme}}, {Protocols: []ontapv1alpha1.Protocol{nfs}}},
+	} {
+		assert.Equal(t, []ontapv1alpha1.Protocol{nvme, iscsi, nfs}, ProjectProtocols(configs))
+	}
+}
+
+func TestDataLIFProtocols(t *testing.T) {
+	var (
+		nvme  = ontapv1alpha1.ProtocolNVMe
+		iscsi = ontapv1alpha1.ProtocolISCSI
+		nfs   = ontapv1alpha1.ProtocolNFS
+	)
+
+	tests := []struct {
+		name         string
+		current      []ontapv1alpha1.Protocol
+		protocols    []ontapv1alpha1.Protocol
+		wantAssigned []ontapv1alpha1.Protocol
+		wantUnserved []ontapv1alpha1.Protocol
+	}{
+		{
+			name:         "new LIFs are assigned in turn",
+			current:      []ontapv1alpha1.Protocol{"", "", ""},
+			protocols:    []ontapv1alpha1.Protocol{nvme, nfs},
+			wantAssigned: []ontapv1alpha1.Protocol{nvme, nfs, nvme},
+		},
+		{
+			name:         "LIFs of used protocols keep their protocol",
+			current:      []ontapv1alpha1.Protocol{nfs, nvme},
+			protocols:    []ontapv1alpha1.Protocol{nvme, nfs},
+			wantAssigned: []ontapv1alpha1.Protocol{nfs, nvme},
+		},
+		{
+			name:         "LIFs of unused protocols serve the protocols without LIF",
+			current:      []ontapv1alpha1.Protocol{nvme, iscsi},
+			protocols:    []ontapv1alpha1.Protocol{nvme, nfs},
+			wantAssigned: []ontapv1alpha1.Protocol{nvme, nfs},
+		},
+		{
+			name:         "added protocol takes a surplus block LIF",
+			current:      []ontapv1alpha1.Protocol{nvme, nvme},
+			protocols:    []ontapv1alpha1.Protocol{nvme, nfs},
+			wantAssigned: []ontapv1alpha1.Protocol{nvme, nfs},
+		},
+		{
+			name:         "NFS LIFs are never taken",
+			current:      []ontapv1alpha1.Protocol{nfs, nfs},
+			protocols:    []ontapv1alpha1.Protocol{nfs, nvme},
+			wantAssigned: []ontapv1alpha1.Protocol{nfs, nfs},
+			wantUnserved: []ontapv1alpha1.Protocol{nvme},
+		},
+		{
+			name:         "the only LIF of a protocol is never taken",
+			current:      []ontapv1alpha1.Protocol{nvme, nfs},
+			protocols:    []ontapv1alpha1.Protocol{nvme, iscsi, nfs},
+			wantAssigned: []ontapv1alpha1.Protocol{nvme, nfs},
+			wantUnserved: []ontapv1alpha1.Protocol{iscsi},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assigned, unserved := dataLIFProtocols(tt.current, tt.protocols)
+			assert.Equal(t, tt.wantAssigned, assigned)
+			assert.ElementsMatch(t, tt.wantUnserved, unserved)
+		})
+	}
+}
+
+func TestTridentBackends(t *testing.T) {
+	secret := "p1-credentials"
+	values := DeployTridentValues{ProjectId: "p1", SeedsecretName: &secret}
+
+	t.Run("nvme keeps the names of earlier versions", func(t *testing.T) {
+		got := tridentBackends(values)
+		require.Len(t, got.Backends, 1)
+		assert.Equal(t, "ontap-p1-backend", got.Backends[0].ConfigName)
+		assert.Equal(t, "ontap-p1", got.Backends[0].Name)
+		assert.Equal(t, "nvme", got.Backends[0].SANType)
+
+		var classes []string
+		for _, sc := range got.StorageClasses {
+			classes = append(classes, sc.Name)
+		}
+		assert.Equal(t, []string{"ontap-gold", "ontap-encrypted"}, classes)
+	})
+
+	t.Run("backend per protocol", func(t *testing.T) {
+		values := values
+		values.Protocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolISCSI, ontapv1alpha1.ProtocolNFS}
+
+		got := tridentBackends(values)
+		require.Len(t, got.Backends, 2)
+		assert.Equal(t, "ontap-p1-backend-iscsi", got.Backends[0].ConfigName)
+		assert.Equal(t, "iscsi", got.Backends[0].SANType)
+		assert.Equal(t, "ontap-p1-backend-nfs", got.Backends[1].ConfigName)
+		assert.Equal(t, "ontap-nas", got.Backends[1].StorageDriverName)
+
+		var classes []string
+		for _, sc := range got.StorageClasses {
+			classes = append(classes, sc.Name)
+		}
+		assert.Equal(t, []string{"ontap-gold-iscsi", "ontap-encrypted-iscsi", "ontap-nfs"}, classes)
+	})
+
+	t.Run("replica backend per block protocol", func(t *testing.T) {
+		values := values
+		values.Protocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI, ontapv1alpha1.ProtocolNFS}
+		values.Replica = &ReplicaValues{
+			SeedsecretName: "p1-replica-credentials",
+			SvmIpAddresses: ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.1.1", DataLifs: []string{"10.0.1.2", "10.0.1.3"}},
+			StorageClass:   ontapv1alpha1.DefaultReplicaStorageClass,
+		}
+
+		got := tridentBackends(values)
+		require.Len(t, got.Backends, 5)
+		for i, want := range []string{"ontap-p1-backend-replica", "ontap-p1-backend-replica-iscsi"} {
+			backend := got.Backends[3+i]
+			assert.Equal(t, want, backend.ConfigName)
+			assert.Equal(t, "10.0.1.1", backend.ManagementLif)
+			assert.Equal(t, "p1-replica-credentials", backend.SecretName)
+		}
+		assert.Equal(t, []backends.Pool{{Label: "replica", Value: "iscsi"}}, got.Backends[4].Pools)
+
+		var classes []string
+		for _, sc := range got.StorageClasses {
+			classes = append(classes, sc.Name)
+		}
+		assert.Equal(t, []string{"ontap-gold", "ontap-encrypted", "ontap-gold-iscsi", "ontap-encrypted-iscsi", "ontap-nfs", "ontap-replica", "ontap-replica-iscsi"}, classes)
+		assert.Equal(t, "replica=iscsi", got.StorageClasses[6].Selector)
+	})
+
+	t.Run("config names of the backends of the SVM", func(t *testing.T) {
+		values := values
+		values.Protocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolISCSI}
+		values.Replica = &ReplicaValues{SeedsecretName: "p1-replica-credentials", SvmIpAddresses: ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.1.1"}}
+		assert.Equal(t, []string{"ontap-p1-backend-iscsi"}, BackendConfigNames(values))
+
+		values.Protocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolNFS}
+		assert.Equal(t, []string{"ontap-p1-backend", "ontap-p1-backend-nfs"}, BackendConfigNames(values))
+	})
+
+	t.Run("legacy storage prefix is not rendered", func(t *testing.T) {
+		values := values
+		values.StoragePrefix = LegacyStoragePrefix
+		assert.Empty(t, tridentBackends(values).StoragePrefix)
+
+		values.StoragePrefix = "proj__myshoot_"
+		assert.Equal(t, "proj__myshoot_", tridentBackends(values).StoragePrefix)
+	})
+}
+
+func TestEnsureSVMProtocols(t *testing.T) {
+	ctx := context.Background()
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
+	protocols := []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI}
+
+	t.Run("enables added protocols", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{Nvme: &models.SvmInlineNvme{Enabled: new(true)}}}, nil)
+		mc.svm.On("SvmModify", mock.Anything, mock.Anything).Return(&s_vm.SvmModifyOK{}, nil, nil)
+
+		require.NoError(t, m.ensureSVMProtocols(ctx, mc.client, "svm-uuid", "proj-1", protocols))
+
+		p := mc.svm.Calls[1].Arguments[0].(*s_vm.SvmModifyParams)
+		assert.Nil(t, p.Info.Nvme)
+		require.NotNil(t, p.Info.Iscsi)
+		assert.True(t, *p.Info.Iscsi.Enabled)
+	})
+
+	t.Run("no-op when all protocols are enabled", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{
+				Nvme:  &models.SvmInlineNvme{Enabled: new(true)},
+				Iscsi: &models.SvmInlineIscsi{Enabled: new(true)},
+			}}, nil)
+
+		require.NoError(t, m.ensureSVMProtocols(ctx, mc.client, "svm-uuid", "proj-1", protocols))
+		mc.svm.AssertNotCalled(t, "SvmModify", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEnsureDataLIFServicePolicies(t *testing.T) {
+	ctx := context.Background()
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
+
+	mc := newMockOntapClient()
+	mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+		Return(&networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{
+			IPInterfaceResponseInlineRecords: []*models.IPInterface{
+				{Name: new("datalif+0"), UUID: new("lif-0"), ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new("default-data-nvme-tcp")}},
+				{Name: new("datalif+1"), UUID: new("lif-1"), ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new("default-data-nvme-tcp")}},
+				{Name: new("managementlif"), UUID: new("lif-m"), ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new("default-management")}},
+			},
+		}}, nil)
+	mc.networking.On("NetworkIPInterfaceModify", mock.Anything, mock.Anything).Return(&networking.NetworkIPInterfaceModifyOK{}, nil)
+
+	protocols := []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolNFS}
+	require.NoError(t, m.ensureDataLIFServicePolicies(ctx, mc.client, "svm-uuid", "proj-1", nil, []string{"10.0.0.2", "10.0.0.3"}, protocols))
+
+	mc.networking.AssertNumberOfCalls(t, "NetworkIPInterfaceModify", 1)
+	p := mc.networking.Calls[1].Arguments[0].(*networking.NetworkIPInterfaceModifyParams)
+	assert.Equal(t, "lif-1", p.UUID)
+	assert.Equal(t, "default-data-files", *p.Info.ServicePolicy.Name)
+}
+
+func TestEnsureDataLIFServicePoliciesKeepsUsedLIFs(t *testing.T) {
+	ctx := context.Background()
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
+
+	mc := newMockOntapClient()
+	mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+		Return(&networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{
+			IPInterfaceResponseInlineRecords: []*models.IPInterface{
+				{Name: new("datalif+0"), UUID: new("lif-0"), ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new("default-data-nvme-tcp")}},
+				{Name: new("datalif+1"), UUID: new("lif-1"), ServicePolicy: &models.IPInterfaceInlineServicePolicy{Name: new("default-data-files")}},
+			},
+		}}, nil)
+
+	// the shoot only uses NVMe, another shoot of the project still uses the NFS LIF
+	protocols := ProjectProtocols([]*ontapv1alpha1.TridentConfig{
+		{Protocols: []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe}},
+		{Protocols: []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolNFS}},
+	})
+	require.NoError(t, m.ensureDataLIFServicePolicies(ctx, mc.client, "svm-uuid", "proj-1", nil, []string{"10.0.0.2", "10.0.0.3"}, protocols))
+	mc.networking.AssertNotCalled(t, "NetworkIPInterfaceModify", mock.Anything, mock.Anything)
+
+	// a protocol without LIF is reported instead of moving the NFS LIF
+	err := m.ensureDataLIFServicePolicies(ctx, mc.client, "svm-uuid", "proj-1", nil, []string{"10.0.0.2", "10.0.0.3"},
+		[]ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolISCSI, ontapv1alpha1.ProtocolNFS})
+	require.ErrorContains(t, err, "no data LIF of SVM proj-1 is left for the protocols [iscsi]")
+	mc.networking.AssertNotCalled(t, "NetworkIPInterfaceModify", mock.Anything, mock.Anything)
+}
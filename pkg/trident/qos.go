@@ -0,0 +1,155 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// QoSPolicyName returns the name of the QoS policy group of the tier on the SVM, the names of QoS policy groups are
+// unique within the cluster.
+func QoSPolicyName(svmName, tier string) string {
+	return svmName + "-qos-" + tier
+}
+
+// ShootQoSTiers returns the tiers the StorageClasses reference.
+func ShootQoSTiers(classes []ontapv1alpha1.StorageClass, tiers []config.QoSTier) ([]config.QoSTier, error) {
+	var result []config.QoSTier
+	for _, sc := range classes {
+		if sc.QoSTier == "" || slices.ContainsFunc(result, func(t config.QoSTier) bool { return t.Name == sc.QoSTier }) {
+			continue
+		}
+		i := slices.IndexFunc(tiers, func(t config.QoSTier) bool { return t.Name == sc.QoSTier })
+		if i < 0 {
+			return nil, fmt.Errorf("storage class %q references unknown QoS tier %q", sc.Name, sc.QoSTier)
+		}
+		result = append(result, tiers[i])
+	}
+	return result, nil
+}
+
+// qosPolicy returns the limits of the QoS policy group of the tier. Fixed limits are not shared, each volume gets the
+// limits on its own.
+func qosPolicy(tier config.QoSTier) *models.QosPolicy {
+	if a := tier.Adaptive; a != nil {
+		adaptive := &models.QosPolicyInlineAdaptive{
+			ExpectedIops: new(a.ExpectedIOPSPerTB),
+			PeakIops:     new(a.PeakIOPSPerTB),
+		}
+		if a.AbsoluteMinIOPS > 0 {
+			adaptive.AbsoluteMinIops = new(a.AbsoluteMinIOPS)
+		}
+		return &models.QosPolicy{Adaptive: adaptive}
+	}
+
+	f := tier.Fixed
+	return &models.QosPolicy{Fixed: &models.QosPolicyInlineFixed{
+		CapacityShared:    new(false),
+		MaxThroughputIops: new(f.MaxIOPS),
+		MinThroughputIops: new(f.MinIOPS),
+		MaxThroughputMbps: new(f.MaxThroughputMBps),
+		MinThroughputMbps: new(f.MinThroughputMBps),
+	}}
+}
+
+// qosPolicyMatches returns whether the QoS policy group has the limits of the tier, unset limits are zero.
+func qosPolicyMatches(actual *models.QosPolicy, tier config.QoSTier) bool {
+	value := func(v *int64) int64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	if a := tier.Adaptive; a != nil {
+		return actual.Adaptive != nil &&
+			value(actual.Adaptive.ExpectedIops) == a.ExpectedIOPSPerTB &&
+			value(actual.Adaptive.PeakIops) == a.PeakIOPSPerTB &&
+			value(actual.Adaptive.AbsoluteMinIops) == a.AbsoluteMinIOPS
+	}
+	f := tier.Fixed
+	return actual.Fixed != nil &&
+		value(actual.Fixed.MaxThroughputIops) == f.MaxIOPS &&
+		value(actual.Fixed.MinThroughputIops) == f.MinIOPS &&
+		value(actual.Fixed.MaxThroughputMbps) == f.MaxThroughputMBps &&
+		value(actual.Fixed.MinThroughputMbps) == f.MinThroughputMBps
+}
+
+// EnsureQoSPolicies creates the QoS policy groups of the tiers on the SVM of the shoot and updates the limits of
+// existing policy groups. Policy groups are not removed, other shoots of the project may still use them.
+func (m *SvmManager) EnsureQoSPolicies(ctx context.Context, opts CreateSVMOptions, tiers []config.QoSTier) (err error) {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "EnsureQoSPolicies")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	for _, tier := range tiers {
+		if err := m.ensureQoSPolicy(ctx, ontapClient, *svmUUID, opts.ProjectID, tier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *SvmManager) ensureQoSPolicy(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, tier config.QoSTier) error {
+	name := QoSPolicyName(svmName, tier.Name)
+	getParams := storage.NewQosPolicyCollectionGetParamsWithContext(ctx)
+	getParams.SetSvmUUID(&svmUUID)
+	getParams.SetName(&name)
+	getParams.SetFields([]string{"uuid", "name", "fixed", "adaptive"})
+
+	result, err := ontapClient.Storage.QosPolicyCollectionGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get QoS policy %s: %w", name, err)
+	}
+
+	want := qosPolicy(tier)
+	if result.Payload == nil || len(result.Payload.QosPolicyResponseInlineRecords) == 0 {
+		m.log.Info("Creating QoS policy", "svm", svmName, "policy", name)
+		want.Name = new(name)
+		want.Svm = &models.QosPolicyInlineSvm{UUID: new(svmUUID)}
+		createParams := storage.NewQosPolicyCreateParamsWithContext(ctx)
+		createParams.SetInfo(want)
+		if _, _, err := ontapClient.Storage.QosPolicyCreate(createParams, nil); err != nil {
+			return fmt.Errorf("failed to create QoS policy %s on SVM %s: %w", name, svmName, err)
+		}
+		m.recorder.Normal(ctx, events.ReasonQoSPolicyCreated, events.ActionCreate, "QoS policy %s of tier %s created on SVM %s", name, tier.Name, svmName)
+		return nil
+	}
+
+	policy := result.Payload.QosPolicyResponseInlineRecords[0]
+	if qosPolicyMatches(policy, tier) {
+		return nil
+	}
+	if (policy.Adaptive != nil) != (tier.Adaptive != nil) {
+		return fmt.Errorf("QoS policy %s on SVM %s cannot be switched between fixed and adaptive limits, it must be removed manually", name, svmName)
+	}
+	if policy.UUID == nil {
+		return fmt.Errorf("QoS policy %s on SVM %s has no uuid", name, svmName)
+	}
+
+	m.log.Info("Updating limits of QoS policy", "svm", svmName, "policy", name)
+	modifyParams := storage.NewQosPolicyModifyParamsWithContext(ctx)
+	modifyParams.SetUUID(*policy.UUID)
+	modifyParams.SetInfo(want)
+	if _, _, err := ontapClient.Storage.QosPolicyModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to update QoS policy %s on SVM %s: %w", name, svmName, err)
+	}
+	m.recorder.Normal(ctx, events.ReasonQoSPolicyUpdated, events.ActionUpdate, "limits of QoS policy %s on SVM %s changed to tier %s", name, svmName, tier.Name)
+	return nil
+}
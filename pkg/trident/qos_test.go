@@ -0,0 +1,97 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestShootQoSTiers(t *testing.T) {
+	tiers := []config.QoSTier{
+		{Name: "gold", Fixed: &config.FixedQoS{MaxIOPS: 5000}},
+		{Name: "silver", Adaptive: &config.AdaptiveQoS{ExpectedIOPSPerTB: 1000, PeakIOPSPerTB: 2000}},
+	}
+
+	got, err := ShootQoSTiers([]ontapv1alpha1.StorageClass{{Name: "a", QoSTier: "gold"}, {Name: "b"}, {Name: "c", QoSTier: "gold"}}, tiers)
+	require.NoError(t, err)
+	assert.Equal(t, tiers[:1], got)
+
+	_, err = ShootQoSTiers([]ontapv1alpha1.StorageClass{{Name: "a", QoSTier: "bronze"}}, tiers)
+	require.ErrorContains(t, err, `unknown QoS tier "bronze"`)
+}
+
+func TestEnsureQoSPolicies(t *testing.T) {
+	ctx := context.Background()
+	opts := CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot"}
+	gold := config.QoSTier{Name: "gold", Fixed: &config.FixedQoS{MaxIOPS: 5000, MaxThroughputMBps: 200}}
+
+	newClient := func(policies ...*models.QosPolicy) *mockOntapClient {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		mc.storage.On("QosPolicyCollectionGet", mock.MatchedBy(func(p *storage.QosPolicyCollectionGetParams) bool {
+			return *p.Name == "proj-1-qos-gold" && *p.SvmUUID == "svm-uuid"
+		}), mock.Anything).
+			Return(&storage.QosPolicyCollectionGetOK{Payload: &models.QosPolicyResponse{QosPolicyResponseInlineRecords: policies}}, nil)
+		return mc
+	}
+
+	t.Run("creates missing policy", func(t *testing.T) {
+		mc := newClient()
+		mc.storage.On("QosPolicyCreate", mock.Anything, mock.Anything).Return(&storage.QosPolicyCreateCreated{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureQoSPolicies(ctx, opts, []config.QoSTier{gold}))
+
+		p := mc.storage.Calls[1].Arguments[0].(*storage.QosPolicyCreateParams)
+		assert.Equal(t, "proj-1-qos-gold", *p.Info.Name)
+		assert.Equal(t, "svm-uuid", *p.Info.Svm.UUID)
+		assert.False(t, *p.Info.Fixed.CapacityShared)
+		assert.Equal(t, int64(5000), *p.Info.Fixed.MaxThroughputIops)
+		assert.Equal(t, int64(200), *p.Info.Fixed.MaxThroughputMbps)
+	})
+
+	t.Run("no-op when the limits match", func(t *testing.T) {
+		mc := newClient(&models.QosPolicy{UUID: new("qos-uuid"), Fixed: &models.QosPolicyInlineFixed{MaxThroughputIops: new(int64(5000)), MaxThroughputMbps: new(int64(200))}})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureQoSPolicies(ctx, opts, []config.QoSTier{gold}))
+		mc.storage.AssertNotCalled(t, "QosPolicyCreate", mock.Anything, mock.Anything)
+		mc.storage.AssertNotCalled(t, "QosPolicyModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("updates changed limits", func(t *testing.T) {
+		mc := newClient(&models.QosPolicy{UUID: new("qos-uuid"), Fixed: &models.QosPolicyInlineFixed{MaxThroughputIops: new(int64(1000))}})
+		mc.storage.On("QosPolicyModify", mock.Anything, mock.Anything).Return(&storage.QosPolicyModifyOK{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureQoSPolicies(ctx, opts, []config.QoSTier{gold}))
+
+		p := mc.storage.Calls[1].Arguments[0].(*storage.QosPolicyModifyParams)
+		assert.Equal(t, "qos-uuid", p.UUID)
+		assert.Equal(t, int64(5000), *p.Info.Fixed.MaxThroughputIops)
+	})
+
+	t.Run("refuses to switch to adaptive limits", func(t *testing.T) {
+		mc := newClient(&models.QosPolicy{UUID: new("qos-uuid"), Fixed: &models.QosPolicyInlineFixed{MaxThroughputIops: new(int64(5000))}})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.EnsureQoSPolicies(ctx, opts, []config.QoSTier{{Name: "gold", Adaptive: &config.AdaptiveQoS{ExpectedIOPSPerTB: 1000, PeakIOPSPerTB: 2000}}})
+		require.ErrorContains(t, err, "cannot be switched between fixed and adaptive limits")
+	})
+}
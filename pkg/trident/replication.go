@@ -0,0 +1,334 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/cluster"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+const (
+	// replicaSuffix is appended to the names of the secondary SVM of a project and its backends.
+	replicaSuffix = "-replica"
+	// replicaPoolLabel labels the virtual storage pools of the backends of the secondary SVM, the StorageClasses of
+	// the shoot select pools by other labels and never provision volumes on the secondary SVM.
+	replicaPoolLabel = "replica"
+	// interclusterService is the service of the LIFs clusters are peered over.
+	interclusterService = "intercluster_core"
+	// svmPeerPeered is the state of an accepted SVM peer relationship.
+	svmPeerPeered = "peered"
+	// svmPeerPending is the state of an SVM peer relationship on the cluster which has to accept it.
+	svmPeerPending = "pending"
+)
+
+// ReplicaSVMName returns the name of the secondary SVM of the project which volumes are mirrored to.
+func ReplicaSVMName(svmName string) string {
+	return svmName + replicaSuffix
+}
+
+// ReplicaSVMOptions returns the options of the secondary SVM of the project. Only the block protocols of the SVM are
+// enabled on it, NFS volumes are not mirrored.
+func ReplicaSVMOptions(opts CreateSVMOptions, replication *ontapv1alpha1.ReplicationConfig) CreateSVMOptions {
+	replica := opts
+	replica.ProjectID = ReplicaSVMName(opts.ProjectID)
+	replica.SvmIpaddresses = replication.SvmIpaddresses
+	replica.SVMAliases = nil
+	replica.Protocols = replicaProtocols(opts.Protocols)
+	replica.NodeCIDRs = nil
+	return replica
+}
+
+// replicaProtocols returns the block protocols of the given protocols.
+func replicaProtocols(protocols []ontapv1alpha1.Protocol) []ontapv1alpha1.Protocol {
+	var result []ontapv1alpha1.Protocol
+	for _, p := range protocolsOrDefault(protocols) {
+		if p != ontapv1alpha1.ProtocolNFS {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// replicaBackend returns the backend of the secondary SVM for the block protocol. Its only virtual storage pool is
+// labelled with the protocol, the destination volumes of TridentMirrorRelationships are provisioned from it.
+func replicaBackend(p ontapv1alpha1.Protocol, backendConfigName, backendName string, replica *ReplicaValues) backends.Backend {
+	return backends.Backend{
+		ConfigName:        backendConfigName + replicaSuffix + backendSuffix(p),
+		Name:              backendName + replicaSuffix + backendSuffix(p),
+		StorageDriverName: "ontap-san",
+		SANType:           string(p),
+		ManagementLif:     replica.SvmIpAddresses.ManagementLif,
+		SecretName:        replica.SeedsecretName,
+		Pools:             []backends.Pool{{Label: replicaPoolLabel, Value: string(p)}},
+	}
+}
+
+// replicaStorageClass returns the StorageClass of the destination volumes of the block protocol.
+func replicaStorageClass(p ontapv1alpha1.Protocol, replica *ReplicaValues) backends.StorageClass {
+	return backends.StorageClass{
+		Name:             replica.StorageClass + backendSuffix(p),
+		BackendType:      "ontap-san",
+		ProvisioningType: string(ontapv1alpha1.ProvisioningThin),
+		Selector:         replicaPoolLabel + "=" + string(p),
+		FSType:           "ext4",
+	}
+}
+
+// EnsureReplication ensures the secondary SVM of the project on another cluster than the SVM of the project, the
+// peering of both clusters and the peering of both SVMs for SnapMirror. The secondary SVM is placed on the other
+// cluster with the fewest volumes.
+func (m *SvmManager) EnsureReplication(ctx context.Context, opts CreateSVMOptions, replication *ontapv1alpha1.ReplicationConfig) (err error) {
+	if replication == nil {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "EnsureReplication")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	var others []*ontapv1.Ontap
+	for _, c := range m.clients {
+		if c != ontapClient && !slices.Contains(opts.ExcludedClients, c) {
+			others = append(others, c)
+		}
+	}
+	if len(others) == 0 {
+		return fmt.Errorf("replication of SVM %s requires another cluster", opts.ProjectID)
+	}
+
+	replicaOpts := ReplicaSVMOptions(opts, replication)
+	replicaManager := NewSvmManager(m.log, others, m.seedClient, m.recorder).WithClusterNames(m.clusterNames).WithPasswordPolicyValidator(m.passwordPolicies)
+	if err := replicaManager.EnsureCompleteSVM(ctx, replicaOpts); err != nil {
+		return fmt.Errorf("failed to ensure secondary SVM %s: %w", replicaOpts.ProjectID, err)
+	}
+	replicaUUID, replicaClient, err := replicaManager.GetSVMByName(ctx, replicaOpts.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get secondary SVM %s: %w", replicaOpts.ProjectID, err)
+	}
+
+	// the SVMs may still have the names of earlier versions or the -mc suffix
+	svmName, err := m.svmName(ctx, ontapClient, *svmUUID)
+	if err != nil {
+		return err
+	}
+	replicaName, err := m.svmName(ctx, replicaClient, *replicaUUID)
+	if err != nil {
+		return err
+	}
+
+	peerCluster, err := m.ensureClusterPeer(ctx, ontapClient, replicaClient)
+	if err != nil {
+		return err
+	}
+	return m.ensureSVMPeer(ctx, ontapClient, svmName, replicaClient, replicaName, peerCluster)
+}
+
+// svmName returns the name of the SVM with the given UUID.
+func (m *SvmManager) svmName(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID string) (string, error) {
+	params := s_vm.NewSvmGetParamsWithContext(ctx)
+	params.SetUUID(svmUUID)
+	params.SetFields([]string{"name"})
+
+	result, err := ontapClient.SVM.SvmGet(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get SVM %s: %w", svmUUID, err)
+	}
+	if result.Payload == nil || result.Payload.Name == nil {
+		return "", fmt.Errorf("SVM %s has no name", svmUUID)
+	}
+	return *result.Payload.Name, nil
+}
+
+// ensureClusterPeer peers the local with the remote cluster over their intercluster LIFs if they are not peered yet.
+// The passphrase is generated by the remote cluster and used by the local cluster to authenticate the peering.
+// It returns the name of the remote cluster.
+func (m *SvmManager) ensureClusterPeer(ctx context.Context, local, remote *ontapv1.Ontap) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "EnsureClusterPeer")
+	defer func() { tracing.End(span, err) }()
+
+	remoteName, err := ontapClusterName(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+
+	params := cluster.NewClusterPeerCollectionGetParamsWithContext(ctx)
+	params.SetFields([]string{"name", "remote.name", "status.state"})
+	peers, err := local.Cluster.ClusterPeerCollectionGet(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster peers: %w", err)
+	}
+	if peers.Payload != nil {
+		for _, peer := range peers.Payload.ClusterPeerResponseInlineRecords {
+			if (peer.Name == nil || *peer.Name != remoteName) && (peer.Remote == nil || peer.Remote.Name == nil || *peer.Remote.Name != remoteName) {
+				continue
+			}
+			if peer.Status != nil && peer.Status.State != nil {
+				m.log.Info("Cluster is already peered", "cluster", remoteName, "state", *peer.Status.State)
+			}
+			return remoteName, nil
+		}
+	}
+
+	localAddresses, err := interclusterAddresses(ctx, local)
+	if err != nil {
+		return "", err
+	}
+	remoteAddresses, err := interclusterAddresses(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+
+	m.log.Info("Peering cluster", "cluster", remoteName, "localAddresses", localAddresses, "remoteAddresses", remoteAddresses)
+	remoteParams := cluster.NewClusterPeerCreateParamsWithContext(ctx)
+	remoteParams.SetReturnRecords(new(true))
+	remoteParams.SetInfo(&models.ClusterPeer{
+		Authentication: &models.ClusterPeerInlineAuthentication{GeneratePassphrase: new(true)},
+		Remote:         &models.ClusterPeerInlineRemote{IPAddresses: localAddresses},
+	})
+	created, err := remote.Cluster.ClusterPeerCreate(remoteParams, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cluster peer on cluster %s: %w", remoteName, err)
+	}
+	if created.Payload == nil || len(created.Payload.ClusterPeerResponseInlineRecords) == 0 {
+		return "", fmt.Errorf("cluster %s returned no passphrase for the cluster peer", remoteName)
+	}
+	auth := created.Payload.ClusterPeerResponseInlineRecords[0].Authentication
+	if auth == nil || auth.Passphrase == nil {
+		return "", fmt.Errorf("cluster %s returned no passphrase for the cluster peer", remoteName)
+	}
+
+	localParams := cluster.NewClusterPeerCreateParamsWithContext(ctx)
+	localParams.SetInfo(&models.ClusterPeer{
+		Authentication: &models.ClusterPeerInlineAuthentication{Passphrase: auth.Passphrase},
+		Remote:         &models.ClusterPeerInlineRemote{IPAddresses: remoteAddresses},
+	})
+	if _, err := local.Cluster.ClusterPeerCreate(localParams, nil); err != nil {
+		return "", fmt.Errorf("failed to create cluster peer with cluster %s: %w", remoteName, err)
+	}
+
+	m.recorder.Normal(ctx, events.ReasonClusterPeered, events.ActionCreate, "cluster peered with cluster %s", remoteName)
+	return remoteName, nil
+}
+
+// ontapClusterName returns the name of the cluster in ONTAP, it may differ from the name in the configuration.
+func ontapClusterName(ctx context.Context, ontapClient *ontapv1.Ontap) (string, error) {
+	result, err := ontapClient.Cluster.ClusterGet(cluster.NewClusterGetParamsWithContext(ctx), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster: %w", err)
+	}
+	if result.Payload == nil || result.Payload.Name == nil {
+		return "", fmt.Errorf("cluster has no name")
+	}
+	return *result.Payload.Name, nil
+}
+
+// interclusterAddresses returns the addresses of the intercluster LIFs of the cluster.
+func interclusterAddresses(ctx context.Context, ontapClient *ontapv1.Ontap) ([]*models.IPAddress, error) {
+	params := networking.NewNetworkIPInterfacesGetParamsWithContext(ctx)
+	params.SetServices(new(interclusterService))
+	params.SetFields([]string{"ip.address"})
+
+	result, err := ontapClient.Networking.NetworkIPInterfacesGet(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intercluster LIFs: %w", err)
+	}
+
+	var addresses []*models.IPAddress
+	if result.Payload != nil {
+		for _, lif := range result.Payload.IPInterfaceResponseInlineRecords {
+			if lif.IP != nil && lif.IP.Address != nil {
+				addresses = append(addresses, lif.IP.Address)
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("cluster has no intercluster LIFs")
+	}
+	return addresses, nil
+}
+
+// ensureSVMPeer peers the local SVM with the remote SVM for SnapMirror. Peer relationships of SVMs on different
+// clusters are pending on the remote cluster until they are accepted there. An error is returned until the
+// relationship is accepted.
+func (m *SvmManager) ensureSVMPeer(ctx context.Context, local *ontapv1.Ontap, svmName string, remote *ontapv1.Ontap, remoteSVMName, remoteCluster string) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureSVMPeer")
+	defer func() { tracing.End(span, err) }()
+
+	params := s_vm.NewSvmPeerCollectionGetParamsWithContext(ctx)
+	params.SetSvmName(&svmName)
+	params.SetPeerSvmName(&remoteSVMName)
+	params.SetFields([]string{"state", "applications"})
+	result, err := local.SVM.SvmPeerCollectionGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM peers of SVM %s: %w", svmName, err)
+	}
+
+	if result.Payload == nil || len(result.Payload.SvmPeerResponseInlineRecords) == 0 {
+		m.log.Info("Peering SVM", "svm", svmName, "peer", remoteSVMName, "cluster", remoteCluster)
+		createParams := s_vm.NewSvmPeerCreateParamsWithContext(ctx)
+		createParams.SetInfo(&models.SvmPeer{
+			Svm: &models.SvmPeerInlineSvm{Name: &svmName},
+			Peer: &models.SvmPeerInlinePeer{
+				Svm:     &models.SvmPeerInlinePeerInlineSvm{Name: &remoteSVMName},
+				Cluster: &models.SvmPeerInlinePeerInlineCluster{Name: &remoteCluster},
+			},
+			SvmPeerInlineApplications: []*models.SvmPeerApplications{models.NewSvmPeerApplications(models.SvmPeerApplicationsSnapmirror)},
+		})
+		if _, _, err := local.SVM.SvmPeerCreate(createParams, nil); err != nil {
+			return fmt.Errorf("failed to peer SVM %s with SVM %s: %w", svmName, remoteSVMName, err)
+		}
+		m.recorder.Normal(ctx, events.ReasonSVMPeered, events.ActionCreate, "SVM %s peered with SVM %s on cluster %s", svmName, remoteSVMName, remoteCluster)
+	} else {
+		peer := result.Payload.SvmPeerResponseInlineRecords[0]
+		if peer.State != nil && *peer.State == svmPeerPeered {
+			if !slices.ContainsFunc(peer.SvmPeerInlineApplications, isSnapmirrorApplication) {
+				return fmt.Errorf("SVM %s is peered with SVM %s without snapmirror", svmName, remoteSVMName)
+			}
+			return nil
+		}
+	}
+
+	pendingParams := s_vm.NewSvmPeerCollectionGetParamsWithContext(ctx)
+	pendingParams.SetSvmName(&remoteSVMName)
+	pendingParams.SetPeerSvmName(&svmName)
+	pendingParams.SetState(new(svmPeerPending))
+	pending, err := remote.SVM.SvmPeerCollectionGet(pendingParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get pending SVM peers of SVM %s: %w", remoteSVMName, err)
+	}
+	if pending.Payload == nil || len(pending.Payload.SvmPeerResponseInlineRecords) == 0 {
+		return fmt.Errorf("SVM peer of SVM %s with SVM %s is not accepted yet", svmName, remoteSVMName)
+	}
+	for _, peer := range pending.Payload.SvmPeerResponseInlineRecords {
+		if peer.UUID == nil {
+			continue
+		}
+		modifyParams := s_vm.NewSvmPeerModifyParamsWithContext(ctx)
+		modifyParams.SetUUID(*peer.UUID)
+		modifyParams.SetInfo(&models.SvmPeer{State: new(svmPeerPeered)})
+		if _, _, err := remote.SVM.SvmPeerModify(modifyParams, nil); err != nil {
+			return fmt.Errorf("failed to accept SVM peer of SVM %s with SVM %s: %w", svmName, remoteSVMName, err)
+		}
+		m.log.Info("Accepted SVM peer", "svm", remoteSVMName, "peer", svmName)
+	}
+	return nil
+}
+
+// isSnapmirrorApplication returns whether the application of an SVM peer relationship is SnapMirror.
+func isSnapmirrorApplication(a *models.SvmPeerApplications) bool {
+	return a != nil && *a == models.SvmPeerApplicationsSnapmirror
+}
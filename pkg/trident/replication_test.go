@@ -0,0 +1,156 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/cluster"
+	"github.com/metal-stack/ontap-go/api/client/networking"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestReplicaSVMOptions(t *testing.T) {
+	opts := CreateSVMOptions{
+		ProjectID:      "proj-1",
+		SvmIpaddresses: ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.0.1", DataLifs: []string{"10.0.0.2", "10.0.0.3"}},
+		SVMAliases:     []string{"proj-old"},
+		Protocols:      []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNFS, ontapv1alpha1.ProtocolNVMe},
+		NodeCIDRs:      []string{"10.1.0.0/16"},
+	}
+	replica := ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.1.1", DataLifs: []string{"10.0.1.2"}}
+
+	got := ReplicaSVMOptions(opts, &ontapv1alpha1.ReplicationConfig{SvmIpaddresses: replica})
+	assert.Equal(t, "proj-1-replica", got.ProjectID)
+	assert.Equal(t, replica, got.SvmIpaddresses)
+	assert.Empty(t, got.SVMAliases)
+	assert.Empty(t, got.NodeCIDRs)
+	assert.Equal(t, []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe}, got.Protocols)
+	assert.Equal(t, "proj-1", opts.ProjectID)
+}
+
+func TestEnsureClusterPeer(t *testing.T) {
+	ctx := context.Background()
+
+	newClients := func(peers ...*models.ClusterPeer) (*mockOntapClient, *mockOntapClient) {
+		local, remote := newMockOntapClient(), newMockOntapClient()
+		remote.cluster.On("ClusterGet", mock.Anything, mock.Anything).
+			Return(&cluster.ClusterGetOK{Payload: &models.Cluster{Name: new("cluster-b")}}, nil)
+		local.cluster.On("ClusterPeerCollectionGet", mock.Anything, mock.Anything).
+			Return(&cluster.ClusterPeerCollectionGetOK{Payload: &models.ClusterPeerResponse{ClusterPeerResponseInlineRecords: peers}}, nil)
+		return local, remote
+	}
+	intercluster := func(mc *mockOntapClient, ip string) {
+		mc.networking.On("NetworkIPInterfacesGet", mock.Anything, mock.Anything).
+			Return(&networking.NetworkIPInterfacesGetOK{Payload: &models.IPInterfaceResponse{IPInterfaceResponseInlineRecords: []*models.IPInterface{
+				{IP: &models.IPInfo{Address: new(models.IPAddress(ip))}},
+			}}}, nil)
+	}
+
+	t.Run("no-op when the clusters are peered", func(t *testing.T) {
+		local, remote := newClients(&models.ClusterPeer{Name: new("cluster-b"), Status: &models.ClusterPeerInlineStatus{State: new("available")}})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		name, err := m.ensureClusterPeer(ctx, local.client, remote.client)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster-b", name)
+		local.cluster.AssertNotCalled(t, "ClusterPeerCreate", mock.Anything, mock.Anything)
+		remote.cluster.AssertNotCalled(t, "ClusterPeerCreate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("peers with the passphrase of the remote cluster", func(t *testing.T) {
+		local, remote := newClients()
+		intercluster(local, "10.10.0.1")
+		intercluster(remote, "10.20.0.1")
+		remote.cluster.On("ClusterPeerCreate", mock.Anything, mock.Anything).
+			Return(&cluster.ClusterPeerCreateCreated{Payload: &models.ClusterPeerResponse{ClusterPeerResponseInlineRecords: []*models.ClusterPeer{
+				{Authentication: &models.ClusterPeerInlineAuthentication{Passphrase: new("secret")}},
+			}}}, nil)
+		local.cluster.On("ClusterPeerCreate", mock.Anything, mock.Anything).Return(&cluster.ClusterPeerCreateCreated{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		name, err := m.ensureClusterPeer(ctx, local.client, remote.client)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster-b", name)
+
+		p := remote.cluster.Calls[1].Arguments[0].(*cluster.ClusterPeerCreateParams)
+		assert.True(t, *p.Info.Authentication.GeneratePassphrase)
+		assert.Equal(t, []*models.IPAddress{new(models.IPAddress("10.10.0.1"))}, p.Info.Remote.IPAddresses)
+
+		p = local.cluster.Calls[1].Arguments[0].(*cluster.ClusterPeerCreateParams)
+		assert.Equal(t, "secret", *p.Info.Authentication.Passphrase)
+		assert.Equal(t, []*models.IPAddress{new(models.IPAddress("10.20.0.1"))}, p.Info.Remote.IPAddresses)
+	})
+}
+
+func TestEnsureSVMPeer(t *testing.T) {
+	ctx := context.Background()
+	snapmirror := []*models.SvmPeerApplications{models.NewSvmPeerApplications(models.SvmPeerApplicationsSnapmirror)}
+
+	peers := func(records ...*models.SvmPeer) *s_vm.SvmPeerCollectionGetOK {
+		return &s_vm.SvmPeerCollectionGetOK{Payload: &models.SvmPeerResponse{SvmPeerResponseInlineRecords: records}}
+	}
+
+	t.Run("no-op when the SVMs are peered", func(t *testing.T) {
+		local, remote := newMockOntapClient(), newMockOntapClient()
+		local.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).
+			Return(peers(&models.SvmPeer{State: new("peered"), SvmPeerInlineApplications: snapmirror}), nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		require.NoError(t, m.ensureSVMPeer(ctx, local.client, "proj-1", remote.client, "proj-1-replica", "cluster-b"))
+		local.svm.AssertNotCalled(t, "SvmPeerCreate", mock.Anything, mock.Anything)
+		remote.svm.AssertNotCalled(t, "SvmPeerCollectionGet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates and accepts the peer", func(t *testing.T) {
+		local, remote := newMockOntapClient(), newMockOntapClient()
+		local.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).Return(peers(), nil)
+		local.svm.On("SvmPeerCreate", mock.Anything, mock.Anything).Return(&s_vm.SvmPeerCreateCreated{}, nil, nil)
+		remote.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).
+			Return(peers(&models.SvmPeer{UUID: new("peer-uuid"), State: new("pending")}), nil)
+		remote.svm.On("SvmPeerModify", mock.Anything, mock.Anything).Return(&s_vm.SvmPeerModifyOK{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		require.NoError(t, m.ensureSVMPeer(ctx, local.client, "proj-1", remote.client, "proj-1-replica", "cluster-b"))
+
+		create := local.svm.Calls[1].Arguments[0].(*s_vm.SvmPeerCreateParams)
+		assert.Equal(t, "proj-1", *create.Info.Svm.Name)
+		assert.Equal(t, "proj-1-replica", *create.Info.Peer.Svm.Name)
+		assert.Equal(t, "cluster-b", *create.Info.Peer.Cluster.Name)
+		assert.Equal(t, snapmirror, create.Info.SvmPeerInlineApplications)
+
+		pending := remote.svm.Calls[0].Arguments[0].(*s_vm.SvmPeerCollectionGetParams)
+		assert.Equal(t, "proj-1-replica", *pending.SvmName)
+		assert.Equal(t, "pending", *pending.State)
+
+		modify := remote.svm.Calls[1].Arguments[0].(*s_vm.SvmPeerModifyParams)
+		assert.Equal(t, "peer-uuid", modify.UUID)
+		assert.Equal(t, "peered", *modify.Info.State)
+	})
+
+	t.Run("fails until the peer is accepted", func(t *testing.T) {
+		local, remote := newMockOntapClient(), newMockOntapClient()
+		local.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).Return(peers(&models.SvmPeer{State: new("initiated")}), nil)
+		remote.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).Return(peers(), nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		err := m.ensureSVMPeer(ctx, local.client, "proj-1", remote.client, "proj-1-replica", "cluster-b")
+		require.ErrorContains(t, err, "is not accepted yet")
+	})
+
+	t.Run("fails for peers without snapmirror", func(t *testing.T) {
+		local, remote := newMockOntapClient(), newMockOntapClient()
+		local.svm.On("SvmPeerCollectionGet", mock.Anything, mock.Anything).Return(peers(&models.SvmPeer{State: new("peered")}), nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{local.client, remote.client}, nil, nil)
+		err := m.ensureSVMPeer(ctx, local.client, "proj-1", remote.client, "proj-1-replica", "cluster-b")
+		require.ErrorContains(t, err, "without snapmirror")
+	})
+}
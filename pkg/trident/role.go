@@ -0,0 +1,166 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+const (
+	// TridentRoleName is the name of the custom REST role of the SVM accounts.
+	TridentRoleName = "gardener-trident"
+	// vsadminRoleName is the built-in administrative role of an SVM.
+	vsadminRoleName = "vsadmin"
+)
+
+// tridentRolePrivileges are the API paths the ontap-san driver of Trident needs for NVMe and iSCSI backends and the
+// ontap-nas driver needs for NFS backends, including the SnapMirror relationships of TridentMirrorRelationships. The
+// export policies are managed by the extension, the accounts of the shoots share the SVM and must not change the
+// exports of other shoots.
+var tridentRolePrivileges = map[string]models.RolePrivilegeLevel{
+	"/api/cluster":                       models.RolePrivilegeLevelReadonly,
+	"/api/cluster/jobs":                  models.RolePrivilegeLevelReadonly,
+	"/api/svm/svms":                      models.RolePrivilegeLevelReadonly,
+	"/api/network/ip/interfaces":         models.RolePrivilegeLevelReadonly,
+	"/api/storage/aggregates":            models.RolePrivilegeLevelReadonly,
+	"/api/storage/qos/policies":          models.RolePrivilegeLevelReadonly,
+	"/api/storage/snapshot-policies":     models.RolePrivilegeLevelReadonly,
+	"/api/storage/volumes":               models.RolePrivilegeLevelAll,
+	"/api/storage/volumes/*/snapshots":   models.RolePrivilegeLevelAll,
+	"/api/storage/luns":                  models.RolePrivilegeLevelAll,
+	"/api/protocols/san/igroups":         models.RolePrivilegeLevelAll,
+	"/api/protocols/san/lun-maps":        models.RolePrivilegeLevelAll,
+	"/api/protocols/san/iscsi/services":  models.RolePrivilegeLevelReadonly,
+	"/api/protocols/nvme/services":       models.RolePrivilegeLevelReadonly,
+	"/api/protocols/nvme/subsystems":     models.RolePrivilegeLevelAll,
+	"/api/protocols/nvme/subsystem-maps": models.RolePrivilegeLevelAll,
+	"/api/storage/namespaces":            models.RolePrivilegeLevelAll,
+	"/api/protocols/nfs/services":        models.RolePrivilegeLevelReadonly,
+	"/api/protocols/nfs/export-policies": models.RolePrivilegeLevelReadonly,
+	"/api/svm/peers":                     models.RolePrivilegeLevelReadonly,
+	"/api/snapmirror/policies":           models.RolePrivilegeLevelReadonly,
+	"/api/snapmirror/relationships":      models.RolePrivilegeLevelAll,
+}
+
+// AccountRoleName returns the name of the ONTAP role of the SVM accounts for the configured account role.
+func AccountRoleName(role config.AccountRole) string {
+	if role == config.AccountRoleVsadmin {
+		return vsadminRoleName
+	}
+	return TridentRoleName
+}
+
+// ensureTridentRole creates the custom REST role on the SVM and converges its privileges, privileges of API paths
+// which are not needed anymore are removed.
+func (m *SvmManager) ensureTridentRole(ctx context.Context, ontapClient *ontapv1.Ontap, opts userAndSecretOptions) error {
+	params := security.NewRoleCollectionGetParamsWithContext(ctx)
+	params.SetOwnerUUID(&opts.svmUUID)
+	params.SetName(new(TridentRoleName))
+	params.SetFields([]string{"privileges"})
+
+	result, err := ontapClient.Security.RoleCollectionGet(params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query role %s on SVM %s: %w", TridentRoleName, opts.projectID, err)
+	}
+
+	if result.Payload == nil || len(result.Payload.RoleResponseInlineRecords) == 0 {
+		var privileges []*models.RolePrivilege
+		for path, access := range tridentRolePrivileges {
+			privileges = append(privileges, &models.RolePrivilege{Path: new(path), Access: access.Pointer()})
+		}
+
+		createParams := security.NewRoleCreateParamsWithContext(ctx)
+		createParams.SetInfo(&models.Role{
+			Name:                 new(TridentRoleName),
+			Owner:                &models.RoleInlineOwner{UUID: new(opts.svmUUID)},
+			RoleInlinePrivileges: privileges,
+		})
+		if _, err := ontapClient.Security.RoleCreate(createParams, nil); err != nil {
+			return fmt.Errorf("failed to create role %s on SVM %s: %w", TridentRoleName, opts.projectID, err)
+		}
+		m.log.Info("Created role", "svm", opts.projectID, "role", TridentRoleName)
+		return nil
+	}
+
+	actual := map[string]models.RolePrivilegeLevel{}
+	for _, p := range result.Payload.RoleResponseInlineRecords[0].RoleInlinePrivileges {
+		if p.Path != nil && p.Access != nil {
+			actual[*p.Path] = *p.Access
+		}
+	}
+
+	for path, access := range tridentRolePrivileges {
+		current, ok := actual[path]
+		switch {
+		case !ok:
+			createParams := security.NewRolePrivilegeCreateParamsWithContext(ctx)
+			createParams.SetOwnerUUID(opts.svmUUID)
+			createParams.SetName(TridentRoleName)
+			createParams.SetInfo(&models.RolePrivilege{Path: new(path), Access: access.Pointer()})
+			if _, err := ontapClient.Security.RolePrivilegeCreate(createParams, nil); err != nil {
+				return fmt.Errorf("failed to add privilege %s to role %s on SVM %s: %w", path, TridentRoleName, opts.projectID, err)
+			}
+		case current != access:
+			modifyParams := security.NewRolePrivilegeModifyParamsWithContext(ctx)
+			modifyParams.SetOwnerUUID(opts.svmUUID)
+			modifyParams.SetName(TridentRoleName)
+			modifyParams.SetPath(path)
+			modifyParams.SetInfo(&models.RolePrivilege{Access: access.Pointer()})
+			if _, err := ontapClient.Security.RolePrivilegeModify(modifyParams, nil); err != nil {
+				return fmt.Errorf("failed to modify privilege %s of role %s on SVM %s: %w", path, TridentRoleName, opts.projectID, err)
+			}
+		default:
+			continue
+		}
+		m.log.Info("Converged role privilege", "svm", opts.projectID, "role", TridentRoleName, "path", path, "access", access)
+	}
+
+	for path := range actual {
+		// only REST paths are managed, ONTAP adds command directory privileges on its own
+		if _, ok := tridentRolePrivileges[path]; ok || !strings.HasPrefix(path, "/api/") {
+			continue
+		}
+		deleteParams := security.NewRolePrivilegeDeleteParamsWithContext(ctx)
+		deleteParams.SetOwnerUUID(opts.svmUUID)
+		deleteParams.SetName(TridentRoleName)
+		deleteParams.SetPath(path)
+		if _, err := ontapClient.Security.RolePrivilegeDelete(deleteParams, nil); err != nil {
+			return fmt.Errorf("failed to remove privilege %s from role %s on SVM %s: %w", path, TridentRoleName, opts.projectID, err)
+		}
+		m.log.Info("Removed role privilege", "svm", opts.projectID, "role", TridentRoleName, "path", path)
+	}
+	return nil
+}
+
+// ensureAccountRole assigns the role of the options to an existing account, e.g. when the account role was switched.
+func (m *SvmManager) ensureAccountRole(ctx context.Context, ontapClient *ontapv1.Ontap, account *models.Account, opts userAndSecretOptions) error {
+	role := opts.accountRoleName()
+	if account.Role == nil || account.Role.Name == nil || *account.Role.Name == role {
+		return nil
+	}
+
+	params := security.NewAccountModifyParamsWithContext(ctx)
+	params.SetOwnerUUID(opts.svmUUID)
+	params.SetName(*account.Name)
+	params.SetInfo(&models.Account{Role: &models.AccountInlineRole{Name: new(role)}})
+	if _, err := ontapClient.Security.AccountModify(params, nil); err != nil {
+		return fmt.Errorf("failed to assign role %s to account %s: %w", role, *account.Name, err)
+	}
+
+	m.log.Info("Assigned role to account", "svm", opts.projectID, "user", *account.Name, "role", role, "previousRole", *account.Role.Name)
+	return nil
+}
+
+// accountRoleName returns the ONTAP role of the account, the built-in vsadmin role if none is set.
+func (o userAndSecretOptions) accountRoleName() string {
+	if o.accountRole == "" {
+		return vsadminRoleName
+	}
+	return o.accountRole
+}
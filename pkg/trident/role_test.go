@@ -0,0 +1,105 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+func TestEnsureTridentRole(t *testing.T) {
+	ctx := context.Background()
+	opts := userAndSecretOptions{projectID: "proj-1", svmUUID: "svm-uuid-1", accountRole: TridentRoleName}
+
+	t.Run("role is created with all privileges", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.security.On("RoleCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.RoleCollectionGetOK{Payload: &models.RoleResponse{}}, nil)
+		mc.security.On("RoleCreate", mock.Anything, mock.Anything).Return(&security.RoleCreateCreated{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.ensureTridentRole(ctx, mc.client, opts))
+
+		mc.security.AssertCalled(t, "RoleCreate", mock.MatchedBy(func(p *security.RoleCreateParams) bool {
+			return *p.Info.Name == TridentRoleName && *p.Info.Owner.UUID == "svm-uuid-1" && len(p.Info.RoleInlinePrivileges) == len(tridentRolePrivileges)
+		}), mock.Anything)
+	})
+
+	t.Run("export policies are read only", func(t *testing.T) {
+		assert.Equal(t, models.RolePrivilegeLevelReadonly, tridentRolePrivileges["/api/protocols/nfs/export-policies"])
+	})
+
+	t.Run("privileges are converged", func(t *testing.T) {
+		var privileges []*models.RolePrivilege
+		for path, access := range tridentRolePrivileges {
+			switch path {
+			case "/api/storage/luns":
+				// missing
+			case "/api/svm/svms":
+				privileges = append(privileges, &models.RolePrivilege{Path: new(path), Access: models.RolePrivilegeLevelAll.Pointer()})
+			default:
+				privileges = append(privileges, &models.RolePrivilege{Path: new(path), Access: access.Pointer()})
+			}
+		}
+		privileges = append(privileges,
+			&models.RolePrivilege{Path: new("/api/security/accounts"), Access: models.RolePrivilegeLevelAll.Pointer()},
+			&models.RolePrivilege{Path: new("DEFAULT"), Access: models.RolePrivilegeLevelNone.Pointer()},
+		)
+
+		mc := newMockOntapClient()
+		mc.security.On("RoleCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.RoleCollectionGetOK{Payload: &models.RoleResponse{
+				RoleResponseInlineRecords: []*models.Role{{Name: new(TridentRoleName), RoleInlinePrivileges: privileges}},
+			}}, nil)
+		mc.security.On("RolePrivilegeCreate", mock.Anything, mock.Anything).Return(&security.RolePrivilegeCreateCreated{}, nil)
+		mc.security.On("RolePrivilegeModify", mock.Anything, mock.Anything).Return(&security.RolePrivilegeModifyOK{}, nil)
+		mc.security.On("RolePrivilegeDelete", mock.Anything, mock.Anything).Return(&security.RolePrivilegeDeleteOK{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.ensureTridentRole(ctx, mc.client, opts))
+
+		mc.security.AssertNumberOfCalls(t, "RolePrivilegeCreate", 1)
+		mc.security.AssertCalled(t, "RolePrivilegeCreate", mock.MatchedBy(func(p *security.RolePrivilegeCreateParams) bool {
+			return *p.Info.Path == "/api/storage/luns"
+		}), mock.Anything)
+		mc.security.AssertNumberOfCalls(t, "RolePrivilegeModify", 1)
+		mc.security.AssertCalled(t, "RolePrivilegeModify", mock.MatchedBy(func(p *security.RolePrivilegeModifyParams) bool {
+			return p.Path == "/api/svm/svms" && *p.Info.Access == models.RolePrivilegeLevelReadonly
+		}), mock.Anything)
+		mc.security.AssertNumberOfCalls(t, "RolePrivilegeDelete", 1)
+		mc.security.AssertCalled(t, "RolePrivilegeDelete", mock.MatchedBy(func(p *security.RolePrivilegeDeleteParams) bool {
+			return p.Path == "/api/security/accounts"
+		}), mock.Anything)
+	})
+}
+
+func TestEnsureAccountRole(t *testing.T) {
+	ctx := context.Background()
+
+	mc := newMockOntapClient()
+	mc.security.On("AccountModify", mock.Anything, mock.Anything).Return(&security.AccountModifyOK{}, nil)
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+
+	account := &models.Account{Name: new("myshoot"), Role: &models.AccountInlineRole{Name: new(vsadminRoleName)}}
+
+	require.NoError(t, m.ensureAccountRole(ctx, mc.client, account, userAndSecretOptions{svmUUID: "svm-uuid-1"}))
+	mc.security.AssertNotCalled(t, "AccountModify", mock.Anything, mock.Anything)
+
+	require.NoError(t, m.ensureAccountRole(ctx, mc.client, account, userAndSecretOptions{svmUUID: "svm-uuid-1", accountRole: TridentRoleName}))
+	mc.security.AssertCalled(t, "AccountModify", mock.MatchedBy(func(p *security.AccountModifyParams) bool {
+		return p.Name == "myshoot" && *p.Info.Role.Name == TridentRoleName
+	}), mock.Anything)
+}
+
+func TestAccountRoleName(t *testing.T) {
+	assert.Equal(t, TridentRoleName, AccountRoleName(config.AccountRoleRestricted))
+	assert.Equal(t, vsadminRoleName, AccountRoleName(config.AccountRoleVsadmin))
+}
@@ -0,0 +1,153 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+const (
+	// pendingPasswordKey stores the new password in the seed secret while a rotation is in progress,
+	// so an interrupted rotation is resumed with the same password.
+	pendingPasswordKey = "pending-password"
+
+	backendPollInterval = 5 * time.Second
+)
+
+// tridentBackendConfigGVK is the kind of the backend config deployed into the shoot.
+var tridentBackendConfigGVK = schema.GroupVersionKind{Group: "trident.netapp.io", Version: "v1", Kind: "TridentBackendConfig"}
+
+// CredentialsRotationPending returns whether a previous rotation of the account password was interrupted.
+func (m *SvmManager) CredentialsRotationPending(ctx context.Context, opts CreateSVMOptions) (bool, error) {
+	secret := &corev1.Secret{}
+	if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: opts.SvmSeedSecretNamespace, Name: opts.seedSecretName()}, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return len(secret.Data[pendingPasswordKey]) > 0, nil
+}
+
+// RotateCredentials sets a new password for the account of the shoot and stores it in the seed secret,
+// with certificate authentication a new client certificate is issued.
+// The new password is stored in the seed secret before it is set in ONTAP, an interrupted rotation is resumed
+// with the same password on the next call.
+func (m *SvmManager) RotateCredentials(ctx context.Context, opts CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "RotateCredentials")
+	defer func() { tracing.End(span, err) }()
+
+	username, err := getClusterUsername(opts.ShootNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to generate cluster username: %w", err)
+	}
+
+	// accounts with certificate authentication have no password, a new client certificate is issued instead
+	if opts.CertificateAuthentication != nil {
+		return m.renewClientCertificate(ctx, username, opts)
+	}
+
+	_, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to find SVM %s: %w", opts.ProjectID, err)
+	}
+
+	secretName := opts.seedSecretName()
+	secret := &corev1.Secret{}
+	if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: opts.SvmSeedSecretNamespace, Name: secretName}, secret); err != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %w", opts.SvmSeedSecretNamespace, secretName, err)
+	}
+
+	password := string(secret.Data[pendingPasswordKey])
+	if password == "" {
+		if err := m.validatePasswordPolicy(ctx, ontapClient); err != nil {
+			return err
+		}
+		password, err = generatePassword(opts.PasswordPolicy)
+		if err != nil {
+			return err
+		}
+
+		patch := client.MergeFrom(secret.DeepCopy())
+		secret.Data[pendingPasswordKey] = []byte(password)
+		if err := m.seedClient.Patch(ctx, secret, patch); err != nil {
+			return fmt.Errorf("failed to store pending password in secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	} else {
+		m.log.Info("Resuming interrupted credentials rotation", "svm", opts.ProjectID, "user", username)
+	}
+
+	if _, err := m.updateExistingUserPassword(ctx, ontapClient, username, opts.ProjectID, password); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	secret.Data["password"] = []byte(password)
+	delete(secret.Data, pendingPasswordKey)
+	if err := m.seedClient.Patch(ctx, secret, patch); err != nil {
+		return fmt.Errorf("failed to store rotated password in secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+
+	m.log.Info("Rotated credentials", "svm", opts.ProjectID, "user", username)
+	m.recorder.Normal(ctx, events.ReasonCredentialsRotated, events.ActionRotate, "password of account %s on SVM %s rotated", username, opts.ProjectID)
+	return nil
+}
+
+// BackendConfigVersions returns the resource versions of the TridentBackendConfigs with the given names in the shoot,
+// the version of a missing backend config is empty. They are recorded before rotated credentials are deployed, so
+// WaitForBackendOnline only accepts operations of Trident after the rotation.
+func BackendConfigVersions(ctx context.Context, shootClient client.Client, backendConfigNames []string) (map[string]string, error) {
+	versions := map[string]string{}
+	for _, name := range backendConfigNames {
+		backend := &unstructured.Unstructured{}
+		backend.SetGroupVersionKind(tridentBackendConfigGVK)
+		if err := shootClient.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: name}, backend); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get trident backend %s: %w", name, err)
+			}
+			continue
+		}
+		versions[name] = backend.GetResourceVersion()
+	}
+	return versions, nil
+}
+
+// WaitForBackendOnline waits until the TridentBackendConfig with the given name in the shoot reports a successful
+// operation which is newer than the given resource version, e.g. after its credentials were rotated. The backend config
+// itself is not changed by a rotation, a newer resource version is written by Trident when it processed the rotated
+// credentials.
+func WaitForBackendOnline(ctx context.Context, shootClient client.Client, backendConfigName, resourceVersion string, timeout time.Duration) (err error) {
+	ctx, span := tracing.Start(ctx, "WaitForBackendOnline")
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		backend = &unstructured.Unstructured{}
+		name    = backendConfigName
+		phase   string
+		status  string
+	)
+	backend.SetGroupVersionKind(tridentBackendConfigGVK)
+
+	err = wait.PollUntilContextTimeout(ctx, backendPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := shootClient.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: name}, backend); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		// the status of the backend config before the rotation does not tell whether Trident accepted the credentials
+		if backend.GetResourceVersion() == resourceVersion {
+			return false, nil
+		}
+		phase, _, _ = unstructured.NestedString(backend.Object, "status", "phase")
+		status, _, _ = unstructured.NestedString(backend.Object, "status", "lastOperationStatus")
+		return phase == "Bound" && status == "Success", nil
+	})
+	if err != nil {
+		return fmt.Errorf("trident backend %s is not online, phase %q, last operation %q: %w", name, phase, status, err)
+	}
+	return nil
+}
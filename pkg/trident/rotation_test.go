@@ -0,0 +1,155 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRotateCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	opts := CreateSVMOptions{
+		ProjectID:              "proj-1",
+		ShootNamespace:         "shoot--proj--myshoot",
+		SvmSeedSecretNamespace: "shoot--proj--myshoot",
+	}
+	secretKey := client.ObjectKey{Namespace: "shoot--proj--myshoot", Name: "proj-1-proj--myshoot-credentials"}
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("old-pw")},
+		}
+	}
+	newMock := func() *mockOntapClient {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		return mc
+	}
+
+	t.Run("password is rotated in ontap and the seed secret", func(t *testing.T) {
+		mc := newMock()
+		mc.security.On("AccountPasswordCreate", mock.Anything, mock.Anything).Return(&security.AccountPasswordCreateCreated{}, nil)
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newSecret()).Build()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		require.NoError(t, m.RotateCredentials(ctx, opts))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, secretKey, secret))
+		password := string(secret.Data["password"])
+		assert.NotEqual(t, "old-pw", password)
+		assert.NotContains(t, secret.Data, pendingPasswordKey)
+		mc.security.AssertCalled(t, "AccountPasswordCreate", mock.MatchedBy(func(p *security.AccountPasswordCreateParams) bool {
+			return *p.Info.Name == "myshoot" && string(*p.Info.Password) == password
+		}), mock.Anything)
+
+		pending, err := m.CredentialsRotationPending(ctx, opts)
+		require.NoError(t, err)
+		assert.False(t, pending)
+	})
+
+	t.Run("interrupted rotation is resumed with the same password", func(t *testing.T) {
+		mc := newMock()
+		mc.security.On("AccountPasswordCreate", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+		mc.security.On("AccountPasswordCreate", mock.Anything, mock.Anything).Return(&security.AccountPasswordCreateCreated{}, nil)
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newSecret()).Build()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		require.Error(t, m.RotateCredentials(ctx, opts))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, secretKey, secret))
+		assert.Equal(t, "old-pw", string(secret.Data["password"]))
+		pendingPassword := string(secret.Data[pendingPasswordKey])
+		require.NotEmpty(t, pendingPassword)
+
+		pending, err := m.CredentialsRotationPending(ctx, opts)
+		require.NoError(t, err)
+		assert.True(t, pending)
+
+		require.NoError(t, m.RotateCredentials(ctx, opts))
+		require.NoError(t, k8s.Get(ctx, secretKey, secret))
+		assert.Equal(t, pendingPassword, string(secret.Data["password"]))
+		assert.NotContains(t, secret.Data, pendingPasswordKey)
+	})
+}
+
+func TestWaitForBackendOnline(t *testing.T) {
+	ctx := context.Background()
+	name := BackendConfigName("proj-1", nil)
+
+	backend := func(phase, status string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(tridentBackendConfigGVK)
+		u.SetNamespace("kube-system")
+		u.SetName("ontap-proj-1-backend")
+		_ = unstructured.SetNestedField(u.Object, phase, "status", "phase")
+		_ = unstructured.SetNestedField(u.Object, status, "status", "lastOperationStatus")
+		return u
+	}
+
+	t.Run("backend online after the rotation", func(t *testing.T) {
+		shoot := fake.NewClientBuilder().WithObjects(backend("Bound", "Success")).Build()
+		versions, err := BackendConfigVersions(ctx, shoot, []string{name})
+		require.NoError(t, err)
+
+		// Trident records the operation with the rotated credentials
+		updated := backend("Bound", "Success")
+		updated.SetResourceVersion(versions[name])
+		_ = unstructured.SetNestedField(updated.Object, "Backend updated", "status", "message")
+		require.NoError(t, shoot.Update(ctx, updated))
+
+		require.NoError(t, WaitForBackendOnline(ctx, shoot, name, versions[name], time.Second))
+	})
+
+	t.Run("backend online before the rotation times out", func(t *testing.T) {
+		shoot := fake.NewClientBuilder().WithObjects(backend("Bound", "Success")).Build()
+		versions, err := BackendConfigVersions(ctx, shoot, []string{name})
+		require.NoError(t, err)
+		require.NotEmpty(t, versions[name])
+
+		require.Error(t, WaitForBackendOnline(ctx, shoot, name, versions[name], 10*time.Millisecond))
+	})
+
+	t.Run("backend created after the rotation", func(t *testing.T) {
+		shoot := fake.NewClientBuilder().Build()
+		versions, err := BackendConfigVersions(ctx, shoot, []string{name})
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+
+		require.NoError(t, shoot.Create(ctx, backend("Bound", "Success")))
+		require.NoError(t, WaitForBackendOnline(ctx, shoot, name, versions[name], time.Second))
+	})
+
+	t.Run("failed backend times out", func(t *testing.T) {
+		shoot := fake.NewClientBuilder().WithObjects(backend("Bound", "Failed")).Build()
+		err := WaitForBackendOnline(ctx, shoot, name, "", 10*time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `phase "Bound", last operation "Failed"`)
+	})
+}
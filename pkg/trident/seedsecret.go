@@ -0,0 +1,145 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+const (
+	// SeedSecretFinalizer protects the credentials secret in the shoot namespace of the seed against accidental deletion.
+	// It is only removed when the Extension is deleted or migrated.
+	SeedSecretFinalizer = "ontap.metal-stack.io/credentials"
+
+	// legacySeedSecretNamespace is the namespace in the seed where credentials secrets were stored by earlier versions.
+	legacySeedSecretNamespace = "kube-system"
+)
+
+// SeedSecretName returns the name of the credentials secret in the seed for the given SVM and shoot namespace.
+func SeedSecretName(projectID, shootNamespace string) string {
+	// Remove "shoot--" prefix from namespace for cleaner secret name
+	return fmt.Sprintf(ClusterSecretNameFormat, projectID, strings.TrimPrefix(shootNamespace, "shoot--"))
+}
+
+// protectSeedSecret adds the finalizer and makes the owner of the options the controller of the secret,
+// so the secret is garbage collected together with the Extension but cannot be deleted on its own.
+func (m *SvmManager) protectSeedSecret(secret *corev1.Secret, opts userAndSecretOptions) error {
+	controllerutil.AddFinalizer(secret, SeedSecretFinalizer)
+	if opts.secretOwner == nil {
+		return nil
+	}
+	if err := controllerutil.SetControllerReference(opts.secretOwner, secret, m.seedClient.Scheme()); err != nil {
+		return fmt.Errorf("unable to set owner of secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// migrateLegacySeedSecret moves a credentials secret from the legacy namespace into the namespace of the options.
+// A secret which already exists in the target namespace takes precedence, the legacy secret is removed in both cases.
+func (m *SvmManager) migrateLegacySeedSecret(ctx context.Context, secretName string, opts userAndSecretOptions) error {
+	if opts.svmSeedSecretNamespace == legacySeedSecretNamespace {
+		return nil
+	}
+
+	legacy := &corev1.Secret{}
+	if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: legacySeedSecretNamespace, Name: secretName}, legacy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get legacy secret %s/%s: %w", legacySeedSecretNamespace, secretName, err)
+	}
+
+	_, err := m.checkIfAccountExistsForSvm(ctx, secretName, opts.svmSeedSecretNamespace)
+	switch {
+	case errors.Is(err, ErrAlreadyExists):
+		m.log.Info("Secret already exists in shoot namespace, dropping legacy secret", "secretName", secretName)
+	case errors.Is(err, ErrSeedSecretMissing) && len(legacy.Data["password"]) == 0:
+		m.log.Info("Legacy secret does not contain a password, not migrating it", "secretName", secretName)
+	case errors.Is(err, ErrSeedSecretMissing):
+		if err := m.buildAndCreateSecretInSeed(ctx, secretName, string(legacy.Data["username"]), string(legacy.Data["password"]), opts); err != nil {
+			return fmt.Errorf("failed to migrate secret %s into namespace %s: %w", secretName, opts.svmSeedSecretNamespace, err)
+		}
+		m.log.Info("Migrated secret into shoot namespace", "secretName", secretName, "from", legacySeedSecretNamespace, "to", opts.svmSeedSecretNamespace)
+		m.recorder.Normal(ctx, events.ReasonSecretMigrated, events.ActionCreate, "seed secret %s moved from %s to %s", secretName, legacySeedSecretNamespace, opts.svmSeedSecretNamespace)
+	default:
+		return err
+	}
+
+	if err := m.seedClient.Delete(ctx, legacy); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete legacy secret %s/%s: %w", legacySeedSecretNamespace, secretName, err)
+	}
+	m.log.Info("Deleted legacy secret", "secretName", secretName, "namespace", legacySeedSecretNamespace)
+	return nil
+}
+
+// migrateRenamedSeedSecrets copies a credentials secret which is named after an alias of the naming templates to the
+// current name. A secret which already exists with the current name takes precedence, the renamed secret is removed
+// in both cases.
+func (m *SvmManager) migrateRenamedSeedSecrets(ctx context.Context, secretName string, opts userAndSecretOptions) error {
+	for _, alias := range opts.naming.SecretAliases(opts.projectID, opts.svmAliases, opts.shootNamespace) {
+		renamed := &corev1.Secret{}
+		if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: opts.svmSeedSecretNamespace, Name: alias}, renamed); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get secret %s/%s: %w", opts.svmSeedSecretNamespace, alias, err)
+		}
+
+		migrated := buildSecret(secretName, opts.svmSeedSecretNamespace, "", "", opts.projectID)
+		migrated.StringData = nil
+		migrated.Data = renamed.Data
+		if err := m.protectSeedSecret(migrated, opts); err != nil {
+			return err
+		}
+		switch err := m.seedClient.Create(ctx, migrated); {
+		case apierrors.IsAlreadyExists(err):
+			m.log.Info("Secret already exists with current name, dropping renamed secret", "secretName", secretName, "alias", alias)
+		case err != nil:
+			return fmt.Errorf("failed to migrate secret %s/%s to %s: %w", opts.svmSeedSecretNamespace, alias, secretName, err)
+		default:
+			m.log.Info("Migrated secret to current name", "secretName", secretName, "alias", alias)
+			m.recorder.Normal(ctx, events.ReasonSecretMigrated, events.ActionCreate, "seed secret %s renamed to %s", alias, secretName)
+		}
+
+		patch := client.MergeFrom(renamed.DeepCopy())
+		controllerutil.RemoveFinalizer(renamed, SeedSecretFinalizer)
+		if err := m.seedClient.Patch(ctx, renamed, patch); err != nil {
+			return fmt.Errorf("failed to remove finalizer from secret %s/%s: %w", renamed.Namespace, renamed.Name, err)
+		}
+		if err := m.seedClient.Delete(ctx, renamed); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete renamed secret %s/%s: %w", renamed.Namespace, renamed.Name, err)
+		}
+	}
+	return nil
+}
+
+// ReleaseSeedSecrets removes the finalizer from the credentials secrets in the given shoot namespace, so they can be
+// deleted together with the Extension or the shoot namespace.
+func ReleaseSeedSecrets(ctx context.Context, c client.Client, shootNamespace string) error {
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(shootNamespace), client.MatchingLabels{"app.kubernetes.io/part-of": "gardener-extension-ontap"}); err != nil {
+		return fmt.Errorf("failed to list secrets in namespace %s: %w", shootNamespace, err)
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !controllerutil.ContainsFinalizer(secret, SeedSecretFinalizer) {
+			continue
+		}
+
+		patch := client.MergeFrom(secret.DeepCopy())
+		controllerutil.RemoveFinalizer(secret, SeedSecretFinalizer)
+		if err := c.Patch(ctx, secret, patch); err != nil {
+			return fmt.Errorf("failed to remove finalizer from secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+)
+
+func TestMigrateLegacySeedSecret(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, extensionsv1alpha1.AddToScheme(scheme))
+
+	const (
+		shootNamespace = "shoot--proj--myshoot"
+		secretName     = "proj-1-proj--myshoot-credentials"
+	)
+	ex := &extensionsv1alpha1.Extension{ObjectMeta: metav1.ObjectMeta{Name: "ontap", Namespace: shootNamespace, UID: "ex-uid"}}
+	opts := userAndSecretOptions{
+		projectID:              "proj-1",
+		shootNamespace:         shootNamespace,
+		svmSeedSecretNamespace: shootNamespace,
+		secretOwner:            ex,
+	}
+	legacySecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "kube-system"},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("legacy-pw")},
+		}
+	}
+
+	t.Run("legacy secret is moved into the shoot namespace", func(t *testing.T) {
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacySecret()).Build()
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{}, k8s, nil)
+
+		require.NoError(t, m.migrateLegacySeedSecret(ctx, secretName, opts))
+
+		migrated := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, client.ObjectKey{Namespace: shootNamespace, Name: secretName}, migrated))
+		// the fake client does not merge stringData into data
+		assert.Equal(t, "legacy-pw", migrated.StringData["password"])
+		assert.Contains(t, migrated.Finalizers, SeedSecretFinalizer)
+		require.Len(t, migrated.OwnerReferences, 1)
+		assert.Equal(t, ex.UID, migrated.OwnerReferences[0].UID)
+
+		err := k8s.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: secretName}, &corev1.Secret{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		// the migration only happens once
+		require.NoError(t, m.migrateLegacySeedSecret(ctx, secretName, opts))
+	})
+
+	t.Run("existing secret in the shoot namespace takes precedence", func(t *testing.T) {
+		current := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: shootNamespace},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("current-pw")},
+		}
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacySecret(), current).Build()
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{}, k8s, nil)
+
+		require.NoError(t, m.migrateLegacySeedSecret(ctx, secretName, opts))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8s.Get(ctx, client.ObjectKey{Namespace: shootNamespace, Name: secretName}, secret))
+		assert.Equal(t, "current-pw", string(secret.Data["password"]))
+
+		err := k8s.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: secretName}, &corev1.Secret{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestMigrateRenamedSeedSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	const shootNamespace = "shoot--proj--myshoot"
+	naming, err := NewNaming(&config.NamingConfig{Secret: "{{ .SVMName }}-{{ trimPrefix \"shoot--\" .ShootNamespace }}"})
+	require.NoError(t, err)
+	opts := userAndSecretOptions{
+		projectID:              "proj-1",
+		shootNamespace:         shootNamespace,
+		svmSeedSecretNamespace: shootNamespace,
+		naming:                 naming,
+	}
+	renamed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proj-1-proj--myshoot-credentials", Namespace: shootNamespace, Finalizers: []string{SeedSecretFinalizer}},
+		Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("renamed-pw")},
+	}
+
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(renamed).Build()
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{}, k8s, nil)
+	require.NoError(t, m.migrateRenamedSeedSecrets(ctx, "proj-1-proj--myshoot", opts))
+
+	migrated := &corev1.Secret{}
+	require.NoError(t, k8s.Get(ctx, client.ObjectKey{Namespace: shootNamespace, Name: "proj-1-proj--myshoot"}, migrated))
+	assert.Equal(t, "renamed-pw", string(migrated.Data["password"]))
+	assert.Contains(t, migrated.Finalizers, SeedSecretFinalizer)
+	assert.Equal(t, "proj-1", migrated.Labels["ontap.metal-stack.io/project-id"])
+
+	err = k8s.Get(ctx, client.ObjectKey{Namespace: shootNamespace, Name: renamed.Name}, &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReleaseSeedSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := buildSecret("proj-1-proj--myshoot-credentials", "shoot--proj--myshoot", "myshoot", "pw", "proj-1")
+	secret.Finalizers = []string{SeedSecretFinalizer}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	require.NoError(t, ReleaseSeedSecrets(ctx, k8s, "shoot--proj--myshoot"))
+
+	released := &corev1.Secret{}
+	require.NoError(t, k8s.Get(ctx, client.ObjectKeyFromObject(secret), released))
+	assert.NotContains(t, released.Finalizers, SeedSecretFinalizer)
+}
@@ -0,0 +1,232 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	versionutils "github.com/gardener/gardener/pkg/utils/version"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// SnapshotPolicyName returns the name of the snapshot policy of the shoot on the SVM of its project, the policies of
+// QoS tiers are suffixed with the tier. The shoots of a project share the SVM, the policies are named after the shoot.
+func SnapshotPolicyName(shootNamespace, tier string) string {
+	if tier == "" {
+		return shootNamespace + "-snapshots"
+	}
+	return shootNamespace + "-snapshots-" + tier
+}
+
+// ValidateSnapshotFeatures returns an error if the Kubernetes version of the shoot does not serve the groupsnapshot
+// API, group snapshots require its v1beta1 version of Kubernetes 1.32.
+func ValidateSnapshotFeatures(snapshots *ontapv1alpha1.SnapshotConfig, kubernetesVersion string) error {
+	if snapshots == nil || !snapshots.GroupSnapshots {
+		return nil
+	}
+	if kubernetesVersion == "" {
+		return fmt.Errorf("group snapshots require the kubernetes version of the shoot")
+	}
+	supported, err := versionutils.CheckVersionMeetsConstraint(kubernetesVersion, ">= 1.32-0")
+	if err != nil {
+		return fmt.Errorf("invalid kubernetes version %q: %w", kubernetesVersion, err)
+	}
+	if !supported {
+		return fmt.Errorf("group snapshots require kubernetes 1.32 or later, the shoot runs %s", kubernetesVersion)
+	}
+	return nil
+}
+
+// groupSnapshotAPIVersion returns the version of the groupsnapshot API of the shoot, v1beta2 replaced v1beta1 with
+// Kubernetes 1.34.
+func groupSnapshotAPIVersion(kubernetesVersion string) (string, error) {
+	v1beta2, err := versionutils.CheckVersionMeetsConstraint(kubernetesVersion, ">= 1.34-0")
+	if err != nil {
+		return "", fmt.Errorf("invalid kubernetes version %q: %w", kubernetesVersion, err)
+	}
+	if v1beta2 {
+		return "groupsnapshot.storage.k8s.io/v1beta2", nil
+	}
+	return "groupsnapshot.storage.k8s.io/v1beta1", nil
+}
+
+// snapshotPolicies returns the snapshot policies of the shoot by name, they are validated against the QoS tiers the
+// StorageClasses of the shoot use.
+func snapshotPolicies(shootNamespace string, snapshots *ontapv1alpha1.SnapshotConfig, tiers []config.QoSTier) (map[string][]ontapv1alpha1.SnapshotSchedule, error) {
+	policies := map[string][]ontapv1alpha1.SnapshotSchedule{}
+	if snapshots == nil {
+		return policies, nil
+	}
+	if snapshots.Policy != nil {
+		policies[SnapshotPolicyName(shootNamespace, "")] = snapshots.Policy.Schedules
+	}
+	for _, p := range snapshots.TierPolicies {
+		if !slices.ContainsFunc(tiers, func(t config.QoSTier) bool { return t.Name == p.Tier }) {
+			return nil, fmt.Errorf("snapshot policy references QoS tier %q which is not used by any storage class", p.Tier)
+		}
+		policies[SnapshotPolicyName(shootNamespace, p.Tier)] = p.Schedules
+	}
+	return policies, nil
+}
+
+// tierSnapshotPolicies returns the names of the snapshot policies of the QoS tiers by tier.
+func tierSnapshotPolicies(shootNamespace string, snapshots *ontapv1alpha1.SnapshotConfig) map[string]string {
+	policies := map[string]string{}
+	if snapshots == nil {
+		return policies
+	}
+	for _, p := range snapshots.TierPolicies {
+		policies[p.Tier] = SnapshotPolicyName(shootNamespace, p.Tier)
+	}
+	return policies
+}
+
+// snapshotReserve returns the snapshot reserve of the backends, it is empty if Trident should use its default.
+func snapshotReserve(snapshots *ontapv1alpha1.SnapshotConfig) string {
+	if snapshots == nil || snapshots.Reserve == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*snapshots.Reserve))
+}
+
+// snapshotCopies returns the number of retained snapshots of the policy by schedule.
+func snapshotCopies(copies []*models.SnapshotPolicyInlineCopiesInlineArrayItem) map[string]*models.SnapshotPolicyInlineCopiesInlineArrayItem {
+	result := map[string]*models.SnapshotPolicyInlineCopiesInlineArrayItem{}
+	for _, c := range copies {
+		if c.Schedule != nil && c.Schedule.Name != nil {
+			result[*c.Schedule.Name] = c
+		}
+	}
+	return result
+}
+
+// EnsureSnapshotPolicies creates the snapshot policies of the shoot on the SVM of its project and converges the
+// schedules of existing policies. Policies are not removed, volumes may still reference them.
+func (m *SvmManager) EnsureSnapshotPolicies(ctx context.Context, opts CreateSVMOptions, snapshots *ontapv1alpha1.SnapshotConfig, tiers []config.QoSTier) (err error) {
+	policies, err := snapshotPolicies(opts.ShootNamespace, snapshots, tiers)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "EnsureSnapshotPolicies")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := m.ensureSnapshotPolicy(ctx, ontapClient, *svmUUID, opts.ProjectID, name, policies[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *SvmManager) ensureSnapshotPolicy(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName, name string, schedules []ontapv1alpha1.SnapshotSchedule) error {
+	getParams := storage.NewSnapshotPolicyCollectionGetParamsWithContext(ctx)
+	getParams.SetSvmUUID(&svmUUID)
+	getParams.SetName(&name)
+	getParams.SetFields([]string{"uuid", "name", "copies"})
+
+	result, err := ontapClient.Storage.SnapshotPolicyCollectionGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot policy %s: %w", name, err)
+	}
+
+	if result.Payload == nil || len(result.Payload.SnapshotPolicyResponseInlineRecords) == 0 {
+		m.log.Info("Creating snapshot policy", "svm", svmName, "policy", name)
+		policy := &models.SnapshotPolicy{
+			Name:    new(name),
+			Enabled: new(true),
+			Svm:     &models.SnapshotPolicyInlineSvm{UUID: new(svmUUID)},
+		}
+		for _, s := range schedules {
+			policy.SnapshotPolicyInlineCopies = append(policy.SnapshotPolicyInlineCopies, &models.SnapshotPolicyInlineCopiesInlineArrayItem{
+				Count:    new(s.Count),
+				Schedule: &models.SnapshotPolicyInlineCopiesInlineArrayItemInlineSchedule{Name: new(s.Schedule)},
+			})
+		}
+		createParams := storage.NewSnapshotPolicyCreateParamsWithContext(ctx)
+		createParams.SetInfo(policy)
+		if _, err := ontapClient.Storage.SnapshotPolicyCreate(createParams, nil); err != nil {
+			return fmt.Errorf("failed to create snapshot policy %s on SVM %s: %w", name, svmName, err)
+		}
+		m.recorder.Normal(ctx, events.ReasonSnapshotPolicyCreated, events.ActionCreate, "snapshot policy %s created on SVM %s", name, svmName)
+		return nil
+	}
+
+	policy := result.Payload.SnapshotPolicyResponseInlineRecords[0]
+	if policy.UUID == nil {
+		return fmt.Errorf("snapshot policy %s on SVM %s has no uuid", name, svmName)
+	}
+	actual := snapshotCopies(policy.SnapshotPolicyInlineCopies)
+
+	// schedules are added before others are removed, a snapshot policy needs at least one schedule
+	changed := false
+	for _, s := range schedules {
+		c, ok := actual[s.Schedule]
+		switch {
+		case !ok:
+			m.log.Info("Adding schedule to snapshot policy", "policy", name, "schedule", s.Schedule, "count", s.Count)
+			createParams := storage.NewSnapshotPolicyScheduleCreateParamsWithContext(ctx)
+			createParams.SetSnapshotPolicyUUID(*policy.UUID)
+			createParams.SetInfo(&models.SnapshotPolicySchedule{
+				Count:    new(s.Count),
+				Schedule: &models.SnapshotPolicyScheduleInlineSchedule{Name: new(s.Schedule)},
+			})
+			if _, err := ontapClient.Storage.SnapshotPolicyScheduleCreate(createParams, nil); err != nil {
+				return fmt.Errorf("failed to add schedule %s to snapshot policy %s: %w", s.Schedule, name, err)
+			}
+			changed = true
+		case c.Count == nil || *c.Count != s.Count:
+			if c.Schedule.UUID == nil {
+				return fmt.Errorf("schedule %s of snapshot policy %s has no uuid", s.Schedule, name)
+			}
+			m.log.Info("Changing retention of snapshot policy schedule", "policy", name, "schedule", s.Schedule, "count", s.Count)
+			modifyParams := storage.NewSnapshotPolicyScheduleModifyParamsWithContext(ctx)
+			modifyParams.SetSnapshotPolicyUUID(*policy.UUID)
+			modifyParams.SetScheduleUUID(*c.Schedule.UUID)
+			modifyParams.SetInfo(&models.SnapshotPolicySchedule{Count: new(s.Count)})
+			if _, err := ontapClient.Storage.SnapshotPolicyScheduleModify(modifyParams, nil); err != nil {
+				return fmt.Errorf("failed to change schedule %s of snapshot policy %s: %w", s.Schedule, name, err)
+			}
+			changed = true
+		}
+	}
+	for schedule, c := range actual {
+		if slices.ContainsFunc(schedules, func(s ontapv1alpha1.SnapshotSchedule) bool { return s.Schedule == schedule }) {
+			continue
+		}
+		if c.Schedule.UUID == nil {
+			return fmt.Errorf("schedule %s of snapshot policy %s has no uuid", schedule, name)
+		}
+		m.log.Info("Removing schedule from snapshot policy", "policy", name, "schedule", schedule)
+		deleteParams := storage.NewSnapshotPolicyScheduleDeleteParamsWithContext(ctx)
+		deleteParams.SetSnapshotPolicyUUID(*policy.UUID)
+		deleteParams.SetScheduleUUID(*c.Schedule.UUID)
+		if _, err := ontapClient.Storage.SnapshotPolicyScheduleDelete(deleteParams, nil); err != nil {
+			return fmt.Errorf("failed to remove schedule %s from snapshot policy %s: %w", schedule, name, err)
+		}
+		changed = true
+	}
+
+	if changed {
+		m.recorder.Normal(ctx, events.ReasonSnapshotPolicyUpdated, events.ActionUpdate, "schedules of snapshot policy %s on SVM %s changed", name, svmName)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestTridentBackendsSnapshots(t *testing.T) {
+	secret := "p1-credentials"
+	values := DeployTridentValues{
+		Namespace:      "shoot--p1--a",
+		ProjectId:      "p1",
+		SeedsecretName: &secret,
+		StorageClasses: []ontapv1alpha1.StorageClass{{Name: "fast", QoSTier: "gold"}, {Name: "slow"}},
+		QoSTiers:       []config.QoSTier{{Name: "gold", Fixed: &config.FixedQoS{MaxIOPS: 5000}}},
+		Snapshots: &ontapv1alpha1.SnapshotConfig{
+			Policy:       &ontapv1alpha1.SnapshotPolicy{Schedules: []ontapv1alpha1.SnapshotSchedule{{Schedule: "daily", Count: 7}}},
+			TierPolicies: []ontapv1alpha1.TierSnapshotPolicy{{Tier: "gold", Schedules: []ontapv1alpha1.SnapshotSchedule{{Schedule: "hourly", Count: 24}}}},
+			Reserve:      new(int32(10)),
+		},
+	}
+
+	got := tridentBackends(values)
+	require.Len(t, got.Backends, 1)
+	backend := got.Backends[0]
+	assert.Equal(t, "shoot--p1--a-snapshots", backend.SnapshotPolicy)
+	assert.Equal(t, "10", backend.SnapshotReserve)
+
+	policies := map[string]string{}
+	for _, pool := range backend.Pools {
+		policies[pool.QoSTier] = pool.SnapshotPolicy
+	}
+	assert.Equal(t, map[string]string{"none": "", "gold": "shoot--p1--a-snapshots-gold"}, policies)
+
+	values.Snapshots = nil
+	got = tridentBackends(values)
+	assert.Empty(t, got.Backends[0].SnapshotPolicy)
+	assert.Empty(t, got.Backends[0].SnapshotReserve)
+}
+
+func TestEnsureSnapshotPolicies(t *testing.T) {
+	ctx := context.Background()
+	opts := CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot"}
+	snapshots := &ontapv1alpha1.SnapshotConfig{
+		Policy: &ontapv1alpha1.SnapshotPolicy{Schedules: []ontapv1alpha1.SnapshotSchedule{{Schedule: "hourly", Count: 24}, {Schedule: "daily", Count: 7}}},
+	}
+
+	newClient := func(policies ...*models.SnapshotPolicy) *mockOntapClient {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		mc.storage.On("SnapshotPolicyCollectionGet", mock.MatchedBy(func(p *storage.SnapshotPolicyCollectionGetParams) bool {
+			return *p.Name == "shoot--proj--myshoot-snapshots" && *p.SvmUUID == "svm-uuid"
+		}), mock.Anything).
+			Return(&storage.SnapshotPolicyCollectionGetOK{Payload: &models.SnapshotPolicyResponse{SnapshotPolicyResponseInlineRecords: policies}}, nil)
+		return mc
+	}
+	snapshotCopy := func(schedule string, count int64) *models.SnapshotPolicyInlineCopiesInlineArrayItem {
+		return &models.SnapshotPolicyInlineCopiesInlineArrayItem{
+			Count:    new(count),
+			Schedule: &models.SnapshotPolicyInlineCopiesInlineArrayItemInlineSchedule{Name: new(schedule), UUID: new(schedule + "-uuid")},
+		}
+	}
+
+	t.Run("no-op without snapshot config", func(t *testing.T) {
+		mc := newMockOntapClient()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureSnapshotPolicies(ctx, opts, nil, nil))
+		mc.svm.AssertNotCalled(t, "SvmCollectionGet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates missing policy", func(t *testing.T) {
+		mc := newClient()
+		mc.storage.On("SnapshotPolicyCreate", mock.Anything, mock.Anything).Return(&storage.SnapshotPolicyCreateCreated{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureSnapshotPolicies(ctx, opts, snapshots, nil))
+
+		p := mc.storage.Calls[1].Arguments[0].(*storage.SnapshotPolicyCreateParams)
+		assert.Equal(t, "shoot--proj--myshoot-snapshots", *p.Info.Name)
+		assert.Equal(t, "svm-uuid", *p.Info.Svm.UUID)
+		require.Len(t, p.Info.SnapshotPolicyInlineCopies, 2)
+		assert.Equal(t, "hourly", *p.Info.SnapshotPolicyInlineCopies[0].Schedule.Name)
+		assert.Equal(t, int64(24), *p.Info.SnapshotPolicyInlineCopies[0].Count)
+	})
+
+	t.Run("no-op when the schedules match", func(t *testing.T) {
+		mc := newClient(&models.SnapshotPolicy{UUID: new("policy-uuid"), SnapshotPolicyInlineCopies: []*models.SnapshotPolicyInlineCopiesInlineArrayItem{
+			snapshotCopy("daily", 7), snapshotCopy("hourly", 24),
+		}})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureSnapshotPolicies(ctx, opts, snapshots, nil))
+		assert.Len(t, mc.storage.Calls, 1)
+	})
+
+	t.Run("converges the schedules", func(t *testing.T) {
+		mc := newClient(&models.SnapshotPolicy{UUID: new("policy-uuid"), SnapshotPolicyInlineCopies: []*models.SnapshotPolicyInlineCopiesInlineArrayItem{
+			snapshotCopy("hourly", 12), snapshotCopy("weekly", 4),
+		}})
+		mc.storage.On("SnapshotPolicyScheduleModify", mock.Anything, mock.Anything).Return(&storage.SnapshotPolicyScheduleModifyOK{}, nil)
+		mc.storage.On("SnapshotPolicyScheduleCreate", mock.Anything, mock.Anything).Return(&storage.SnapshotPolicyScheduleCreateCreated{}, nil)
+		mc.storage.On("SnapshotPolicyScheduleDelete", mock.Anything, mock.Anything).Return(&storage.SnapshotPolicyScheduleDeleteOK{}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.EnsureSnapshotPolicies(ctx, opts, snapshots, nil))
+
+		modify := mc.storage.Calls[1].Arguments[0].(*storage.SnapshotPolicyScheduleModifyParams)
+		assert.Equal(t, "policy-uuid", modify.SnapshotPolicyUUID)
+		assert.Equal(t, "hourly-uuid", modify.ScheduleUUID)
+		assert.Equal(t, int64(24), *modify.Info.Count)
+
+		create := mc.storage.Calls[2].Arguments[0].(*storage.SnapshotPolicyScheduleCreateParams)
+		assert.Equal(t, "daily", *create.Info.Schedule.Name)
+		assert.Equal(t, int64(7), *create.Info.Count)
+
+		remove := mc.storage.Calls[3].Arguments[0].(*storage.SnapshotPolicyScheduleDeleteParams)
+		assert.Equal(t, "weekly-uuid", remove.ScheduleUUID)
+	})
+
+	t.Run("unknown tier", func(t *testing.T) {
+		mc := newMockOntapClient()
+		snapshots := &ontapv1alpha1.SnapshotConfig{
+			TierPolicies: []ontapv1alpha1.TierSnapshotPolicy{{Tier: "gold", Schedules: []ontapv1alpha1.SnapshotSchedule{{Schedule: "hourly", Count: 24}}}},
+		}
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.EnsureSnapshotPolicies(ctx, opts, snapshots, nil)
+		require.ErrorContains(t, err, `QoS tier "gold" which is not used by any storage class`)
+	})
+}
+
+func TestValidateSnapshotFeatures(t *testing.T) {
+	groupSnapshots := &ontapv1alpha1.SnapshotConfig{GroupSnapshots: true}
+
+	require.NoError(t, ValidateSnapshotFeatures(nil, "1.30.0"))
+	require.NoError(t, ValidateSnapshotFeatures(&ontapv1alpha1.SnapshotConfig{InPlaceRestore: true}, "1.30.0"))
+	require.NoError(t, ValidateSnapshotFeatures(groupSnapshots, "1.32.2"))
+	require.ErrorContains(t, ValidateSnapshotFeatures(groupSnapshots, "1.31.5"), "group snapshots require kubernetes 1.32 or later, the shoot runs 1.31.5")
+	require.ErrorContains(t, ValidateSnapshotFeatures(groupSnapshots, ""), "require the kubernetes version of the shoot")
+
+	apiVersion, err := groupSnapshotAPIVersion("1.33.1")
+	require.NoError(t, err)
+	assert.Equal(t, "groupsnapshot.storage.k8s.io/v1beta1", apiVersion)
+	apiVersion, err = groupSnapshotAPIVersion("1.34.0")
+	require.NoError(t, err)
+	assert.Equal(t, "groupsnapshot.storage.k8s.io/v1beta2", apiVersion)
+}
+
+func TestRenderTemplates(t *testing.T) {
+	files := map[string][]byte{"bundle.yaml": []byte("spec:\n  debug: true\n  {{- if .GroupSnapshots }}\n  enableVolumeGroupSnapshots: true\n  {{- end }}\n")}
+
+	got, err := renderTemplates(files, tridentInit{GroupSnapshots: true})
+	require.NoError(t, err)
+	assert.Equal(t, "spec:\n  debug: true\n  enableVolumeGroupSnapshots: true\n", string(got["bundle.yaml"]))
+
+	got, err = renderTemplates(files, tridentInit{})
+	require.NoError(t, err)
+	assert.Equal(t, "spec:\n  debug: true\n", string(got["bundle.yaml"]))
+}
@@ -0,0 +1,210 @@
+package trident
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+const (
+	// noCleanupStorageClass was deployed with the no-cleanup label by earlier versions, the built-in catalog keeps it.
+	noCleanupStorageClass = "ontap-gold"
+	// thickSpaceReserve is the space reserve of the virtual storage pools of thick provisioned volumes.
+	thickSpaceReserve = "volume"
+)
+
+// builtinStorageClasses returns the StorageClasses of shoots without catalog, they keep the names of earlier versions.
+func builtinStorageClasses(protocols []ontapv1alpha1.Protocol) []ontapv1alpha1.StorageClass {
+	var classes []ontapv1alpha1.StorageClass
+	for _, p := range protocols {
+		switch p {
+		case ontapv1alpha1.ProtocolNVMe:
+			classes = append(classes,
+				ontapv1alpha1.StorageClass{Name: "ontap-gold", Protocol: p},
+				ontapv1alpha1.StorageClass{Name: "ontap-encrypted", Protocol: p, LUKS: true},
+			)
+		case ontapv1alpha1.ProtocolISCSI:
+			classes = append(classes,
+				ontapv1alpha1.StorageClass{Name: "ontap-gold-iscsi", Protocol: p},
+				ontapv1alpha1.StorageClass{Name: "ontap-encrypted-iscsi", Protocol: p, LUKS: true},
+			)
+		case ontapv1alpha1.ProtocolNFS:
+			classes = append(classes, ontapv1alpha1.StorageClass{Name: "ontap-nfs", Protocol: p})
+		}
+	}
+	return classes
+}
+
+// ShootStorageClasses returns the StorageClasses of the shoot. Shoots which declare none get the catalog of the
+// ControllerConfiguration without the classes of protocols they do not enable and without encrypted NFS classes.
+func ShootStorageClasses(tridentConfig *ontapv1alpha1.TridentConfig, catalog []ontapv1alpha1.StorageClass) []ontapv1alpha1.StorageClass {
+	if len(tridentConfig.StorageClasses) > 0 {
+		return tridentConfig.StorageClasses
+	}
+	protocols := protocolsOrDefault(tridentConfig.Protocols)
+	var classes []ontapv1alpha1.StorageClass
+	for _, sc := range catalog {
+		p := storageClassProtocol(sc, protocols[0])
+		if !slices.Contains(protocols, p) || (p == ontapv1alpha1.ProtocolNFS && sc.LUKS) {
+			continue
+		}
+		classes = append(classes, sc)
+	}
+	return classes
+}
+
+// storageClassProtocol returns the protocol of the StorageClass, classes without protocol use the given default.
+func storageClassProtocol(sc ontapv1alpha1.StorageClass, defaultProtocol ontapv1alpha1.Protocol) ontapv1alpha1.Protocol {
+	if sc.Protocol == "" {
+		return defaultProtocol
+	}
+	return sc.Protocol
+}
+
+// poolSelector returns the label of the virtual storage pools of a SAN backend with the given encryption. The iSCSI
+// pools use their own label, the selectors of the NVMe StorageClasses must not match them.
+func poolSelector(p ontapv1alpha1.Protocol, luks bool) (label, value string) {
+	switch p {
+	case ontapv1alpha1.ProtocolNVMe:
+		if luks {
+			return "luks", "true"
+		}
+		return "luks", "false"
+	case ontapv1alpha1.ProtocolISCSI:
+		if luks {
+			return "iscsi", "luks"
+		}
+		return "iscsi", "plain"
+	}
+	return "", ""
+}
+
+// poolOptions are the values the virtual storage pools and StorageClasses of a backend are rendered with.
+type poolOptions struct {
+	protocol        ontapv1alpha1.Protocol
+	defaultProtocol ontapv1alpha1.Protocol
+	svmName         string
+	tiers           map[string]config.QoSTier
+	// tiered is set if a StorageClass of the backend uses a QoS tier, all pools are labelled with their tier then
+	tiered bool
+	// snapshotPolicies are the snapshot policies of the pools of QoS tiers by tier
+	snapshotPolicies map[string]string
+}
+
+func newPoolOptions(p, defaultProtocol ontapv1alpha1.Protocol, classes []ontapv1alpha1.StorageClass, svmName string, tiers []config.QoSTier) poolOptions {
+	opts := poolOptions{protocol: p, defaultProtocol: defaultProtocol, svmName: svmName, tiers: map[string]config.QoSTier{}}
+	for _, t := range tiers {
+		opts.tiers[t.Name] = t
+	}
+	for _, sc := range classes {
+		if storageClassProtocol(sc, defaultProtocol) == p && sc.QoSTier != "" {
+			opts.tiered = true
+		}
+	}
+	return opts
+}
+
+// pool returns the virtual storage pool the volumes of the StorageClass are provisioned from.
+func (o poolOptions) pool(sc ontapv1alpha1.StorageClass) backends.Pool {
+	var pool backends.Pool
+	if o.protocol == ontapv1alpha1.ProtocolNFS {
+		pool.SpaceReserve = "none"
+	} else {
+		pool.Label, pool.Value = poolSelector(o.protocol, sc.LUKS)
+		pool.LUKS = sc.LUKS
+	}
+	if sc.ProvisioningType == ontapv1alpha1.ProvisioningThick {
+		pool.SpaceReserve = thickSpaceReserve
+	}
+	if o.tiered {
+		pool.QoSTier = ontapv1alpha1.NoQoSTier
+	}
+	if tier, ok := o.tiers[sc.QoSTier]; ok {
+		pool.QoSTier = tier.Name
+		if tier.Adaptive != nil {
+			pool.AdaptiveQoSPolicy = QoSPolicyName(o.svmName, tier.Name)
+		} else {
+			pool.QoSPolicy = QoSPolicyName(o.svmName, tier.Name)
+		}
+		pool.SnapshotPolicy = o.snapshotPolicies[tier.Name]
+	}
+	return pool
+}
+
+// selector returns the selector of the virtual storage pool of the StorageClass.
+func (o poolOptions) selector(sc ontapv1alpha1.StorageClass) string {
+	pool := o.pool(sc)
+	var terms []string
+	if pool.Label != "" {
+		terms = append(terms, pool.Label+"="+pool.Value)
+	}
+	if pool.QoSTier != "" {
+		terms = append(terms, "qos="+pool.QoSTier)
+	}
+	return strings.Join(terms, "; ")
+}
+
+// storagePools returns the virtual storage pools of the backend. SAN backends always have a thin pool with and without
+// encryption, the other pools are only added if a StorageClass uses them. The volumes of NFS backends of earlier
+// versions were provisioned from the default pool, NFS backends only get a pool for each StorageClass if one of them
+// needs thick provisioning or a QoS tier.
+func storagePools(o poolOptions, classes []ontapv1alpha1.StorageClass) []backends.Pool {
+	var pools []backends.Pool
+	if o.protocol != ontapv1alpha1.ProtocolNFS {
+		pools = append(pools, o.pool(ontapv1alpha1.StorageClass{LUKS: true}), o.pool(ontapv1alpha1.StorageClass{LUKS: false}))
+	}
+
+	for _, sc := range classes {
+		if storageClassProtocol(sc, o.defaultProtocol) != o.protocol {
+			continue
+		}
+		if o.protocol == ontapv1alpha1.ProtocolNFS && len(pools) == 0 {
+			if sc.ProvisioningType != ontapv1alpha1.ProvisioningThick && sc.QoSTier == "" && !o.tiered {
+				continue
+			}
+			pools = append(pools, o.pool(ontapv1alpha1.StorageClass{}))
+		}
+		if pool := o.pool(sc); !slices.Contains(pools, pool) {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// storageClass renders a StorageClass of the catalog, it selects the virtual storage pool of its protocol with its
+// encryption, provisioning type and QoS tier.
+func storageClass(sc ontapv1alpha1.StorageClass, o poolOptions) backends.StorageClass {
+	rendered := backends.StorageClass{
+		Name:             sc.Name,
+		Default:          sc.Default,
+		BackendType:      "ontap-san",
+		ProvisioningType: string(ontapv1alpha1.ProvisioningThin),
+		Selector:         o.selector(sc),
+		FSType:           sc.FSType,
+		Encrypted:        sc.LUKS,
+		MountOptions:     sc.MountOptions,
+	}
+	if sc.ProvisioningType != "" {
+		rendered.ProvisioningType = string(sc.ProvisioningType)
+	}
+	if sc.ReclaimPolicy != nil {
+		rendered.ReclaimPolicy = string(*sc.ReclaimPolicy)
+	}
+	if sc.VolumeBindingMode != nil {
+		rendered.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+
+	if o.protocol == ontapv1alpha1.ProtocolNFS {
+		rendered.BackendType = "ontap-nas"
+		rendered.FSType = ""
+		rendered.Encrypted = false
+		return rendered
+	}
+	if rendered.FSType == "" {
+		rendered.FSType = "ext4"
+	}
+	return rendered
+}
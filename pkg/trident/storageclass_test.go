@@ -0,0 +1,126 @@
+package trident
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	"github.com/metal-stack/gardener-extension-ontap/charts/trident/resources/backends"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestStorageClassCatalog(t *testing.T) {
+	secret := "p1-credentials"
+	values := DeployTridentValues{ProjectId: "p1", SeedsecretName: &secret}
+
+	t.Run("built-in classes of earlier versions", func(t *testing.T) {
+		got := tridentBackends(values)
+		assert.Equal(t, []backends.Pool{
+			{Label: "luks", Value: "true", LUKS: true},
+			{Label: "luks", Value: "false", LUKS: false},
+		}, got.Backends[0].Pools)
+		assert.Equal(t, []backends.StorageClass{
+			{Name: "ontap-gold", NoCleanup: true, BackendType: "ontap-san", ProvisioningType: "thin", Selector: "luks=false", FSType: "ext4"},
+			{Name: "ontap-encrypted", BackendType: "ontap-san", ProvisioningType: "thin", Selector: "luks=true", FSType: "ext4", Encrypted: true},
+		}, got.StorageClasses)
+	})
+
+	t.Run("catalog adds thick pools", func(t *testing.T) {
+		values := values
+		values.Protocols = []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolISCSI, ontapv1alpha1.ProtocolNFS}
+		values.StorageClasses = []ontapv1alpha1.StorageClass{
+			{
+				Name:              "fast",
+				Default:           true,
+				FSType:            "xfs",
+				ProvisioningType:  ontapv1alpha1.ProvisioningThick,
+				LUKS:              true,
+				ReclaimPolicy:     new(corev1.PersistentVolumeReclaimRetain),
+				VolumeBindingMode: new(storagev1.VolumeBindingWaitForFirstConsumer),
+				MountOptions:      []string{"discard"},
+			},
+			{Name: "shared", Protocol: ontapv1alpha1.ProtocolNFS, ProvisioningType: ontapv1alpha1.ProvisioningThick},
+		}
+
+		got := tridentBackends(values)
+		assert.Equal(t, []backends.Pool{
+			{Label: "iscsi", Value: "luks", LUKS: true},
+			{Label: "iscsi", Value: "plain", LUKS: false},
+			{Label: "iscsi", Value: "luks", LUKS: true, SpaceReserve: "volume"},
+		}, got.Backends[0].Pools)
+		assert.Equal(t, []backends.Pool{{SpaceReserve: "none"}, {SpaceReserve: "volume"}}, got.Backends[1].Pools)
+		assert.Equal(t, []backends.StorageClass{
+			{
+				Name:              "fast",
+				Default:           true,
+				BackendType:       "ontap-san",
+				ProvisioningType:  "thick",
+				Selector:          "iscsi=luks",
+				FSType:            "xfs",
+				Encrypted:         true,
+				ReclaimPolicy:     "Retain",
+				VolumeBindingMode: "WaitForFirstConsumer",
+				MountOptions:      []string{"discard"},
+			},
+			{Name: "shared", BackendType: "ontap-nas", ProvisioningType: "thick"},
+		}, got.StorageClasses)
+	})
+}
+
+func TestShootStorageClasses(t *testing.T) {
+	catalog := []ontapv1alpha1.StorageClass{
+		{Name: "default-protocol"},
+		{Name: "block", Protocol: ontapv1alpha1.ProtocolNVMe, LUKS: true},
+		{Name: "file", Protocol: ontapv1alpha1.ProtocolNFS},
+	}
+	names := func(classes []ontapv1alpha1.StorageClass) []string {
+		var result []string
+		for _, sc := range classes {
+			result = append(result, sc.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"default-protocol", "block"}, names(ShootStorageClasses(&ontapv1alpha1.TridentConfig{}, catalog)))
+	// encrypted classes cannot be served with NFS
+	assert.Equal(t, []string{"default-protocol", "file"}, names(ShootStorageClasses(&ontapv1alpha1.TridentConfig{Protocols: []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNFS}}, append(catalog, ontapv1alpha1.StorageClass{Name: "encrypted", LUKS: true}))))
+	assert.Equal(t, []string{"own"}, names(ShootStorageClasses(&ontapv1alpha1.TridentConfig{StorageClasses: []ontapv1alpha1.StorageClass{{Name: "own"}}}, catalog)))
+}
+
+func TestStorageClassQoSTiers(t *testing.T) {
+	secret := "p1-credentials"
+	values := DeployTridentValues{
+		ProjectId:      "p1",
+		SeedsecretName: &secret,
+		Protocols:      []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNVMe, ontapv1alpha1.ProtocolNFS},
+		StorageClasses: []ontapv1alpha1.StorageClass{
+			{Name: "gold", QoSTier: "gold"},
+			{Name: "standard"},
+			{Name: "shared", Protocol: ontapv1alpha1.ProtocolNFS, QoSTier: "silver"},
+		},
+		QoSTiers: []config.QoSTier{
+			{Name: "gold", Fixed: &config.FixedQoS{MaxIOPS: 5000}},
+			{Name: "silver", Adaptive: &config.AdaptiveQoS{ExpectedIOPSPerTB: 1000, PeakIOPSPerTB: 2000}},
+		},
+	}
+
+	got := tridentBackends(values)
+	assert.Equal(t, []backends.Pool{
+		{Label: "luks", Value: "true", LUKS: true, QoSTier: "none"},
+		{Label: "luks", Value: "false", LUKS: false, QoSTier: "none"},
+		{Label: "luks", Value: "false", LUKS: false, QoSTier: "gold", QoSPolicy: "p1-qos-gold"},
+	}, got.Backends[0].Pools)
+	assert.Equal(t, []backends.Pool{
+		{SpaceReserve: "none", QoSTier: "none"},
+		{SpaceReserve: "none", QoSTier: "silver", AdaptiveQoSPolicy: "p1-qos-silver"},
+	}, got.Backends[1].Pools)
+
+	var selectors []string
+	for _, sc := range got.StorageClasses {
+		selectors = append(selectors, sc.Selector)
+	}
+	assert.Equal(t, []string{"luks=false; qos=gold", "luks=false; qos=none", "qos=silver"}, selectors)
+}
@@ -0,0 +1,98 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+// LegacyStoragePrefix is the default storage prefix of Trident, the backends of earlier versions did not set one.
+const LegacyStoragePrefix = "trident_"
+
+// ShootStoragePrefix returns the prefix of the volumes of the shoot on the SVM of its project. ONTAP volume names must
+// not contain hyphens, they are replaced by underscores which do not occur in shoot namespaces.
+func ShootStoragePrefix(shootNamespace string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(shootNamespace, "shoot--"), "-", "_") + "_"
+}
+
+// StoragePrefix returns the storage prefix of the backends of the shoot. Trident rejects changes of the storage prefix
+// of existing backends, shoots whose backends were deployed without a recorded prefix keep the default of Trident.
+func StoragePrefix(ctx context.Context, c client.Client, shootNamespace, recorded string) (string, error) {
+	if recorded != "" {
+		return recorded, nil
+	}
+
+	mr := &resourcesv1alpha1.ManagedResource{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: shootNamespace, Name: tridentBackendsMR}, mr)
+	if client.IgnoreNotFound(err) != nil {
+		return "", fmt.Errorf("failed to get managed resource %s: %w", tridentBackendsMR, err)
+	}
+	if err == nil {
+		return LegacyStoragePrefix, nil
+	}
+	return ShootStoragePrefix(shootNamespace), nil
+}
+
+// ShootUsage returns the number, the provisioned and the used size of the volumes of the shoot on the SVM of its
+// project and records them as metrics. The volumes of shoots with the legacy storage prefix cannot be told apart from
+// the volumes of other shoots of the project, no usage is returned for them.
+func (m *SvmManager) ShootUsage(ctx context.Context, opts CreateSVMOptions, storagePrefix string) (_ *ontapv1alpha1.ShootUsageStatus, err error) {
+	if storagePrefix == "" || storagePrefix == LegacyStoragePrefix {
+		return nil, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "ShootUsage")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+
+	params := storage.NewVolumeCollectionGetParamsWithContext(ctx)
+	params.SetSvmUUID(svmUUID)
+	// the prefix of shoot a is a prefix of the one of shoot a-b as well, only the volumes of Trident are matched
+	params.SetName(new(storagePrefix + "pvc_*"))
+	params.SetFields([]string{"name", "space.size", "space.used"})
+	result, err := ontapClient.Storage.VolumeCollectionGet(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volumes of shoot %s: %w", opts.ShootNamespace, err)
+	}
+
+	var volumes, provisioned, used int64
+	if result.Payload != nil {
+		for _, volume := range result.Payload.VolumeResponseInlineRecords {
+			volumes++
+			if volume.Space == nil {
+				continue
+			}
+			if volume.Space.Size != nil {
+				provisioned += *volume.Space.Size
+			}
+			if volume.Space.Used != nil {
+				used += *volume.Space.Used
+			}
+		}
+	}
+
+	usage := &ontapv1alpha1.ShootUsageStatus{
+		Volumes:     volumes,
+		Provisioned: *resource.NewQuantity(provisioned, resource.BinarySI),
+		Used:        *resource.NewQuantity(used, resource.BinarySI),
+	}
+
+	cluster := metrics.ClusterName(ontapClient)
+	metrics.ShootVolumes.WithLabelValues(cluster, opts.ProjectID, opts.ShootNamespace).Set(float64(volumes))
+	metrics.ShootProvisionedBytes.WithLabelValues(cluster, opts.ProjectID, opts.ShootNamespace).Set(float64(provisioned))
+	metrics.ShootUsedBytes.WithLabelValues(cluster, opts.ProjectID, opts.ShootNamespace).Set(float64(used))
+	return usage, nil
+}
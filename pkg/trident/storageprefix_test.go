@@ -0,0 +1,127 @@
+package trident
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/go-logr/logr"
+	openapiruntime "github.com/go-openapi/runtime"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestShootStoragePrefix(t *testing.T) {
+	assert.Equal(t, "proj__myshoot_", ShootStoragePrefix("shoot--proj--myshoot"))
+	assert.Equal(t, "my_proj__my_shoot_", ShootStoragePrefix("shoot--my-proj--my-shoot"))
+}
+
+func TestStoragePrefix(t *testing.T) {
+	ctx := context.Background()
+	shootNamespace := "shoot--proj--myshoot"
+	scheme := runtime.NewScheme()
+	require.NoError(t, resourcesv1alpha1.AddToScheme(scheme))
+
+	t.Run("new shoots get a prefix of their own", func(t *testing.T) {
+		k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
+		prefix, err := StoragePrefix(ctx, k8s, shootNamespace, "")
+		require.NoError(t, err)
+		assert.Equal(t, "proj__myshoot_", prefix)
+	})
+
+	t.Run("deployed backends keep the default of Trident", func(t *testing.T) {
+		mr := &resourcesv1alpha1.ManagedResource{ObjectMeta: metav1.ObjectMeta{Namespace: shootNamespace, Name: tridentBackendsMR}}
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mr).Build()
+		prefix, err := StoragePrefix(ctx, k8s, shootNamespace, "")
+		require.NoError(t, err)
+		assert.Equal(t, LegacyStoragePrefix, prefix)
+
+		prefix, err = StoragePrefix(ctx, k8s, shootNamespace, "proj__myshoot_")
+		require.NoError(t, err)
+		assert.Equal(t, "proj__myshoot_", prefix)
+	})
+}
+
+func TestShootUsage(t *testing.T) {
+	ctx := context.Background()
+	opts := CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot"}
+
+	t.Run("sums the volumes of the shoot", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		mc.storage.On("VolumeCollectionGet", mock.MatchedBy(func(p *storage.VolumeCollectionGetParams) bool {
+			return *p.SvmUUID == "svm-uuid" && *p.Name == "proj__myshoot_pvc_*"
+		}), mock.Anything).
+			Return(&storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{VolumeResponseInlineRecords: []*models.Volume{
+				{Name: new("proj__myshoot_pvc_1"), Space: &models.VolumeInlineSpace{Size: new(int64(1 << 30)), Used: new(int64(1 << 20))}},
+				{Name: new("proj__myshoot_pvc_2"), Space: &models.VolumeInlineSpace{Size: new(int64(1 << 30)), Used: new(int64(1 << 21))}},
+			}}}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		usage, err := m.ShootUsage(ctx, opts, "proj__myshoot_")
+		require.NoError(t, err)
+		assert.Equal(t, &ontapv1alpha1.ShootUsageStatus{
+			Volumes:     2,
+			Provisioned: *resource.NewQuantity(2<<30, resource.BinarySI),
+			Used:        *resource.NewQuantity(3<<20, resource.BinarySI),
+		}, usage)
+	})
+
+	t.Run("skips the volumes of shoots with a longer name", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("svm-uuid")}},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+		// the volumes of the shoots myshoot and myshoot-b, filtered by name like ONTAP does
+		mc.storage.On("VolumeCollectionGet", mock.Anything, mock.Anything).
+			Return(func(p *storage.VolumeCollectionGetParams, _ openapiruntime.ClientAuthInfoWriter, _ ...storage.ClientOption) (*storage.VolumeCollectionGetOK, error) {
+				resp := &storage.VolumeCollectionGetOK{Payload: &models.VolumeResponse{}}
+				for _, name := range []string{"proj__myshoot_pvc_1", "proj__myshoot_b_pvc_1", "proj__myshoot_b_pvc_2"} {
+					if strings.HasPrefix(name, strings.TrimSuffix(*p.Name, "*")) {
+						resp.Payload.VolumeResponseInlineRecords = append(resp.Payload.VolumeResponseInlineRecords,
+							&models.Volume{Name: new(name), Space: &models.VolumeInlineSpace{Size: new(int64(1 << 30)), Used: new(int64(1 << 20))}})
+					}
+				}
+				return resp, nil
+			})
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		usage, err := m.ShootUsage(ctx, opts, ShootStoragePrefix("shoot--proj--myshoot"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), usage.Volumes)
+
+		usage, err = m.ShootUsage(ctx, CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot-b"}, ShootStoragePrefix("shoot--proj--myshoot-b"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), usage.Volumes)
+	})
+
+	t.Run("no usage for the legacy prefix", func(t *testing.T) {
+		mc := newMockOntapClient()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		usage, err := m.ShootUsage(ctx, opts, LegacyStoragePrefix)
+		require.NoError(t, err)
+		assert.Nil(t, usage)
+		mc.storage.AssertNotCalled(t, "VolumeCollectionGet", mock.Anything, mock.Anything)
+	})
+}
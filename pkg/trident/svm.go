@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,7 +20,11 @@ import (
 	"github.com/metal-stack/ontap-go/api/models"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
 	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
 )
 
 var (
@@ -29,6 +34,8 @@ var (
 	ErrAlreadyExists = errors.New("AlreadyExists")
 
 	ErrSeedSecretMissing = errors.New("SeedSecretMissing")
+	// ErrSvmNotEmpty is returned if an SVM should be deleted which still contains volumes
+	ErrSvmNotEmpty = errors.New("SvmNotEmpty")
 )
 
 // NetworkTags for SVM network interfaces
@@ -46,6 +53,49 @@ type CreateSVMOptions struct {
 	ShootNamespace         string // Full namespace like "shoot--<project>--<name>"
 	SvmIpaddresses         ontapv1alpha1.SvmIpaddresses
 	SvmSeedSecretNamespace string
+	Seed                   string                       // Name of the seed, recorded as owner of the created ONTAP objects
+	Owner                  client.Object                // Extension which owns the credentials secret in the seed
+	PasswordPolicy         *config.PasswordPolicyConfig // Policy of generated account passwords, the default policy if nil
+	// CertificateAuthentication switches the account to client certificate authentication if set
+	CertificateAuthentication *config.CertificateAuthenticationConfig
+	// AccountRole is the ONTAP role of the account, defaults to vsadmin
+	AccountRole string
+	// Naming renders the names of the LIFs and the credentials secret, the names of earlier versions are used if nil
+	Naming *Naming
+	// SVMAliases are the names of the SVM of earlier versions, existing SVMs are also found by them
+	SVMAliases []string
+	// Protocols are the protocols of the shoot, defaults to NVMe
+	Protocols []ontapv1alpha1.Protocol
+	// NodeCIDRs are the node networks of the shoot, the NFS export policy of the shoot allows access from them
+	NodeCIDRs []string
+	// Limits are the storage limit and maximum number of volumes of the SVM, the SVM is unlimited if they are not set
+	Limits ontapv1alpha1.SVMLimits
+	// ExcludedClients are the clients of clusters no SVMs of projects are placed on, e.g. the DR cluster of SVM-DR
+	ExcludedClients []*ontapv1.Ontap
+	// HandOver hands the account of the shoot over to Seed if it is owned by another seed, it is set on the restore
+	// of the shoot after a migration of its control plane
+	HandOver bool
+	// SVMProtocols are the protocols of all shoots sharing the SVM, they are enabled on the SVM and the data LIFs are
+	// assigned to them, defaults to Protocols
+	SVMProtocols []ontapv1alpha1.Protocol
+}
+
+// svmNames returns the names the SVM of the options is found by, the recorded project of the SVM is one of them.
+func (o CreateSVMOptions) svmNames() []string {
+	return svmNameCandidates(o.ProjectID, o.SVMAliases...)
+}
+
+// svmProtocols returns the protocols of the SVM of the options.
+func (o CreateSVMOptions) svmProtocols() []ontapv1alpha1.Protocol {
+	if len(o.SVMProtocols) == 0 {
+		return o.Protocols
+	}
+	return o.SVMProtocols
+}
+
+// seedSecretName returns the name of the credentials secret of the shoot in the seed.
+func (o CreateSVMOptions) seedSecretName() string {
+	return o.Naming.SecretName(o.ProjectID, o.ShootNamespace)
 }
 
 // networkInterfaceOptions holds the parameters required for createNetworkInterfaceForSvm function.
@@ -56,33 +106,92 @@ type networkInterfaceOptions struct {
 	lifName   string
 	nodeUUID  string
 	isDataLif bool
+	// protocol is the protocol a data LIF serves, it selects the service policy
+	protocol ontapv1alpha1.Protocol
 }
 
 type SvmManager struct {
 	log        logr.Logger
 	clients    []*ontapv1.Ontap
 	seedClient client.Client
+	recorder   *events.Recorder
+	// clusterNames are the configured names of the clusters of the clients
+	clusterNames map[*ontapv1.Ontap]string
+	// passwordPolicies validates the password policy before passwords are generated, no validation happens if nil
+	passwordPolicies *PasswordPolicyValidator
 }
 
-func NewSvmManager(log logr.Logger, clients []*ontapv1.Ontap, seedClient client.Client) *SvmManager {
+// NewSvmManager returns a new SvmManager, recorder may be nil if no lifecycle events should be emitted.
+func NewSvmManager(log logr.Logger, clients []*ontapv1.Ontap, seedClient client.Client, recorder *events.Recorder) *SvmManager {
 	return &SvmManager{
 		log:        log,
 		clients:    clients,
 		seedClient: seedClient,
+		recorder:   recorder,
+	}
+}
+
+// WithClusterNames sets the configured names of the clusters of the clients, they are used in the status and the
+// events of the Extensions.
+func (m *SvmManager) WithClusterNames(clusterNames map[*ontapv1.Ontap]string) *SvmManager {
+	m.clusterNames = clusterNames
+	return m
+}
+
+// WithPasswordPolicyValidator sets the validator of the password policy, the policy is validated against the password
+// rules of a cluster before the first password is generated for an account on it.
+func (m *SvmManager) WithPasswordPolicyValidator(validator *PasswordPolicyValidator) *SvmManager {
+	m.passwordPolicies = validator
+	return m
+}
+
+// validatePasswordPolicy validates the password policy against the password rules of the cluster of the client.
+func (m *SvmManager) validatePasswordPolicy(ctx context.Context, ontapClient *ontapv1.Ontap) error {
+	if m.passwordPolicies == nil {
+		return nil
+	}
+	return m.passwordPolicies.Validate(ctx, ontapClient)
+}
+
+// ClusterName returns the configured name of the cluster of the client, it is unknown if no name was set.
+func (m *SvmManager) ClusterName(ontapClient *ontapv1.Ontap) string {
+	if name, ok := m.clusterNames[ontapClient]; ok {
+		return name
 	}
+	return metrics.UnknownCluster
 }
 
-// getWriteClient dynamically selects the client with the fewest total volumes.
-func (m *SvmManager) getWriteClient(ctx context.Context) (*ontapv1.Ontap, error) {
+// getWriteClient dynamically selects the client with the fewest total volumes, excluded clients and clients of
+// MetroCluster sites in switchover or with an unknown MetroCluster state are skipped.
+func (m *SvmManager) getWriteClient(ctx context.Context, excluded ...*ontapv1.Ontap) (_ *ontapv1.Ontap, err error) {
+	ctx, span := tracing.Start(ctx, "SelectWriteClient")
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		bestClient      *ontapv1.Ontap
 		bestClientIndex       = -1
 		minVolumes      int64 = math.MaxInt64
+		// errs are the errors of the skipped clients, they are returned if no client qualifies
+		errs []error
 	)
 
 	for i, c := range m.clients {
+		if slices.Contains(excluded, c) {
+			continue
+		}
+		// SVMs cannot be created on a site of a MetroCluster in switchover
+		mode, err := metroclusterMode(ctx, c)
+		if err != nil {
+			m.log.Error(err, "skipping client with unknown metrocluster state", "client_index", i)
+			errs = append(errs, err)
+			continue
+		}
+		if isSwitchover(mode) {
+			m.log.Info("skipping client of metrocluster site in switchover", "client_index", i, "mode", mode)
+			continue
+		}
 		params := storage.NewAggregateCollectionGetParamsWithContext(ctx)
-		params.Fields = []string{"volume-count"}
+		params.Fields = []string{"volume-count", "space.block_storage.used", "space.block_storage.available"}
 
 		result, err := c.Storage.AggregateCollectionGet(params, nil)
 		if err != nil {
@@ -92,6 +201,7 @@ func (m *SvmManager) getWriteClient(ctx context.Context) (*ontapv1.Ontap, error)
 		var volumeCount int64
 		var nilCount int
 		for _, aggr := range result.Payload.AggregateResponseInlineRecords {
+			recordAggregateMetrics(c, aggr)
 			if aggr.VolumeCount == nil {
 				nilCount++
 				m.log.Info("aggregate with nil volume_count", "client_index", i, "aggregate", aggr.Name, "uuid", aggr.UUID)
@@ -110,7 +220,7 @@ func (m *SvmManager) getWriteClient(ctx context.Context) (*ontapv1.Ontap, error)
 	}
 
 	if bestClient == nil {
-		return nil, fmt.Errorf("no suitable write client found")
+		return nil, fmt.Errorf("no suitable write client found: %w", errors.Join(errs...))
 	}
 
 	m.log.Info("selected write client", "client_index", bestClientIndex, "volume_count", minVolumes)
@@ -122,7 +232,7 @@ func (m *SvmManager) CreateSVM(ctx context.Context, opts CreateSVMOptions) error
 	m.log.Info("Creating SVM with IPs", "name", opts.ProjectID, "managementLif", opts.SvmIpaddresses.ManagementLif, "dataLifs", opts.SvmIpaddresses.DataLifs)
 
 	// 0. Dynamically select the write target based on volume counts
-	writeClient, err := m.getWriteClient(ctx)
+	writeClient, err := m.getWriteClient(ctx, opts.ExcludedClients...)
 	if err != nil {
 		return fmt.Errorf("failed to select write client: %w", err)
 	}
@@ -135,98 +245,109 @@ func (m *SvmManager) CreateSVM(ctx context.Context, opts CreateSVMOptions) error
 
 	// 2. Trident needs a svm assigned, but is not exclusive to that aggregate
 	// Ontap uses all aggregates per default to assign aggregates.
-	agrgp := storage.NewAggregateCollectionGetParamsWithContext(ctx)
-	// Got these from the Swagger UI, i don't think there is another way
-	agrcget, err := writeClient.Storage.AggregateCollectionGet(agrgp, nil)
+	aggrArrayItem, err := svmAggregates(ctx, writeClient)
 	if err != nil {
 		return err
 	}
-	// Use all aggregates
-	aggragetRecord := agrcget.Payload.AggregateResponseInlineRecords
-	var aggrArrayItem []*models.SvmInlineAggregatesInlineArrayItem
-
-	m.log.Info("aggr collection get return", "agrcg", agrcget.Payload)
-
-	for _, aggregate := range aggragetRecord {
-		aggrItem := models.SvmInlineAggregatesInlineArrayItem{
-			UUID: aggregate.UUID,
-		}
-		aggrArrayItem = append(aggrArrayItem, &aggrItem)
-
-	}
 
 	m.log.Info("Assigning SVM to selected aggregate", "svm", opts.ProjectID, "aggr", aggrArrayItem)
 
 	// 3. Create the SVM without network interfaces
+	info := svmProtocols(opts.svmProtocols())
+	info.Name = &opts.ProjectID
+	info.Comment = new(NewOwnership(opts.Seed, opts.ShootNamespace, opts.ProjectID).Comment())
+	info.SvmInlineAggregates = aggrArrayItem
+	storageLimit, maxVolumes := svmLimits(opts.Limits)
+	if storageLimit > 0 {
+		info.Storage = &models.SvmInlineStorage{Limit: &storageLimit}
+	}
+	info.MaxVolumes = &maxVolumes
 	params := &s_vm.SvmCreateParams{
-		Info: &models.Svm{
-			Name:                &opts.ProjectID,
-			SvmInlineAggregates: aggrArrayItem,
-			Nvme: &models.SvmInlineNvme{
-				Enabled: new(true),
-				Allowed: new(true),
-			},
-		},
+		Info:    info,
 		Context: ctx,
 	}
 
-	m.log.Info("Sending SVM create request", "params", fmt.Sprintf("%+v", params))
+	m.log.Info("Sending SVM create request", "svm", params.Info)
 	if _, _, err = writeClient.SVM.SvmCreate(params, nil); err != nil {
 		return fmt.Errorf("failed to create SVM %s: %w", opts.ProjectID, err)
 	}
 
 	m.log.Info("SVM created successfully", "name", opts.ProjectID)
+	m.recorder.Normal(ctx, events.ReasonSVMCreated, events.ActionCreate, "SVM %s created", opts.ProjectID)
 	// 3. Wait for SVM to be ready and get its UUID
-	svmUUID, err := m.waitForSvmReady(ctx, opts.ProjectID)
+	svmUUID, err := m.waitForSvmReady(ctx, opts.ProjectID, opts.svmProtocols())
 	if err != nil {
 		return fmt.Errorf("SVM '%s' was not ready: %w", opts.ProjectID, err)
 	}
 	m.log.Info("SVM is ready", "projectId", opts.ProjectID, "uuid", svmUUID)
 
-	// 4. Create data LIFs
+	// 4. Create data and management LIFs
+	if err := m.createLIFs(ctx, writeClient, svmUUID, nodesUUIDs, opts); err != nil {
+		return err
+	}
+
+	// 7. Create user and secret in svmSeedSecretNamespace namespace
+	m.log.Info("Proceeding to create user and secret for SVM", "svm", opts.ProjectID, "shootNamespace", opts.ShootNamespace)
+	userOpts := userAndSecretOptions{
+		projectID:                 opts.ProjectID,
+		shootNamespace:            opts.ShootNamespace,
+		svmSeedSecretNamespace:    opts.SvmSeedSecretNamespace,
+		secretOwner:               opts.Owner,
+		seedClient:                m.seedClient,
+		svmUUID:                   svmUUID,
+		seed:                      opts.Seed,
+		passwordPolicy:            opts.PasswordPolicy,
+		certificateAuthentication: opts.CertificateAuthentication,
+		accountRole:               opts.AccountRole,
+		naming:                    opts.Naming,
+		svmAliases:                opts.SVMAliases,
+	}
+	if err := m.CreateUserAndSecret(ctx, writeClient, userOpts); err != nil {
+		return fmt.Errorf("SVM %s created, but failed to create user and secret: %w", opts.ProjectID, err)
+	}
+
+	m.recordLIFMetrics(ctx, writeClient, svmUUID, opts.ProjectID)
+
+	m.log.Info("Successfully completed SVM creation and setup", "svm", opts.ProjectID)
+	return nil
+}
+
+// createLIFs creates the data LIFs distributed across the given nodes and the management LIF on the first node.
+func (m *SvmManager) createLIFs(ctx context.Context, writeClient *ontapv1.Ontap, svmUUID string, nodesUUIDs []string, opts CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "CreateLIFs")
+	defer func() { tracing.End(span, err) }()
+
 	for i, datalifIp := range opts.SvmIpaddresses.DataLifs {
 		selectedNodeUUID := nodesUUIDs[i%len(nodesUUIDs)]
 		dataLifOpts := networkInterfaceOptions{
 			svmUUID:   svmUUID,
 			svmName:   opts.ProjectID,
 			ipAddress: datalifIp,
-			lifName:   fmt.Sprintf("%s+%d", dataLifTag, i),
+			lifName:   opts.Naming.DataLIFName(opts.ProjectID, i),
 			// TODO:needs to be adjusted so ips are created distributed on both nodes, PR is open for this already
 			nodeUUID:  selectedNodeUUID,
 			isDataLif: true,
+			protocol:  dataLIFProtocol(opts.svmProtocols(), i),
 		}
 		if err := m.createNetworkInterfaceForSvm(ctx, writeClient, dataLifOpts); err != nil {
 			return fmt.Errorf("failed to create data LIF for SVM %s: %w", opts.ProjectID, err)
 		}
+		m.recorder.Normal(ctx, events.ReasonLIFCreated, events.ActionCreate, "data LIF %s with ip %s created on SVM %s", dataLifOpts.lifName, datalifIp, opts.ProjectID)
 	}
 
-	// 6. Create management LIF
 	mgmtLifOpts := networkInterfaceOptions{
 		svmUUID:   svmUUID,
 		svmName:   opts.ProjectID,
 		ipAddress: opts.SvmIpaddresses.ManagementLif,
-		lifName:   managementLifTag,
+		lifName:   opts.Naming.ManagementLIFName(opts.ProjectID),
 		nodeUUID:  nodesUUIDs[0],
 		isDataLif: false,
 	}
 	if err := m.createNetworkInterfaceForSvm(ctx, writeClient, mgmtLifOpts); err != nil {
 		return fmt.Errorf("failed to create management LIF for SVM %s: %w", opts.ProjectID, err)
 	}
+	m.recorder.Normal(ctx, events.ReasonLIFCreated, events.ActionCreate, "management LIF with ip %s created on SVM %s", opts.SvmIpaddresses.ManagementLif, opts.ProjectID)
 
-	// 7. Create user and secret in svmSeedSecretNamespace namespace
-	m.log.Info("Proceeding to create user and secret for SVM", "svm", opts.ProjectID, "shootNamespace", opts.ShootNamespace)
-	userOpts := userAndSecretOptions{
-		projectID:              opts.ProjectID,
-		shootNamespace:         opts.ShootNamespace,
-		svmSeedSecretNamespace: opts.SvmSeedSecretNamespace,
-		seedClient:             m.seedClient,
-		svmUUID:                svmUUID,
-	}
-	if err := m.CreateUserAndSecret(ctx, writeClient, userOpts); err != nil {
-		return fmt.Errorf("SVM %s created, but failed to create user and secret: %w", opts.ProjectID, err)
-	}
-
-	m.log.Info("Successfully completed SVM creation and setup", "svm", opts.ProjectID)
 	return nil
 }
 
@@ -306,17 +427,8 @@ func (m *SvmManager) createNetworkInterfaceForSvm(ctx context.Context, ontapClie
 		UUID: new(opts.nodeUUID),
 	}
 	interfaceInfo.Location = location
-	if opts.isDataLif {
-		// NVMe/TCP policy
-		interfaceInfo.ServicePolicy = &models.IPInterfaceInlineServicePolicy{
-			Name: new("default-data-nvme-tcp"),
-		}
-	}
-	if !opts.isDataLif {
-		// Management policy
-		interfaceInfo.ServicePolicy = &models.IPInterfaceInlineServicePolicy{
-			Name: new("default-management"),
-		}
+	interfaceInfo.ServicePolicy = &models.IPInterfaceInlineServicePolicy{
+		Name: new(opts.servicePolicy()),
 	}
 	params.SetInfo(interfaceInfo)
 	if _, err := ontapClient.Networking.NetworkIPInterfacesCreate(params, nil); err != nil {
@@ -328,11 +440,37 @@ func (m *SvmManager) createNetworkInterfaceForSvm(ctx context.Context, ontapClie
 }
 
 // validateAndEnsureCompleteSVMState validates all components of an SVM and creates missing parts
-func (m *SvmManager) validateAndEnsureCompleteSVMState(ctx context.Context, activeClient *ontapv1.Ontap, svmUUID, svmName string, opts CreateSVMOptions) error {
+func (m *SvmManager) validateAndEnsureCompleteSVMState(ctx context.Context, activeClient *ontapv1.Ontap, svmUUID, svmName string, opts CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "ValidateSVMState")
+	defer func() { tracing.End(span, err) }()
+
 	m.log.Info("Validating complete SVM state", "svmName", svmName, "uuid", svmUUID)
 
-	// 1. Validate SVM is running and NVMe enabled
-	if err := m.validateSVMRunningState(ctx, activeClient, svmUUID, svmName); err != nil {
+	// The SVM of the partner site runs after a MetroCluster switchover, it is validated without repairs
+	activeName, err := m.svmName(ctx, activeClient, svmUUID)
+	if err != nil {
+		return err
+	}
+	if isSwitchedOverSVM(activeName) {
+		if err := m.validateSwitchedOverSVM(ctx, activeClient, svmUUID, activeName, opts); err != nil {
+			return err
+		}
+		return m.ensureSVMAccount(ctx, activeClient, svmUUID, svmName, opts)
+	}
+
+	// 0. Never touch an SVM which was not created by us
+	if err := m.ensureSVMOwnership(ctx, activeClient, svmUUID, svmName, opts); err != nil {
+		return err
+	}
+
+	// 1. Enable protocols added since the SVM was created and validate SVM is running with all protocols enabled
+	if err := m.ensureSVMProtocols(ctx, activeClient, svmUUID, svmName, opts.svmProtocols()); err != nil {
+		return err
+	}
+	if err := m.validateSVMRunningState(ctx, activeClient, svmUUID, svmName, opts.svmProtocols()); err != nil {
+		return err
+	}
+	if err := m.ensureSVMLimits(ctx, activeClient, svmUUID, svmName, opts.Limits); err != nil {
 		return err
 	}
 
@@ -343,21 +481,39 @@ func (m *SvmManager) validateAndEnsureCompleteSVMState(ctx context.Context, acti
 	}
 
 	// 3. Validate and ensure data LIFs exist
-	if err := m.validateAndEnsureDataLIFs(ctx, activeClient, svmUUID, svmName, opts.SvmIpaddresses.DataLifs, nodesUUIDs); err != nil {
+	if err := m.validateAndEnsureDataLIFs(ctx, activeClient, svmUUID, svmName, opts.Naming, opts.SvmIpaddresses.DataLifs, opts.svmProtocols(), nodesUUIDs); err != nil {
+		return err
+	}
+	if err := m.ensureDataLIFServicePolicies(ctx, activeClient, svmUUID, svmName, opts.Naming, opts.SvmIpaddresses.DataLifs, opts.svmProtocols()); err != nil {
 		return err
 	}
 
 	// 4. Validate and ensure management LIF exists
-	if err := m.validateAndEnsureManagementLIF(ctx, activeClient, svmUUID, svmName, opts.SvmIpaddresses.ManagementLif, nodesUUIDs[0]); err != nil {
+	if err := m.validateAndEnsureManagementLIF(ctx, activeClient, svmUUID, svmName, opts.Naming, opts.SvmIpaddresses.ManagementLif, nodesUUIDs[0]); err != nil {
 		return err
 	}
 
+	m.recordLIFMetrics(ctx, activeClient, svmUUID, svmName)
+
+	return m.ensureSVMAccount(ctx, activeClient, svmUUID, svmName, opts)
+}
+
+// ensureSVMAccount ensures the account of the shoot on the SVM and its credentials secret in the seed
+func (m *SvmManager) ensureSVMAccount(ctx context.Context, activeClient *ontapv1.Ontap, svmUUID, svmName string, opts CreateSVMOptions) error {
 	userOpts := userAndSecretOptions{
-		projectID:              svmName,
-		shootNamespace:         opts.ShootNamespace,
-		svmSeedSecretNamespace: opts.SvmSeedSecretNamespace,
-		seedClient:             m.seedClient,
-		svmUUID:                svmUUID,
+		projectID:                 svmName,
+		shootNamespace:            opts.ShootNamespace,
+		svmSeedSecretNamespace:    opts.SvmSeedSecretNamespace,
+		secretOwner:               opts.Owner,
+		seedClient:                m.seedClient,
+		svmUUID:                   svmUUID,
+		seed:                      opts.Seed,
+		passwordPolicy:            opts.PasswordPolicy,
+		certificateAuthentication: opts.CertificateAuthentication,
+		accountRole:               opts.AccountRole,
+		naming:                    opts.Naming,
+		svmAliases:                opts.SVMAliases,
+		handOver:                  opts.HandOver,
 	}
 	if err := m.CreateUserAndSecret(ctx, activeClient, userOpts); err != nil {
 		return fmt.Errorf("failed to ensure user and secret for SVM %s: %w", svmName, err)
@@ -367,8 +523,39 @@ func (m *SvmManager) validateAndEnsureCompleteSVMState(ctx context.Context, acti
 	return nil
 }
 
-// validateSVMRunningState checks if SVM is in running state with NVMe enabled
-func (m *SvmManager) validateSVMRunningState(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string) error {
+// ensureSVMOwnership fails if the SVM is owned by another project or was not created by the extension.
+// SVMs created before ownership was recorded are adopted by writing the ownership into their comment.
+func (m *SvmManager) ensureSVMOwnership(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, opts CreateSVMOptions) error {
+	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
+	getParams.SetUUID(svmUUID)
+	getParams.SetFields([]string{"comment"})
+
+	svmInfo, err := ontapClient.SVM.SvmGet(getParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM comment: %w", err)
+	}
+	var comment *string
+	if svmInfo.Payload != nil {
+		comment = svmInfo.Payload.Comment
+	}
+
+	adopt, err := checkSVMOwnership(svmName, comment, opts.svmNames()...)
+	if err != nil || !adopt {
+		return err
+	}
+
+	m.log.Info("Recording ownership of existing SVM", "svmName", svmName, "uuid", svmUUID)
+	modifyParams := s_vm.NewSvmModifyParamsWithContext(ctx)
+	modifyParams.SetUUID(svmUUID)
+	modifyParams.SetInfo(&models.Svm{Comment: new(NewOwnership(opts.Seed, opts.ShootNamespace, opts.ProjectID).Comment())})
+	if _, _, err := ontapClient.SVM.SvmModify(modifyParams, nil); err != nil {
+		return fmt.Errorf("failed to record ownership of SVM %s: %w", svmName, err)
+	}
+	return nil
+}
+
+// validateSVMRunningState checks if SVM is in running state with the given protocols enabled, NVMe if none are given
+func (m *SvmManager) validateSVMRunningState(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, protocols []ontapv1alpha1.Protocol) error {
 	getParams := s_vm.NewSvmGetParamsWithContext(ctx)
 	getParams.SetUUID(svmUUID)
 
@@ -382,8 +569,8 @@ func (m *SvmManager) validateSVMRunningState(ctx context.Context, ontapClient *o
 	if *svmInfo.Payload.State != "running" {
 		return fmt.Errorf("SVM is not in running state: %s", *svmInfo.Payload.State)
 	}
-	if svmInfo.Payload.Nvme == nil || svmInfo.Payload.Nvme.Enabled == nil || !*svmInfo.Payload.Nvme.Enabled {
-		return fmt.Errorf("SVM NVMe is not enabled")
+	if disabled := disabledProtocols(svmInfo.Payload, protocols); len(disabled) > 0 {
+		return fmt.Errorf("SVM protocols %v are not enabled", disabled)
 	}
 
 	return nil
@@ -413,22 +600,95 @@ func (m *SvmManager) getExistingNetworkInterfaces(ctx context.Context, ontapClie
 	return interfaces, nil
 }
 
-// validateAndEnsureDataLIFs validates all expected data LIFs exist and creates missing ones
-func (m *SvmManager) validateAndEnsureDataLIFs(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, expectedDataLifs []string, nodesUUIDs []string) error {
+// lookupLIF returns the name and ip of the existing interface with the given name or, if it does not exist, one of
+// its aliases.
+func lookupLIF(interfaces map[string]string, name string, aliases []string) (string, string, bool) {
+	for _, candidate := range append([]string{name}, aliases...) {
+		if ip, ok := interfaces[candidate]; ok {
+			return candidate, ip, true
+		}
+	}
+	return "", "", false
+}
+
+// recordLIFMetrics updates the LIF count of an SVM, failures are only logged.
+func (m *SvmManager) recordLIFMetrics(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string) {
+	interfaces, err := m.getExistingNetworkInterfaces(ctx, ontapClient, svmUUID)
+	if err != nil {
+		m.log.Error(err, "unable to record LIF metrics", "svm", svmName)
+		return
+	}
+	metrics.SVMLIFs.WithLabelValues(metrics.ClusterName(ontapClient), svmName).Set(float64(len(interfaces)))
+}
+
+// RecordClusterMetrics refreshes the aggregate metrics and the number of SVMs of all clusters, failures are only
+// logged. The metrics are recorded on every reconciliation because SVMs are neither created nor looked up on all
+// clusters.
+func (m *SvmManager) RecordClusterMetrics(ctx context.Context) {
+	for _, c := range m.clients {
+		if c == nil || c.SVM == nil || c.Storage == nil {
+			continue
+		}
+		log := m.log.WithValues("cluster", m.ClusterName(c))
+
+		aggrParams := storage.NewAggregateCollectionGetParamsWithContext(ctx)
+		aggrParams.Fields = []string{"volume-count", "space.block_storage.used", "space.block_storage.available"}
+		if result, err := c.Storage.AggregateCollectionGet(aggrParams, nil); err != nil {
+			log.Error(err, "unable to record aggregate metrics")
+		} else if result.Payload != nil {
+			for _, aggr := range result.Payload.AggregateResponseInlineRecords {
+				recordAggregateMetrics(c, aggr)
+			}
+		}
+
+		svmParams := s_vm.NewSvmCollectionGetParamsWithContext(ctx)
+		svmParams.Fields = []string{"name"}
+		if result, err := c.SVM.SvmCollectionGet(svmParams, nil); err != nil {
+			log.Error(err, "unable to record SVM metrics")
+		} else if result.Payload != nil {
+			metrics.SVMs.WithLabelValues(metrics.ClusterName(c)).Set(float64(len(result.Payload.SvmResponseInlineRecords)))
+		}
+	}
+}
+
+// recordAggregateMetrics updates the capacity and volume count of an aggregate.
+func recordAggregateMetrics(ontapClient *ontapv1.Ontap, aggr *models.Aggregate) {
+	if aggr.Name == nil {
+		return
+	}
+	cluster := metrics.ClusterName(ontapClient)
+
+	if aggr.VolumeCount != nil {
+		metrics.AggregateVolumes.WithLabelValues(cluster, *aggr.Name).Set(float64(*aggr.VolumeCount))
+	}
+	if aggr.Space == nil || aggr.Space.BlockStorage == nil {
+		return
+	}
+	if aggr.Space.BlockStorage.Used != nil {
+		metrics.AggregateUsedBytes.WithLabelValues(cluster, *aggr.Name).Set(float64(*aggr.Space.BlockStorage.Used))
+	}
+	if aggr.Space.BlockStorage.Available != nil {
+		metrics.AggregateAvailableBytes.WithLabelValues(cluster, *aggr.Name).Set(float64(*aggr.Space.BlockStorage.Available))
+	}
+}
+
+// validateAndEnsureDataLIFs validates all expected data LIFs exist and creates missing ones,
+// LIFs with the names of earlier versions are accepted
+func (m *SvmManager) validateAndEnsureDataLIFs(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, naming *Naming, expectedDataLifs []string, protocols []ontapv1alpha1.Protocol, nodesUUIDs []string) error {
 	existingInterfaces, err := m.getExistingNetworkInterfaces(ctx, ontapClient, svmUUID)
 	if err != nil {
 		return err
 	}
 
 	for i, datalifIp := range expectedDataLifs {
-		expectedLifName := fmt.Sprintf("%s+%d", dataLifTag, i)
+		expectedLifName := naming.DataLIFName(svmName, i)
 
-		if existingIP, exists := existingInterfaces[expectedLifName]; exists {
+		if existingName, existingIP, exists := lookupLIF(existingInterfaces, expectedLifName, naming.DataLIFAliases(svmName, i)); exists {
 			if existingIP == datalifIp {
-				m.log.Info("Data LIF already exists with correct IP", "lifName", expectedLifName, "ip", datalifIp)
+				m.log.Info("Data LIF already exists with correct IP", "lifName", existingName, "ip", datalifIp)
 				continue
 			} else {
-				m.log.Error(fmt.Errorf("data LIF exists but with different IP"), "skipping", "lifName", expectedLifName, "existing", existingIP, "expected", datalifIp)
+				m.log.Error(fmt.Errorf("data LIF exists but with different IP"), "skipping", "lifName", existingName, "existing", existingIP, "expected", datalifIp)
 				continue // Don't try to fix IP mismatches for now
 			}
 		}
@@ -443,23 +703,27 @@ func (m *SvmManager) validateAndEnsureDataLIFs(ctx context.Context, ontapClient
 			lifName:   expectedLifName,
 			nodeUUID:  selectedNodeUUID,
 			isDataLif: true,
+			protocol:  dataLIFProtocol(protocols, i),
 		}
 		if err := m.createNetworkInterfaceForSvm(ctx, ontapClient, dataLifOpts); err != nil {
 			return fmt.Errorf("failed to create missing data LIF %s: %w", expectedLifName, err)
 		}
+		m.recorder.Normal(ctx, events.ReasonLIFCreated, events.ActionRepair, "missing data LIF %s with ip %s recreated on SVM %s", expectedLifName, datalifIp, svmName)
 	}
 
 	return nil
 }
 
-// validateAndEnsureManagementLIF validates management LIF exists and creates if missing
-func (m *SvmManager) validateAndEnsureManagementLIF(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName, managementIP, nodeUUID string) error {
+// validateAndEnsureManagementLIF validates management LIF exists and creates if missing,
+// a LIF with the name of earlier versions is accepted
+func (m *SvmManager) validateAndEnsureManagementLIF(ctx context.Context, ontapClient *ontapv1.Ontap, svmUUID, svmName string, naming *Naming, managementIP, nodeUUID string) error {
 	existingInterfaces, err := m.getExistingNetworkInterfaces(ctx, ontapClient, svmUUID)
 	if err != nil {
 		return err
 	}
 
-	if existingIP, exists := existingInterfaces[managementLifTag]; exists {
+	lifName := naming.ManagementLIFName(svmName)
+	if _, existingIP, exists := lookupLIF(existingInterfaces, lifName, naming.ManagementLIFAliases(svmName)); exists {
 		if existingIP == managementIP {
 			m.log.Info("Management LIF already exists with correct IP", "ip", managementIP)
 			return nil
@@ -475,13 +739,14 @@ func (m *SvmManager) validateAndEnsureManagementLIF(ctx context.Context, ontapCl
 		svmUUID:   svmUUID,
 		svmName:   svmName,
 		ipAddress: managementIP,
-		lifName:   managementLifTag,
+		lifName:   lifName,
 		nodeUUID:  nodeUUID,
 		isDataLif: false,
 	}
 	if err := m.createNetworkInterfaceForSvm(ctx, ontapClient, mgmtLifOpts); err != nil {
 		return fmt.Errorf("failed to create missing management LIF: %w", err)
 	}
+	m.recorder.Normal(ctx, events.ReasonLIFCreated, events.ActionRepair, "missing management LIF with ip %s recreated on SVM %s", managementIP, svmName)
 
 	return nil
 }
@@ -491,7 +756,7 @@ func (m *SvmManager) EnsureCompleteSVM(ctx context.Context, opts CreateSVMOption
 	m.log.Info("Ensuring complete SVM state", "projectId", opts.ProjectID)
 
 	// First check if SVM exists
-	existingUUID, foundClient, err := m.GetSVMByName(ctx, opts.ProjectID)
+	existingUUID, foundClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
 	if err != nil {
 		if errors.Is(err, ErrSvmNotFound) {
 			// SVM doesn't exist, create it completely
@@ -506,13 +771,11 @@ func (m *SvmManager) EnsureCompleteSVM(ctx context.Context, opts CreateSVMOption
 	return m.validateAndEnsureCompleteSVMState(ctx, foundClient, *existingUUID, opts.ProjectID, opts)
 }
 
-// GetSVMByName searches all clients for a running SVM matching svmName (or svmName-mc).
+// GetSVMByName searches all clients for a running SVM matching svmName (or svmName-mc), the aliases are the names of
+// earlier versions and are tried in the same way after svmName.
 // Returns the UUID, the ONTAP client where the SVM is running, and an error.
-func (m *SvmManager) GetSVMByName(ctx context.Context, svmName string) (*string, *ontapv1.Ontap, error) {
-	var fallbackName string
-	if !strings.HasSuffix(svmName, "-mc") {
-		fallbackName = fmt.Sprintf("%s-mc", svmName)
-	}
+func (m *SvmManager) GetSVMByName(ctx context.Context, svmName string, aliases ...string) (*string, *ontapv1.Ontap, error) {
+	attemptedNames := svmNameCandidates(svmName, aliases...)
 
 	for _, rc := range m.clients {
 		if rc == nil || rc.SVM == nil {
@@ -530,40 +793,39 @@ func (m *SvmManager) GetSVMByName(ctx context.Context, svmName string) (*string,
 			continue
 		}
 
-		var (
-			primaryUUID  *string
-			fallbackUUID *string
-		)
-
+		uuids := map[string]*string{}
 		for _, svm := range svmGetOK.Payload.SvmResponseInlineRecords {
 			if svm.Name == nil || svm.UUID == nil {
 				continue
 			}
-			switch *svm.Name {
-			case svmName:
-				primaryUUID = svm.UUID
-			case fallbackName:
-				fallbackUUID = svm.UUID
-			}
+			uuids[*svm.Name] = svm.UUID
 		}
 
-		// Check primary name first, then fallback (-mc)
-		if uuid, ok := m.isRunningSVM(ctx, rc, primaryUUID, svmName); ok {
-			return uuid, rc, nil
-		}
-		if uuid, ok := m.isRunningSVM(ctx, rc, fallbackUUID, fallbackName); ok {
-			return uuid, rc, nil
+		// Check primary name first, then fallback (-mc), then the aliases
+		for _, name := range attemptedNames {
+			if uuid, ok := m.isRunningSVM(ctx, rc, uuids[name], name); ok {
+				return uuid, rc, nil
+			}
 		}
 	}
 
-	attemptedNames := []string{svmName}
-	if fallbackName != "" {
-		attemptedNames = append(attemptedNames, fallbackName)
-	}
 	m.log.Info("SVM not found after trying all known names on all clients", "requestedName", svmName, "attemptedNames", attemptedNames)
 	return nil, nil, ErrSvmNotFound
 }
 
+// svmNameCandidates returns the names an SVM is searched by in order of preference: svmName, its -mc name, then the
+// aliases and their -mc names.
+func svmNameCandidates(svmName string, aliases ...string) []string {
+	var names []string
+	for _, name := range append([]string{svmName}, aliases...) {
+		names = append(names, name)
+		if !strings.HasSuffix(name, "-mc") {
+			names = append(names, fmt.Sprintf("%s-mc", name))
+		}
+	}
+	return names
+}
+
 // isRunningSVM checks whether a single SVM candidate is in "running" state.
 // Returns the UUID and true if it is running, nil and false otherwise.
 func (m *SvmManager) isRunningSVM(ctx context.Context, ontapClient *ontapv1.Ontap, uuid *string, name string) (*string, bool) {
@@ -591,12 +853,15 @@ func (m *SvmManager) isRunningSVM(ctx context.Context, ontapClient *ontapv1.Onta
 	return nil, false
 }
 
-// waitForSvmReady polls until the SVM exists and is in a "running" state.
-func (m *SvmManager) waitForSvmReady(ctx context.Context, svmName string) (string, error) {
+// waitForSvmReady polls until the SVM exists, is in a "running" state and has the given protocols enabled.
+func (m *SvmManager) waitForSvmReady(ctx context.Context, svmName string, protocols []ontapv1alpha1.Protocol) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "WaitForSVMReady")
+	defer func() { tracing.End(span, err) }()
+
 	m.log.Info("waiting for SVM to be ready", "svmName", svmName)
 
 	var uuid string
-	err := retry.Do(func() error {
+	err = retry.Do(func() error {
 		svmUUID, foundClient, err := m.GetSVMByName(ctx, svmName)
 		if err != nil {
 			if errors.Is(err, ErrSvmNotFound) {
@@ -628,14 +893,14 @@ func (m *SvmManager) waitForSvmReady(ctx context.Context, svmName string) (strin
 			return fmt.Errorf("svm exist but not in running state yet:%s", currentState)
 		}
 
-		if svmInfo.Payload.Nvme != nil && svmInfo.Payload.Nvme.Enabled != nil && *svmInfo.Payload.Nvme.Enabled {
-			m.log.Info("SVM is ready and NVMe is enabled", "svmName", svmName, "uuid", svmUUID, "state", currentState)
-			uuid = *svmUUID
-			return nil
+		if disabled := disabledProtocols(svmInfo.Payload, protocols); len(disabled) > 0 {
+			m.log.Info("SVM is running but protocols are not yet enabled, retrying...", "svmName", svmName, "protocols", disabled)
+			return fmt.Errorf("SVM is running but protocols %v are not yet enabled", disabled)
 		}
 
-		m.log.Info("SVM is running but NVMe is not yet enabled, retrying...", "svmName", svmName)
-		return fmt.Errorf("SVM is running but NVMe is not yet enabled")
+		m.log.Info("SVM is ready and protocols are enabled", "svmName", svmName, "uuid", svmUUID, "state", currentState)
+		uuid = *svmUUID
+		return nil
 	},
 		retry.Attempts(10),
 		retry.MaxDelay(5*time.Second),
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 	ontapv1 "github.com/metal-stack/ontap-go/api/client"
 	"github.com/metal-stack/ontap-go/api/client/cluster"
@@ -13,9 +14,12 @@ import (
 	"github.com/metal-stack/ontap-go/api/client/s_vm"
 	"github.com/metal-stack/ontap-go/api/client/storage"
 	"github.com/metal-stack/ontap-go/api/models"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
 )
 
 func TestGetWriteClient(t *testing.T) {
@@ -23,6 +27,8 @@ func TestGetWriteClient(t *testing.T) {
 
 	t.Run("selects client with fewest volumes", func(t *testing.T) {
 		mc1, mc2 := newMockOntapClient(), newMockOntapClient()
+		withMetroClusterMode(mc1, "normal")
+		withMetroClusterMode(mc2, "normal")
 		mc1.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
 			Return(&storage.AggregateCollectionGetOK{Payload: &models.AggregateResponse{
 				AggregateResponseInlineRecords: []*models.Aggregate{
@@ -36,7 +42,7 @@ func TestGetWriteClient(t *testing.T) {
 				},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		got, err := m.getWriteClient(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, mc2.client, got)
@@ -44,16 +50,17 @@ func TestGetWriteClient(t *testing.T) {
 
 	t.Run("error on api failure", func(t *testing.T) {
 		mc := newMockOntapClient()
+		withMetroClusterMode(mc, metroclusterNotConfigured)
 		mc.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
 			Return(nil, fmt.Errorf("connection refused"))
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
 		_, err := m.getWriteClient(ctx)
 		require.Error(t, err)
 	})
 
 	t.Run("no clients", func(t *testing.T) {
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{}, nil, nil)
 		_, err := m.getWriteClient(ctx)
 		require.Error(t, err)
 	})
@@ -73,7 +80,7 @@ func TestGetAllNodesInCluster(t *testing.T) {
 				},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), nil, nil)
+		m := NewSvmManager(logr.Discard(), nil, nil, nil)
 		uuids, err := m.getAllNodesInCluster(ctx, mc.client)
 		require.NoError(t, err)
 		assert.Equal(t, []string{"node-1", "node-2"}, uuids)
@@ -89,7 +96,7 @@ func TestGetAllNodesInCluster(t *testing.T) {
 				},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), nil, nil)
+		m := NewSvmManager(logr.Discard(), nil, nil, nil)
 		uuids, err := m.getAllNodesInCluster(ctx, mc.client)
 		require.NoError(t, err)
 		assert.Equal(t, []string{"node-1"}, uuids)
@@ -111,7 +118,7 @@ func TestGetSVMByName(t *testing.T) {
 			}}, nil)
 		mc.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
 		uuid, c, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-1", *uuid)
@@ -128,12 +135,28 @@ func TestGetSVMByName(t *testing.T) {
 			}}, nil)
 		mc.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
 		uuid, _, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-mc", *uuid)
 	})
 
+	t.Run("falls back to alias", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+				SvmResponseInlineRecords: []*models.Svm{
+					{Name: new("pproj1-mc"), UUID: new("uuid-legacy")},
+				},
+			}}, nil)
+		mc.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		uuid, _, err := m.GetSVMByName(ctx, "svm_proj1", "pproj1")
+		require.NoError(t, err)
+		assert.Equal(t, "uuid-legacy", *uuid)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		mc := newMockOntapClient()
 		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
@@ -141,7 +164,7 @@ func TestGetSVMByName(t *testing.T) {
 				SvmResponseInlineRecords: []*models.Svm{},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
 		_, _, err := m.GetSVMByName(ctx, "proj-1")
 		require.ErrorIs(t, err, ErrSvmNotFound)
 	})
@@ -158,7 +181,7 @@ func TestGetSVMByName(t *testing.T) {
 			}}, nil)
 		mc2.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		uuid, _, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-2", *uuid)
@@ -191,7 +214,7 @@ func TestGetSVMByName_MultiClientFailover(t *testing.T) {
 			}}, nil)
 		mc2.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		uuid, c, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-c2", *uuid)
@@ -213,7 +236,7 @@ func TestGetSVMByName_MultiClientFailover(t *testing.T) {
 			}}, nil)
 		mc2.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		uuid, c, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-mc", *uuid)
@@ -231,7 +254,7 @@ func TestGetSVMByName_MultiClientFailover(t *testing.T) {
 			}}, nil)
 		mc.svm.On("SvmGet", mock.Anything, mock.Anything).Return(runningSvmGet, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
 		uuid, _, err := m.GetSVMByName(ctx, "proj-1")
 		require.NoError(t, err)
 		assert.Equal(t, "uuid-primary", *uuid, "primary name should be preferred over -mc")
@@ -240,7 +263,7 @@ func TestGetSVMByName_MultiClientFailover(t *testing.T) {
 
 func TestIsRunningSVM(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("nil uuid returns false", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -274,6 +297,8 @@ func TestGetWriteClient_FailoverBehavior(t *testing.T) {
 	t.Run("first client down fails entire selection", func(t *testing.T) {
 		// Documents current behavior: getWriteClient does NOT skip failing clients
 		mc1, mc2 := newMockOntapClient(), newMockOntapClient()
+		withMetroClusterMode(mc1, "normal")
+		withMetroClusterMode(mc2, "normal")
 		mc1.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
 			Return(nil, fmt.Errorf("cluster unreachable"))
 		mc2.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
@@ -283,7 +308,7 @@ func TestGetWriteClient_FailoverBehavior(t *testing.T) {
 				},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		_, err := m.getWriteClient(ctx)
 		require.Error(t, err, "getWriteClient fails hard if any client is unreachable")
 		assert.Contains(t, err.Error(), "cluster unreachable")
@@ -291,6 +316,8 @@ func TestGetWriteClient_FailoverBehavior(t *testing.T) {
 
 	t.Run("nil volume counts skipped, still selects correctly", func(t *testing.T) {
 		mc1, mc2 := newMockOntapClient(), newMockOntapClient()
+		withMetroClusterMode(mc1, "normal")
+		withMetroClusterMode(mc2, "normal")
 		// Client1: all aggregates have nil volume counts → treated as 0
 		mc1.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
 			Return(&storage.AggregateCollectionGetOK{Payload: &models.AggregateResponse{
@@ -307,7 +334,7 @@ func TestGetWriteClient_FailoverBehavior(t *testing.T) {
 				},
 			}}, nil)
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
 		got, err := m.getWriteClient(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, mc1.client, got, "nil volumes counted as 0 → client1 appears emptier")
@@ -316,7 +343,7 @@ func TestGetWriteClient_FailoverBehavior(t *testing.T) {
 
 func TestGetExistingNetworkInterfaces(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("skips interfaces with nil name or ip", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -339,7 +366,7 @@ func TestGetExistingNetworkInterfaces(t *testing.T) {
 
 func TestValidateAndEnsureDataLIFs_IPMismatch(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("tolerates ip mismatch without creating new lif", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -352,7 +379,7 @@ func TestValidateAndEnsureDataLIFs_IPMismatch(t *testing.T) {
 			}}, nil)
 
 		// Expected IP differs from existing
-		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", []string{"10.0.0.1"}, []string{"n1", "n2"})
+		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", nil, []string{"10.0.0.1"}, nil, []string{"n1", "n2"})
 		require.NoError(t, err)
 		mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
 	})
@@ -360,7 +387,7 @@ func TestValidateAndEnsureDataLIFs_IPMismatch(t *testing.T) {
 
 func TestValidateSVMRunningState(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("ok when running with nvme", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -370,7 +397,7 @@ func TestValidateSVMRunningState(t *testing.T) {
 				Nvme:  &models.SvmInlineNvme{Enabled: new(true)},
 			},
 		}, nil)
-		require.NoError(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm"))
+		require.NoError(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm", nil))
 	})
 
 	t.Run("error when stopped", func(t *testing.T) {
@@ -381,7 +408,7 @@ func TestValidateSVMRunningState(t *testing.T) {
 				Nvme:  &models.SvmInlineNvme{Enabled: new(true)},
 			},
 		}, nil)
-		require.Error(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm"))
+		require.Error(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm", nil))
 	})
 
 	t.Run("error when nvme disabled", func(t *testing.T) {
@@ -392,13 +419,13 @@ func TestValidateSVMRunningState(t *testing.T) {
 				Nvme:  &models.SvmInlineNvme{Enabled: new(false)},
 			},
 		}, nil)
-		require.Error(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm"))
+		require.Error(t, m.validateSVMRunningState(ctx, mc.client, "uuid", "svm", nil))
 	})
 }
 
 func TestCreateNetworkInterfaceForSvm(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	noBgp := &networking.NetworkIPBgpPeerGroupsGetOK{
 		Payload: &models.BgpPeerGroupResponse{NumRecords: new(int64(0))},
@@ -459,7 +486,7 @@ func TestCreateNetworkInterfaceForSvm(t *testing.T) {
 
 func TestValidateAndEnsureDataLIFs(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("no-op when all lifs exist", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -471,7 +498,7 @@ func TestValidateAndEnsureDataLIFs(t *testing.T) {
 				},
 			}}, nil)
 
-		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", []string{"10.0.0.1"}, []string{"n1", "n2"})
+		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", nil, []string{"10.0.0.1"}, nil, []string{"n1", "n2"})
 		require.NoError(t, err)
 		mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
 	})
@@ -489,7 +516,7 @@ func TestValidateAndEnsureDataLIFs(t *testing.T) {
 		mc.networking.On("NetworkIPInterfacesCreate", mock.Anything, mock.Anything).
 			Return(&networking.NetworkIPInterfacesCreateCreated{}, nil)
 
-		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", []string{"10.0.0.1"}, []string{"n1", "n2"})
+		err := m.validateAndEnsureDataLIFs(ctx, mc.client, "uuid", "svm", nil, []string{"10.0.0.1"}, nil, []string{"n1", "n2"})
 		require.NoError(t, err)
 		mc.networking.AssertCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
 	})
@@ -497,7 +524,7 @@ func TestValidateAndEnsureDataLIFs(t *testing.T) {
 
 func TestValidateAndEnsureManagementLIF(t *testing.T) {
 	ctx := context.Background()
-	m := NewSvmManager(logr.Discard(), nil, nil)
+	m := NewSvmManager(logr.Discard(), nil, nil, nil)
 
 	t.Run("no-op when exists with correct ip", func(t *testing.T) {
 		mc := newMockOntapClient()
@@ -509,7 +536,7 @@ func TestValidateAndEnsureManagementLIF(t *testing.T) {
 				},
 			}}, nil)
 
-		err := m.validateAndEnsureManagementLIF(ctx, mc.client, "uuid", "svm", "10.0.0.100", "n1")
+		err := m.validateAndEnsureManagementLIF(ctx, mc.client, "uuid", "svm", nil, "10.0.0.100", "n1")
 		require.NoError(t, err)
 		mc.networking.AssertNotCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
 	})
@@ -527,8 +554,47 @@ func TestValidateAndEnsureManagementLIF(t *testing.T) {
 		mc.networking.On("NetworkIPInterfacesCreate", mock.Anything, mock.Anything).
 			Return(&networking.NetworkIPInterfacesCreateCreated{}, nil)
 
-		err := m.validateAndEnsureManagementLIF(ctx, mc.client, "uuid", "svm", "10.0.0.100", "n1")
+		err := m.validateAndEnsureManagementLIF(ctx, mc.client, "uuid", "svm", nil, "10.0.0.100", "n1")
 		require.NoError(t, err)
 		mc.networking.AssertCalled(t, "NetworkIPInterfacesCreate", mock.Anything, mock.Anything)
 	})
 }
+
+func TestRecordClusterMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	newClient := func(cluster string, svms int) *mockOntapClient {
+		mc := newMockOntapClient()
+		instrumented := ontapv1.New(httptransport.New("localhost", "/api", nil), strfmt.Default)
+		metrics.InstrumentClient(instrumented, cluster)
+		mc.client.Transport = instrumented.Transport
+
+		mc.storage.On("AggregateCollectionGet", mock.Anything, mock.Anything).
+			Return(&storage.AggregateCollectionGetOK{Payload: &models.AggregateResponse{
+				AggregateResponseInlineRecords: []*models.Aggregate{
+					{Name: new("aggr1"), VolumeCount: new(int64(svms * 10))},
+				},
+			}}, nil)
+		records := make([]*models.Svm, svms)
+		for i := range records {
+			records[i] = &models.Svm{Name: new(fmt.Sprintf("svm-%d", i))}
+		}
+		mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+			Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{SvmResponseInlineRecords: records}}, nil)
+		return mc
+	}
+
+	mc1, mc2 := newClient("metrics-a", 2), newClient("metrics-b", 3)
+	m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc1.client, mc2.client}, nil, nil)
+	m.RecordClusterMetrics(ctx)
+
+	// all clusters are recorded, not only the cluster an SVM is found or created on
+	for cluster, want := range map[string]float64{"metrics-a": 2, "metrics-b": 3} {
+		gauge := &dto.Metric{}
+		require.NoError(t, metrics.SVMs.WithLabelValues(cluster).Write(gauge))
+		assert.InDelta(t, want, gauge.GetGauge().GetValue(), 0)
+
+		require.NoError(t, metrics.AggregateVolumes.WithLabelValues(cluster, "aggr1").Write(gauge))
+		assert.InDelta(t, want*10, gauge.GetGauge().GetValue(), 0)
+	}
+}
@@ -0,0 +1,353 @@
+package trident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/snap_mirror"
+	"github.com/metal-stack/ontap-go/api/client/storage"
+	"github.com/metal-stack/ontap-go/api/models"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/metrics"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
+)
+
+const (
+	// drSuffix is appended to the name of the SVM of a project for its DR SVM.
+	drSuffix = "-dr"
+	// dpDestinationSubtype is the subtype of the destination SVM of an SVM-DR relationship, it is stopped until the
+	// SVM fails over to it.
+	dpDestinationSubtype = "dp_destination"
+	// drIdentityPreservation replicates the configuration of the SVM except its network configuration, the DR SVM
+	// keeps its own LIFs.
+	drIdentityPreservation = "exclude_network_config"
+	// snapmirrorSnapmirrored is the state of a relationship which transferred its baseline.
+	snapmirrorSnapmirrored = "snapmirrored"
+	// snapmirrorBrokenOff is the state of a relationship whose destination is writable, e.g. after a failover.
+	snapmirrorBrokenOff = "broken_off"
+)
+
+// lagTimeRegex matches the ISO 8601 durations ONTAP reports the lag time of relationships in, e.g. P1DT2H3M4S.
+var lagTimeRegex = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ErrFailoverRefused is returned if the SVM cannot fail over to the DR SVM in the current state of the relationship.
+var ErrFailoverRefused = errors.New("FailoverRefused")
+
+// DRSVMName returns the name of the DR SVM of the project on the DR cluster.
+func DRSVMName(svmName string) string {
+	return svmName + drSuffix
+}
+
+// DRSVMOptions returns the options of the DR SVM of the project, it serves the protocols of the SVM after a failover.
+func DRSVMOptions(opts CreateSVMOptions, dr *ontapv1alpha1.DisasterRecoveryConfig) CreateSVMOptions {
+	drOpts := opts
+	drOpts.ProjectID = DRSVMName(opts.ProjectID)
+	drOpts.SvmIpaddresses = dr.SvmIpaddresses
+	drOpts.SVMAliases = nil
+	return drOpts
+}
+
+// EnsureSVMDR ensures the DR SVM of the project on the DR cluster, the peering of the cluster of the SVM with the DR
+// cluster and of both SVMs, and the SnapMirror SVM-DR relationship from the SVM to the DR SVM. The relationship
+// replicates the volumes and the configuration of the SVM without its LIFs, it is initialized when it is created.
+// The policy and schedule of existing relationships are not changed.
+func (m *SvmManager) EnsureSVMDR(ctx context.Context, opts CreateSVMOptions, drClient *ontapv1.Ontap, dr *ontapv1alpha1.DisasterRecoveryConfig, svmDR *config.SVMDRConfig) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureSVMDR")
+	defer func() { tracing.End(span, err) }()
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to get SVM %s: %w", opts.ProjectID, err)
+	}
+	// the SVM may still have the name of earlier versions or the -mc suffix
+	svmName, err := m.svmName(ctx, ontapClient, *svmUUID)
+	if err != nil {
+		return err
+	}
+
+	drOpts := DRSVMOptions(opts, dr)
+	if err := m.ensureDRSVM(ctx, drClient, drOpts); err != nil {
+		return err
+	}
+
+	// SVM-DR relationships are created on the DR cluster which peers with the cluster of the SVM
+	peerCluster, err := m.ensureClusterPeer(ctx, drClient, ontapClient)
+	if err != nil {
+		return err
+	}
+	if err := m.ensureSVMPeer(ctx, drClient, drOpts.ProjectID, ontapClient, svmName, peerCluster); err != nil {
+		return err
+	}
+
+	relationship, err := svmDRRelationship(ctx, drClient, drOpts.ProjectID)
+	if err != nil || relationship != nil {
+		return err
+	}
+
+	m.log.Info("Creating SVM-DR relationship", "svm", svmName, "drSVM", drOpts.ProjectID, "policy", svmDR.Policy)
+	info := &models.SnapmirrorRelationship{
+		Source:               &models.SnapmirrorSourceEndpoint{Path: new(svmName + ":")},
+		Destination:          &models.SnapmirrorEndpoint{Path: new(drOpts.ProjectID + ":")},
+		Policy:               &models.SnapmirrorRelationshipInlinePolicy{Name: &svmDR.Policy},
+		IdentityPreservation: new(drIdentityPreservation),
+		// the baseline transfer is started with the creation
+		State: new(snapmirrorSnapmirrored),
+	}
+	if svmDR.Schedule != "" {
+		info.TransferSchedule = &models.SnapmirrorRelationshipInlineTransferSchedule{Name: &svmDR.Schedule}
+	}
+	params := snap_mirror.NewSnapmirrorRelationshipCreateParamsWithContext(ctx)
+	params.SetInfo(info)
+	if _, _, err := drClient.SnapMirror.SnapmirrorRelationshipCreate(params, nil); err != nil {
+		return fmt.Errorf("failed to create SVM-DR relationship from SVM %s to SVM %s: %w", svmName, drOpts.ProjectID, err)
+	}
+
+	m.recorder.Normal(ctx, events.ReasonSVMDRCreated, events.ActionCreate, "SVM-DR relationship from SVM %s to SVM %s on cluster %s created", svmName, drOpts.ProjectID, m.ClusterName(drClient))
+	return nil
+}
+
+// ensureDRSVM ensures the DR SVM and its LIFs on the DR cluster. The DR SVM gets no account, the accounts of the SVM
+// are replicated to it and the extension creates the account of the shoot after a failover.
+func (m *SvmManager) ensureDRSVM(ctx context.Context, drClient *ontapv1.Ontap, drOpts CreateSVMOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureDRSVM")
+	defer func() { tracing.End(span, err) }()
+
+	nodesUUIDs, err := m.getAllNodesInCluster(ctx, drClient)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes of the DR cluster: %w", err)
+	}
+
+	svm, err := svmByName(ctx, drClient, drOpts.ProjectID)
+	if err != nil {
+		return err
+	}
+	if svm != nil {
+		if err := m.validateAndEnsureDataLIFs(ctx, drClient, *svm.UUID, drOpts.ProjectID, drOpts.Naming, drOpts.SvmIpaddresses.DataLifs, drOpts.svmProtocols(), nodesUUIDs); err != nil {
+			return err
+		}
+		return m.validateAndEnsureManagementLIF(ctx, drClient, *svm.UUID, drOpts.ProjectID, drOpts.Naming, drOpts.SvmIpaddresses.ManagementLif, nodesUUIDs[0])
+	}
+
+	aggregates, err := svmAggregates(ctx, drClient)
+	if err != nil {
+		return err
+	}
+
+	m.log.Info("Creating DR SVM", "name", drOpts.ProjectID, "managementLif", drOpts.SvmIpaddresses.ManagementLif, "dataLifs", drOpts.SvmIpaddresses.DataLifs)
+	params := s_vm.NewSvmCreateParamsWithContext(ctx)
+	params.SetInfo(&models.Svm{
+		Name:                &drOpts.ProjectID,
+		Subtype:             new(dpDestinationSubtype),
+		Comment:             new(NewOwnership(drOpts.Seed, drOpts.ShootNamespace, drOpts.ProjectID).Comment()),
+		SvmInlineAggregates: aggregates,
+	})
+	if _, _, err := drClient.SVM.SvmCreate(params, nil); err != nil {
+		return fmt.Errorf("failed to create DR SVM %s: %w", drOpts.ProjectID, err)
+	}
+	m.recorder.Normal(ctx, events.ReasonSVMCreated, events.ActionCreate, "DR SVM %s created", drOpts.ProjectID)
+
+	// the DR SVM is stopped, it is therefore not found by GetSVMByName
+	err = retry.Do(func() error {
+		svm, err = svmByName(ctx, drClient, drOpts.ProjectID)
+		if err == nil && svm == nil {
+			err = ErrSvmNotFound
+		}
+		return err
+	},
+		retry.Attempts(10),
+		retry.MaxDelay(5*time.Second),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		return fmt.Errorf("DR SVM %s was not created: %w", drOpts.ProjectID, err)
+	}
+
+	return m.createLIFs(ctx, drClient, *svm.UUID, nodesUUIDs, drOpts)
+}
+
+// svmByName returns the SVM with the given name in any state, it is nil if the SVM does not exist.
+func svmByName(ctx context.Context, ontapClient *ontapv1.Ontap, name string) (*models.Svm, error) {
+	params := s_vm.NewSvmCollectionGetParamsWithContext(ctx)
+	params.SetName(&name)
+	params.SetFields([]string{"uuid", "name", "state"})
+
+	result, err := ontapClient.SVM.SvmCollectionGet(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM %s: %w", name, err)
+	}
+	if result.Payload == nil {
+		return nil, nil
+	}
+	for _, svm := range result.Payload.SvmResponseInlineRecords {
+		if svm.Name != nil && *svm.Name == name && svm.UUID != nil {
+			return svm, nil
+		}
+	}
+	return nil, nil
+}
+
+// svmAggregates returns all aggregates of the cluster, Trident needs the aggregates assigned to the SVM but is not
+// restricted to a single aggregate.
+func svmAggregates(ctx context.Context, ontapClient *ontapv1.Ontap) ([]*models.SvmInlineAggregatesInlineArrayItem, error) {
+	result, err := ontapClient.Storage.AggregateCollectionGet(storage.NewAggregateCollectionGetParamsWithContext(ctx), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregates: %w", err)
+	}
+
+	var aggregates []*models.SvmInlineAggregatesInlineArrayItem
+	if result.Payload != nil {
+		for _, aggregate := range result.Payload.AggregateResponseInlineRecords {
+			aggregates = append(aggregates, &models.SvmInlineAggregatesInlineArrayItem{UUID: aggregate.UUID})
+		}
+	}
+	return aggregates, nil
+}
+
+// svmDRRelationship returns the SVM-DR relationship to the DR SVM, it is nil if it does not exist.
+func svmDRRelationship(ctx context.Context, drClient *ontapv1.Ontap, drName string) (*models.SnapmirrorRelationship, error) {
+	params := snap_mirror.NewSnapmirrorRelationshipsGetParamsWithContext(ctx)
+	params.SetDestinationPath(new(drName + ":"))
+	params.SetFields([]string{"uuid", "state", "healthy", "unhealthy_reason", "lag_time"})
+
+	result, err := drClient.SnapMirror.SnapmirrorRelationshipsGet(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SVM-DR relationship of SVM %s: %w", drName, err)
+	}
+	if result.Payload == nil || len(result.Payload.SnapmirrorRelationshipResponseInlineRecords) == 0 {
+		return nil, nil
+	}
+	return result.Payload.SnapmirrorRelationshipResponseInlineRecords[0], nil
+}
+
+// SVMDRState returns the state of the SVM-DR relationship to the DR SVM, the state is empty if the relationship does
+// not exist yet. The SVM failed over if the relationship is broken off and the DR SVM is running.
+func (m *SvmManager) SVMDRState(ctx context.Context, drClient *ontapv1.Ontap, drName string) (*ontapv1alpha1.DisasterRecoveryStatus, error) {
+	relationship, err := svmDRRelationship(ctx, drClient, drName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ontapv1alpha1.DisasterRecoveryStatus{SVM: drName}
+	if relationship == nil {
+		return status, nil
+	}
+	if relationship.State != nil {
+		status.State = *relationship.State
+	}
+	if relationship.Healthy != nil {
+		status.Healthy = *relationship.Healthy
+	}
+	if relationship.LagTime != nil {
+		status.LagTime = *relationship.LagTime
+	}
+	var reasons []string
+	for _, reason := range relationship.SnapmirrorRelationshipInlineUnhealthyReason {
+		if reason != nil && reason.Message != nil {
+			reasons = append(reasons, *reason.Message)
+		}
+	}
+	status.UnhealthyReason = strings.Join(reasons, "; ")
+
+	healthy := 0.0
+	if status.Healthy {
+		healthy = 1
+	}
+	metrics.SVMDRHealthy.WithLabelValues(metrics.ClusterName(drClient), drName).Set(healthy)
+	if lag, ok := parseLagTime(status.LagTime); ok {
+		metrics.SVMDRLag.WithLabelValues(metrics.ClusterName(drClient), drName).Set(lag.Seconds())
+	}
+
+	if status.State == snapmirrorBrokenOff {
+		svm, err := svmByName(ctx, drClient, drName)
+		if err != nil {
+			return nil, err
+		}
+		status.FailedOver = svm != nil && svm.State != nil && *svm.State == "running"
+	}
+	return status, nil
+}
+
+// parseLagTime parses the lag time of a relationship, it returns false if the lag time is empty or invalid.
+func parseLagTime(lagTime string) (time.Duration, bool) {
+	match := lagTimeRegex.FindStringSubmatch(lagTime)
+	if match == nil || lagTime == "P" {
+		return 0, false
+	}
+	var lag time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		lag += time.Duration(n) * unit
+	}
+	return lag, true
+}
+
+// FailoverSVMDR fails the SVM over to the DR SVM and waits until the DR SVM is running. ONTAP validates the failover,
+// stops the SVM and starts the DR SVM with the replicated volumes. The failover is refused if the relationship does
+// not exist or is not snapmirrored, e.g. before its baseline transfer finished or after an earlier failover.
+func (m *SvmManager) FailoverSVMDR(ctx context.Context, drClient *ontapv1.Ontap, drName string) (err error) {
+	ctx, span := tracing.Start(ctx, "FailoverSVMDR")
+	defer func() { tracing.End(span, err) }()
+
+	relationship, err := svmDRRelationship(ctx, drClient, drName)
+	if err != nil {
+		return err
+	}
+	if relationship == nil || relationship.UUID == nil {
+		return fmt.Errorf("SVM-DR relationship to SVM %s does not exist: %w", drName, ErrFailoverRefused)
+	}
+	if relationship.State == nil || *relationship.State != snapmirrorSnapmirrored {
+		state := ""
+		if relationship.State != nil {
+			state = *relationship.State
+		}
+		return fmt.Errorf("SVM-DR relationship to SVM %s is %q instead of %s: %w", drName, state, snapmirrorSnapmirrored, ErrFailoverRefused)
+	}
+
+	m.log.Info("Failing over to DR SVM", "drSVM", drName, "relationship", relationship.UUID.String())
+	params := snap_mirror.NewSnapmirrorRelationshipModifyParamsWithContext(ctx)
+	params.SetUUID(relationship.UUID.String())
+	params.SetFailover(new(true))
+	params.SetInfo(&models.SnapmirrorRelationship{})
+	if _, _, err := drClient.SnapMirror.SnapmirrorRelationshipModify(params, nil); err != nil {
+		return fmt.Errorf("failed to fail over to SVM %s: %w", drName, err)
+	}
+
+	// the failover is executed by a job
+	err = retry.Do(func() error {
+		status, err := m.SVMDRState(ctx, drClient, drName)
+		if err != nil {
+			return err
+		}
+		if !status.FailedOver {
+			return fmt.Errorf("SVM-DR relationship to SVM %s is %q", drName, status.State)
+		}
+		return nil
+	},
+		retry.Attempts(10),
+		retry.MaxDelay(5*time.Second),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failover to SVM %s did not finish: %w", drName, err)
+	}
+
+	m.recorder.Normal(ctx, events.ReasonFailedOver, events.ActionFailover, "SVM failed over to SVM %s on cluster %s", drName, m.ClusterName(drClient))
+	return nil
+}
@@ -0,0 +1,160 @@
+package trident
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-openapi/strfmt"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/snap_mirror"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	ontapv1alpha1 "github.com/metal-stack/gardener-extension-ontap/pkg/apis/ontap/v1alpha1"
+)
+
+func TestDRSVMOptions(t *testing.T) {
+	opts := CreateSVMOptions{
+		ProjectID:      "proj-1",
+		SvmIpaddresses: ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.0.1", DataLifs: []string{"10.0.0.2"}},
+		SVMAliases:     []string{"proj-old"},
+		Protocols:      []ontapv1alpha1.Protocol{ontapv1alpha1.ProtocolNFS},
+	}
+	dr := ontapv1alpha1.SvmIpaddresses{ManagementLif: "10.0.2.1", DataLifs: []string{"10.0.2.2"}}
+
+	got := DRSVMOptions(opts, &ontapv1alpha1.DisasterRecoveryConfig{SvmIpaddresses: dr})
+	assert.Equal(t, "proj-1-dr", got.ProjectID)
+	assert.Equal(t, dr, got.SvmIpaddresses)
+	assert.Empty(t, got.SVMAliases)
+	assert.Equal(t, opts.Protocols, got.Protocols)
+	assert.Equal(t, "proj-1", opts.ProjectID)
+}
+
+func TestParseLagTime(t *testing.T) {
+	tests := []struct {
+		lagTime string
+		want    time.Duration
+		ok      bool
+	}{
+		{lagTime: "PT8H35M42S", want: 8*time.Hour + 35*time.Minute + 42*time.Second, ok: true},
+		{lagTime: "P1DT2M", want: 24*time.Hour + 2*time.Minute, ok: true},
+		{lagTime: "PT0S", ok: true},
+		{lagTime: ""},
+		{lagTime: "P"},
+		{lagTime: "8h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lagTime, func(t *testing.T) {
+			got, ok := parseLagTime(tt.lagTime)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func drRelationship(mc *mockOntapClient, state string, healthy bool) *mock.Call {
+	return mc.snapmirror.On("SnapmirrorRelationshipsGet", mock.Anything, mock.Anything).
+		Return(&snap_mirror.SnapmirrorRelationshipsGetOK{Payload: &models.SnapmirrorRelationshipResponse{SnapmirrorRelationshipResponseInlineRecords: []*models.SnapmirrorRelationship{
+			{UUID: new(strfmt.UUID("rel-uuid")), State: new(state), Healthy: new(healthy)},
+		}}}, nil)
+}
+
+func drSVM(mc *mockOntapClient, state string) {
+	mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+		Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+			SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1-dr"), UUID: new("svm-uuid"), State: new(state)}},
+		}}, nil)
+}
+
+func TestSVMDRState(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty state without relationship", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.snapmirror.On("SnapmirrorRelationshipsGet", mock.Anything, mock.Anything).
+			Return(&snap_mirror.SnapmirrorRelationshipsGetOK{Payload: &models.SnapmirrorRelationshipResponse{}}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		status, err := m.SVMDRState(ctx, mc.client, "proj-1-dr")
+		require.NoError(t, err)
+		assert.Equal(t, &ontapv1alpha1.DisasterRecoveryStatus{SVM: "proj-1-dr"}, status)
+	})
+
+	t.Run("healthy snapmirrored relationship", func(t *testing.T) {
+		mc := newMockOntapClient()
+		drRelationship(mc, "snapmirrored", true)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		status, err := m.SVMDRState(ctx, mc.client, "proj-1-dr")
+		require.NoError(t, err)
+		assert.Equal(t, "snapmirrored", status.State)
+		assert.True(t, status.Healthy)
+		assert.False(t, status.FailedOver)
+		mc.svm.AssertNotCalled(t, "SvmCollectionGet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed over when broken off and the DR SVM runs", func(t *testing.T) {
+		mc := newMockOntapClient()
+		drRelationship(mc, "broken_off", true)
+		drSVM(mc, "running")
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		status, err := m.SVMDRState(ctx, mc.client, "proj-1-dr")
+		require.NoError(t, err)
+		assert.True(t, status.FailedOver)
+	})
+
+	t.Run("not failed over when the DR SVM is stopped", func(t *testing.T) {
+		mc := newMockOntapClient()
+		drRelationship(mc, "broken_off", true)
+		drSVM(mc, "stopped")
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		status, err := m.SVMDRState(ctx, mc.client, "proj-1-dr")
+		require.NoError(t, err)
+		assert.False(t, status.FailedOver)
+	})
+}
+
+func TestFailoverSVMDR(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("refused without relationship", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.snapmirror.On("SnapmirrorRelationshipsGet", mock.Anything, mock.Anything).
+			Return(&snap_mirror.SnapmirrorRelationshipsGetOK{Payload: &models.SnapmirrorRelationshipResponse{}}, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.FailoverSVMDR(ctx, mc.client, "proj-1-dr")
+		require.ErrorIs(t, err, ErrFailoverRefused)
+	})
+
+	t.Run("refused when not snapmirrored", func(t *testing.T) {
+		mc := newMockOntapClient()
+		drRelationship(mc, "uninitialized", false)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		err := m.FailoverSVMDR(ctx, mc.client, "proj-1-dr")
+		require.ErrorIs(t, err, ErrFailoverRefused)
+		mc.snapmirror.AssertNotCalled(t, "SnapmirrorRelationshipModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fails over the relationship", func(t *testing.T) {
+		mc := newMockOntapClient()
+		drRelationship(mc, "snapmirrored", true).Once()
+		drRelationship(mc, "broken_off", true)
+		drSVM(mc, "running")
+		mc.snapmirror.On("SnapmirrorRelationshipModify", mock.MatchedBy(func(p *snap_mirror.SnapmirrorRelationshipModifyParams) bool {
+			return p.UUID == "rel-uuid" && p.Failover != nil && *p.Failover
+		}), mock.Anything).Return(&snap_mirror.SnapmirrorRelationshipModifyOK{}, nil, nil)
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		require.NoError(t, m.FailoverSVMDR(ctx, mc.client, "proj-1-dr"))
+		mc.snapmirror.AssertNumberOfCalls(t, "SnapmirrorRelationshipModify", 1)
+	})
+}
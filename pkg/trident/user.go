@@ -2,11 +2,15 @@ package trident
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 
-	"github.com/sethvargo/go-password/password"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/tracing"
 
 	ontapv1 "github.com/metal-stack/ontap-go/api/client"
 	"github.com/metal-stack/ontap-go/api/models"
@@ -39,6 +43,19 @@ type userAndSecretOptions struct {
 	svmSeedSecretNamespace string
 	seedClient             client.Client
 	svmUUID                string
+	seed                   string
+	secretOwner            client.Object
+	passwordPolicy         *config.PasswordPolicyConfig
+	// certificateAuthentication switches the account to client certificate authentication if set
+	certificateAuthentication *config.CertificateAuthenticationConfig
+	// accountRole is the ONTAP role of the account, defaults to vsadmin
+	accountRole string
+	// naming renders the name of the credentials secret, the name of earlier versions is used if nil
+	naming *Naming
+	// svmAliases are the names of the SVM of earlier versions, secrets named after them are migrated
+	svmAliases []string
+	// handOver hands the account of the shoot over to seed if it is owned by another seed
+	handOver bool
 }
 
 // ontapUserOptions holds parameters for CreateONTAPUserForSVM
@@ -47,6 +64,9 @@ type ontapUserOptions struct {
 	svmName          string
 	kubeSeedSecretNs string
 	svmUUID          string
+	comment          string
+	authMethod       string // defaults to password authentication
+	role             string
 }
 
 func extractShootNameFromNamespace(namespace string) (string, error) {
@@ -68,8 +88,33 @@ func extractShootNameFromNamespace(namespace string) (string, error) {
 	return shootName, nil
 }
 
+const (
+	// maxUsernameLength keeps usernames well below the ONTAP limit of 40 characters
+	maxUsernameLength = 25
+	// usernameHashLength is the number of hex characters of the hash appended to truncated usernames
+	usernameHashLength = 8
+)
+
 // ONTAP username requirements: A-Z, a-z, 0-9, ".", "_", "-" (cannot start with "-"), max 40 chars
+// Shoot names which exceed the maximum length are truncated and suffixed with a hash of the full shoot name,
+// so shoots with a common prefix get distinct accounts on the SVM of their project.
 func getClusterUsername(shootNamespace string) (string, error) {
+	shootName, err := legacyClusterUsername(shootNamespace)
+	if err != nil {
+		return "", err
+	}
+	fullName, _ := extractShootNameFromNamespace(shootNamespace)
+	if len(fullName) <= maxUsernameLength {
+		return shootName, nil
+	}
+
+	hash := sha256.Sum256([]byte(fullName))
+	prefix := strings.TrimRight(shootName[:maxUsernameLength-usernameHashLength-1], "-")
+	return prefix + "-" + hex.EncodeToString(hash[:])[:usernameHashLength], nil
+}
+
+// legacyClusterUsername returns the username of earlier versions of the extension, which only truncated the shoot name.
+func legacyClusterUsername(shootNamespace string) (string, error) {
 	// Extract shoot name from namespace
 	shootName, err := extractShootNameFromNamespace(shootNamespace)
 	if err != nil {
@@ -81,9 +126,8 @@ func getClusterUsername(shootNamespace string) (string, error) {
 		shootName = "s" + shootName[1:]
 	}
 
-	// ONTAP username limit is 40 characters, we use 25 to keep it reasonable
-	if len(shootName) > 25 {
-		shootName = shootName[:25]
+	if len(shootName) > maxUsernameLength {
+		shootName = shootName[:maxUsernameLength]
 	}
 
 	return shootName, nil
@@ -97,34 +141,83 @@ func (m *SvmManager) validateAndEnsureCompleteUserState(ctx context.Context, ont
 		return fmt.Errorf("failed to generate cluster username: %w", err)
 	}
 
-	// Remove "shoot--" prefix from namespace for cleaner secret name
-	shootNamespaceForSecret := strings.TrimPrefix(opts.shootNamespace, "shoot--")
-	secretName := fmt.Sprintf(ClusterSecretNameFormat, opts.projectID, shootNamespaceForSecret)
+	secretName := opts.naming.SecretName(opts.projectID, opts.shootNamespace)
 
-	// 1. Check K8s secret state first
-	existingPassword, secretErr := m.checkIfAccountExistsForSvm(ctx, secretName, opts.svmSeedSecretNamespace)
+	// 0. Move a secret created by an earlier version of the extension into the shoot namespace and to its current name
+	for _, name := range append(opts.naming.SecretAliases(opts.projectID, opts.svmAliases, opts.shootNamespace), secretName) {
+		if err := m.migrateLegacySeedSecret(ctx, name, opts); err != nil {
+			return err
+		}
+	}
+	if err := m.migrateRenamedSeedSecrets(ctx, secretName, opts); err != nil {
+		return err
+	}
+
+	// 1. Ensure the custom role exists before accounts are assigned to it
+	if opts.accountRoleName() == TridentRoleName {
+		if err := m.ensureTridentRole(ctx, ontapClient, opts); err != nil {
+			return err
+		}
+	}
 
 	// 2. Check if ontap user exists already
-	ontapUserExists, _, userErr := m.validateONTAPUserExists(ctx, ontapClient, clusterUsername, opts)
+	account, userErr := m.getONTAPAccount(ctx, ontapClient, clusterUsername, opts)
+	ontapUserExists := account != nil
+	if ontapUserExists {
+		// Never touch an account which was not created by us
+		if err := m.ensureAccountOwnership(ctx, ontapClient, clusterUsername, account.Comment, opts); err != nil {
+			return err
+		}
+		if err := m.ensureAccountRole(ctx, ontapClient, account, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.certificateAuthentication != nil {
+		if userErr != nil {
+			return fmt.Errorf("failed to check ONTAP user state: %w", userErr)
+		}
+		return m.ensureCertificateUserState(ctx, ontapClient, clusterUsername, secretName, ontapUserExists, opts)
+	}
+
+	// 3. Check K8s secret state
+	existingPassword, secretErr := m.checkIfAccountExistsForSvm(ctx, secretName, opts.svmSeedSecretNamespace)
 
-	// 3. Determine what needs to be created/updated
+	// 4. Determine what needs to be created/updated
 	switch {
 	// Both exist - validate password consistency
 	case errors.Is(secretErr, ErrAlreadyExists) && ontapUserExists:
-		return m.validatePasswordConsistency(ctx, clusterUsername, secretName, opts, existingPassword)
+		if err := m.validatePasswordConsistency(ctx, clusterUsername, secretName, opts, existingPassword); err != nil {
+			return err
+		}
+		return m.ensureSecretUsername(ctx, secretName, clusterUsername, opts)
 	// Secret exists but ONTAP user missing - create ONTAP user with existing password
 	case errors.Is(secretErr, ErrAlreadyExists) && !ontapUserExists:
 		m.log.Info("K8s secret exists but ONTAP user missing, creating ONTAP user", "svm", opts.projectID, "user", clusterUsername)
-		return m.createONTAPUserWithPassword(ctx, ontapClient, clusterUsername, opts, existingPassword)
+		if err := m.createONTAPUserWithPassword(ctx, ontapClient, clusterUsername, opts, existingPassword); err != nil {
+			return err
+		}
+		m.recorder.Normal(ctx, events.ReasonAccountCreated, events.ActionRepair, "missing account %s recreated on SVM %s", clusterUsername, opts.projectID)
+		// the secret still contains the legacy username if the account was created for a migrated username
+		return m.ensureSecretUsername(ctx, secretName, clusterUsername, opts)
 
 	case errors.Is(secretErr, ErrSeedSecretMissing) && ontapUserExists:
 		// ONTAP user exists but secret missing - create secret with new password
 		m.log.Info("ONTAP user exists but K8s secret missing, updating password and creating secret", "svm", opts.projectID, "user", clusterUsername)
+		// the account may authenticate with a client certificate if certificate authentication was disabled
+		if err := m.ensureAccountAuthMethod(ctx, ontapClient, clusterUsername, authMethodPassword, opts); err != nil {
+			return err
+		}
 		newPassword, err := m.resetONTAPUserPassword(ctx, ontapClient, clusterUsername, opts)
 		if err != nil {
 			return err
 		}
-		return m.buildAndCreateSecretInSeed(ctx, secretName, clusterUsername, newPassword, opts.projectID)
+		m.recorder.Normal(ctx, events.ReasonPasswordReset, events.ActionRepair, "password of account %s on SVM %s reset because the seed secret was missing", clusterUsername, opts.projectID)
+		if err := m.buildAndCreateSecretInSeed(ctx, secretName, clusterUsername, newPassword, opts); err != nil {
+			return err
+		}
+		m.recorder.Normal(ctx, events.ReasonSecretCreated, events.ActionRepair, "seed secret %s recreated", secretName)
+		return nil
 
 	case errors.Is(secretErr, ErrSeedSecretMissing) && !ontapUserExists:
 		// Neither exists - create both
@@ -142,41 +235,72 @@ func (m *SvmManager) validateAndEnsureCompleteUserState(ctx context.Context, ont
 	}
 }
 
-// validateONTAPUserExists checks if ONTAP user exists by querying accounts
-func (m *SvmManager) validateONTAPUserExists(ctx context.Context, ontapClient *ontapv1.Ontap, username string, opts userAndSecretOptions) (bool, string, error) {
+// getONTAPAccount returns the account with the given name on the SVM, it is nil if the account does not exist
+func (m *SvmManager) getONTAPAccount(ctx context.Context, ontapClient *ontapv1.Ontap, username string, opts userAndSecretOptions) (*models.Account, error) {
 	params := security.NewAccountCollectionGetParamsWithContext(ctx)
 	params.SetOwnerUUID(&opts.svmUUID)
 	params.SetName(&username)
+	params.SetFields([]string{"name", "comment", "role"})
 
 	result, err := ontapClient.Security.AccountCollectionGet(params, nil)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to query ONTAP users: %w", err)
+		return nil, fmt.Errorf("failed to query ONTAP users: %w", err)
 	}
 
 	if result.Payload != nil && len(result.Payload.AccountResponseInlineRecords) > 0 {
-		return true, "", nil // User exists
+		return result.Payload.AccountResponseInlineRecords[0], nil
 	}
 
-	return false, "", nil // User doesn't exist
+	return nil, nil // User doesn't exist
+}
+
+// ensureAccountOwnership fails if the account is owned by another seed or was not created by the extension.
+// Accounts created before ownership was recorded are adopted by writing the ownership into their comment, the account
+// of a shoot whose control plane was migrated is handed over to the seed of the restore.
+func (m *SvmManager) ensureAccountOwnership(ctx context.Context, ontapClient *ontapv1.Ontap, username string, comment *string, opts userAndSecretOptions) error {
+	adopt, err := checkOwnership("account", username, comment, opts.seed)
+	if errors.Is(err, ErrNotOwned) && opts.handOver && ownedByShoot(comment, opts.shootNamespace) {
+		m.log.Info("Handing over account of the migrated shoot", "svm", opts.projectID, "user", username, "seed", opts.seed)
+		adopt, err = true, nil
+	}
+	if err != nil || !adopt {
+		return err
+	}
+
+	m.log.Info("Recording ownership of existing account", "svm", opts.projectID, "user", username)
+	params := security.NewAccountModifyParamsWithContext(ctx)
+	params.SetOwnerUUID(opts.svmUUID)
+	params.SetName(username)
+	params.SetInfo(&models.Account{Comment: new(NewOwnership(opts.seed, opts.shootNamespace, opts.projectID).Comment())})
+	if _, err := ontapClient.Security.AccountModify(params, nil); err != nil {
+		return fmt.Errorf("failed to record ownership of account %s: %w", username, err)
+	}
+	return nil
 }
 
 // attemptUserCreation tries to create a user with given password
 func (m *SvmManager) attemptUserCreation(ctx context.Context, ontapClient *ontapv1.Ontap, opts ontapUserOptions, password string) (string, error) {
 	var (
 		application = "http"
-		authMethod  = "password"
-		pwdVal      = strfmt.Password(password)
-		vsadminRole = "vsadmin"
+		authMethod  = authMethodPassword
+		pwdVal      *strfmt.Password
 	)
+	if opts.authMethod != "" {
+		authMethod = opts.authMethod
+	}
+	if password != "" {
+		pwdVal = new(strfmt.Password(password))
+	}
 
 	createAccountParams := security.NewAccountCreateParamsWithContext(ctx)
 	createAccountParams.SetInfo(&models.Account{
 		Name:     new(opts.username),
-		Password: &pwdVal,
+		Password: pwdVal,
 		Role: &models.AccountInlineRole{
-			Name: new(vsadminRole),
+			Name: new(opts.role),
 		},
-		Locked: new(false),
+		Locked:  new(false),
+		Comment: new(opts.comment),
 		Owner: &models.AccountInlineOwner{
 			UUID: new(opts.svmUUID),
 		},
@@ -201,6 +325,8 @@ func (m *SvmManager) createONTAPUserWithPassword(ctx context.Context, ontapClien
 		svmName:          opts.projectID,
 		kubeSeedSecretNs: opts.svmSeedSecretNamespace,
 		svmUUID:          opts.svmUUID,
+		comment:          NewOwnership(opts.seed, opts.shootNamespace, opts.projectID).Comment(),
+		role:             opts.accountRoleName(),
 	}
 
 	_, err := m.attemptUserCreation(ctx, ontapClient, ontapOpts, password)
@@ -214,7 +340,10 @@ func (m *SvmManager) createONTAPUserWithPassword(ctx context.Context, ontapClien
 
 // resetONTAPUserPassword updates existing user password
 func (m *SvmManager) resetONTAPUserPassword(ctx context.Context, ontapClient *ontapv1.Ontap, username string, opts userAndSecretOptions) (string, error) {
-	password, err := generateSecurePassword()
+	if err := m.validatePasswordPolicy(ctx, ontapClient); err != nil {
+		return "", err
+	}
+	password, err := generatePassword(opts.passwordPolicy)
 	if err != nil {
 		return "", err
 	}
@@ -229,7 +358,10 @@ func (m *SvmManager) resetONTAPUserPassword(ctx context.Context, ontapClient *on
 
 // createCompleteUserAndSecret creates both ONTAP user and K8s secret
 func (m *SvmManager) createCompleteUserAndSecret(ctx context.Context, ontapClient *ontapv1.Ontap, username string, secretName string, opts userAndSecretOptions) error {
-	password, err := generateSecurePassword()
+	if err := m.validatePasswordPolicy(ctx, ontapClient); err != nil {
+		return err
+	}
+	password, err := generatePassword(opts.passwordPolicy)
 	if err != nil {
 		return err
 	}
@@ -240,6 +372,8 @@ func (m *SvmManager) createCompleteUserAndSecret(ctx context.Context, ontapClien
 		svmName:          opts.projectID,
 		kubeSeedSecretNs: opts.svmSeedSecretNamespace,
 		svmUUID:          opts.svmUUID,
+		comment:          NewOwnership(opts.seed, opts.shootNamespace, opts.projectID).Comment(),
+		role:             opts.accountRoleName(),
 	}
 
 	_, err = m.attemptUserCreation(ctx, ontapClient, ontapOpts, password)
@@ -248,12 +382,13 @@ func (m *SvmManager) createCompleteUserAndSecret(ctx context.Context, ontapClien
 	}
 
 	// Create K8s secret
-	err = m.buildAndCreateSecretInSeed(ctx, secretName, username, password, opts.projectID)
+	err = m.buildAndCreateSecretInSeed(ctx, secretName, username, password, opts)
 	if err != nil {
 		return fmt.Errorf("failed to create K8s secret after ONTAP user creation: %w", err)
 	}
 
 	m.log.Info("Successfully created complete user and secret", "svm", opts.projectID, "user", username)
+	m.recorder.Normal(ctx, events.ReasonAccountCreated, events.ActionCreate, "account %s and seed secret %s created on SVM %s", username, secretName, opts.projectID)
 	return nil
 }
 
@@ -266,14 +401,15 @@ func (m *SvmManager) validatePasswordConsistency(ctx context.Context, username s
 }
 
 // updateSecretInSeed updates an existing secret in the seed cluster
-func (m *SvmManager) updateSecretInSeed(ctx context.Context, secretName, namespace, username, password, projectId string) error {
+func (m *SvmManager) updateSecretInSeed(ctx context.Context, secretName, username, password string, opts userAndSecretOptions) error {
+	namespace := opts.svmSeedSecretNamespace
 	// Try to get existing secret first
 	existingSecret := &corev1.Secret{}
 	err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, existingSecret)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Secret doesn't exist, create it
-			return m.buildAndCreateSecretInSeed(ctx, secretName, username, password, projectId)
+			return m.buildAndCreateSecretInSeed(ctx, secretName, username, password, opts)
 		}
 		return fmt.Errorf("failed to get existing secret: %w", err)
 	}
@@ -284,6 +420,9 @@ func (m *SvmManager) updateSecretInSeed(ctx context.Context, secretName, namespa
 		"password": []byte(password),
 	}
 	existingSecret.StringData = nil
+	if err := m.protectSeedSecret(existingSecret, opts); err != nil {
+		return err
+	}
 
 	if err := m.seedClient.Update(ctx, existingSecret); err != nil {
 		return fmt.Errorf("failed to update secret in seed: %w", err)
@@ -294,7 +433,10 @@ func (m *SvmManager) updateSecretInSeed(ctx context.Context, secretName, namespa
 }
 
 // CreateUserAndSecret creates an svm scoped account set to vsadmin role.
-func (m *SvmManager) CreateUserAndSecret(ctx context.Context, ontapClient *ontapv1.Ontap, opts userAndSecretOptions) error {
+func (m *SvmManager) CreateUserAndSecret(ctx context.Context, ontapClient *ontapv1.Ontap, opts userAndSecretOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "EnsureUserAndSecret")
+	defer func() { tracing.End(span, err) }()
+
 	m.log.Info("Ensuring complete user and secret state", "svm", opts.projectID)
 
 	// Use comprehensive validation instead of simple creation
@@ -349,23 +491,13 @@ func (m *SvmManager) checkIfAccountExistsForSvm(ctx context.Context, secretName
 	return "", ErrSeedSecretMissing
 }
 
-func generateSecurePassword() (string, error) {
-	// Generate a password that is 8 characters long with 2 digits, 0 symbols,
-	// allowing upper and lower case letters, disallowing repeat characters.
-	res, err := password.Generate(8, 2, 0, false, false)
-	if err != nil {
-		return "", fmt.Errorf("unable to create a random password:%w", err)
-	}
-	return res, nil
-}
-
 // buildSecret creates a secret with the SVM credentials in the specified namespace
-func buildSecret(secretName, userName, password, projectId string) *corev1.Secret {
+func buildSecret(secretName, namespace, userName, password, projectId string) *corev1.Secret {
 	// Build and return a Kubernetes secret
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
-			Namespace: svmSeedSecretNamespace,
+			Namespace: namespace,
 			Labels: map[string]string{
 				"app.kubernetes.io/part-of":       "gardener-extension-ontap",
 				"app.kubernetes.io/managed-by":    "gardener",
@@ -380,14 +512,16 @@ func buildSecret(secretName, userName, password, projectId string) *corev1.Secre
 	}
 }
 
-func (m *SvmManager) buildAndCreateSecretInSeed(ctx context.Context, secretName, userName, password, projectId string) error {
-	tridentSecret := buildSecret(secretName, userName, password, projectId)
-	// make this use of managedResource aswell, otherwise seed secret can be deleted
+func (m *SvmManager) buildAndCreateSecretInSeed(ctx context.Context, secretName, userName, password string, opts userAndSecretOptions) error {
+	tridentSecret := buildSecret(secretName, opts.svmSeedSecretNamespace, userName, password, opts.projectID)
+	if err := m.protectSeedSecret(tridentSecret, opts); err != nil {
+		return err
+	}
 	if err := m.seedClient.Create(ctx, tridentSecret); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			// Secret already exists, try to update it instead
 			m.log.Info("Secret already exists, updating it", "secretName", secretName)
-			return m.updateSecretInSeed(ctx, secretName, svmSeedSecretNamespace, userName, password, projectId)
+			return m.updateSecretInSeed(ctx, secretName, userName, password, opts)
 		}
 		return fmt.Errorf("creating secret in seed failed: %w", err)
 	}
@@ -2,17 +2,22 @@ package trident
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/metal-stack/gardener-extension-ontap/pkg/redact"
 	ontapv1 "github.com/metal-stack/ontap-go/api/client"
 	"github.com/metal-stack/ontap-go/api/client/security"
 	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -28,7 +33,7 @@ func TestValidateAndEnsureCompleteUserState(t *testing.T) {
 		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
 			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
 				AccountResponseInlineRecords: []*models.Account{
-					{Name: new("myshoot")},
+					{Name: new("myshoot"), Comment: new(NewOwnership("seed-a", "shoot--proj--myshoot", "proj-1").Comment())},
 				},
 			}}, nil)
 
@@ -45,13 +50,14 @@ func TestValidateAndEnsureCompleteUserState(t *testing.T) {
 		}
 		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existingSecret).Build()
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
 		err := m.validateAndEnsureCompleteUserState(ctx, mc.client, userAndSecretOptions{
 			projectID:              "proj-1",
 			shootNamespace:         "shoot--proj--myshoot",
 			svmSeedSecretNamespace: "kube-system",
 			seedClient:             k8s,
 			svmUUID:                "svm-uuid-1",
+			seed:                   "seed-a",
 		})
 		require.NoError(t, err)
 	})
@@ -72,17 +78,192 @@ func TestValidateAndEnsureCompleteUserState(t *testing.T) {
 		// No pre-existing K8s secret
 		k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
 
-		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s)
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
 		err := m.validateAndEnsureCompleteUserState(ctx, mc.client, userAndSecretOptions{
 			projectID:              "proj-1",
 			shootNamespace:         "shoot--proj--myshoot",
 			svmSeedSecretNamespace: "kube-system",
 			seedClient:             k8s,
 			svmUUID:                "svm-uuid-1",
+			seed:                   "seed-a",
 		})
 		require.NoError(t, err)
 
 		// Verify ONTAP user was created
 		mc.security.AssertCalled(t, "AccountCreate", mock.Anything, mock.Anything)
+		mc.security.AssertCalled(t, "AccountCreate", mock.MatchedBy(func(p *security.AccountCreateParams) bool {
+			o, ok := ParseOwnership(*p.Info.Comment)
+			return ok && o.Seed == "seed-a" && o.ShootNamespace == "shoot--proj--myshoot" && o.ProjectID == "proj-1"
+		}), mock.Anything)
 	})
+
+	t.Run("account without ownership is adopted", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+				AccountResponseInlineRecords: []*models.Account{{Name: new("myshoot")}},
+			}}, nil)
+		mc.security.On("AccountModify", mock.Anything, mock.Anything).Return(&security.AccountModifyOK{}, nil)
+
+		existingSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "proj-1-proj--myshoot-credentials", Namespace: "kube-system"},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("existing-pw")},
+		}
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existingSecret).Build()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		err := m.validateAndEnsureCompleteUserState(ctx, mc.client, userAndSecretOptions{
+			projectID:              "proj-1",
+			shootNamespace:         "shoot--proj--myshoot",
+			svmSeedSecretNamespace: "kube-system",
+			seedClient:             k8s,
+			svmUUID:                "svm-uuid-1",
+			seed:                   "seed-a",
+		})
+		require.NoError(t, err)
+		mc.security.AssertCalled(t, "AccountModify", mock.MatchedBy(func(p *security.AccountModifyParams) bool {
+			o, ok := ParseOwnership(*p.Info.Comment)
+			return ok && o.Seed == "seed-a" && p.Name == "myshoot" && p.OwnerUUID == "svm-uuid-1"
+		}), mock.Anything)
+	})
+
+	t.Run("account of another seed is not modified", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+				AccountResponseInlineRecords: []*models.Account{
+					{Name: new("myshoot"), Comment: new(NewOwnership("seed-b", "shoot--proj--myshoot", "proj-1").Comment())},
+				},
+			}}, nil)
+
+		// secret is missing, which would reset the password of an owned account
+		k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		err := m.validateAndEnsureCompleteUserState(ctx, mc.client, userAndSecretOptions{
+			projectID:              "proj-1",
+			shootNamespace:         "shoot--proj--myshoot",
+			svmSeedSecretNamespace: "kube-system",
+			seedClient:             k8s,
+			svmUUID:                "svm-uuid-1",
+			seed:                   "seed-a",
+		})
+		require.ErrorIs(t, err, ErrNotOwned)
+		mc.security.AssertNotCalled(t, "AccountPasswordCreate", mock.Anything, mock.Anything)
+		mc.security.AssertNotCalled(t, "AccountModify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("account of a migrated shoot is handed over on restore", func(t *testing.T) {
+		mc := newMockOntapClient()
+		mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+			Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+				AccountResponseInlineRecords: []*models.Account{
+					{Name: new("myshoot"), Comment: new(NewOwnership("seed-b", "shoot--proj--myshoot", "proj-1").Comment())},
+				},
+			}}, nil)
+		mc.security.On("AccountModify", mock.Anything, mock.Anything).Return(&security.AccountModifyOK{}, nil)
+
+		existingSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "proj-1-proj--myshoot-credentials", Namespace: "kube-system"},
+			Data:       map[string][]byte{"username": []byte("myshoot"), "password": []byte("existing-pw")},
+		}
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existingSecret).Build()
+
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+		opts := userAndSecretOptions{
+			projectID:              "proj-1",
+			shootNamespace:         "shoot--proj--myshoot",
+			svmSeedSecretNamespace: "kube-system",
+			seedClient:             k8s,
+			svmUUID:                "svm-uuid-1",
+			seed:                   "seed-a",
+			handOver:               true,
+		}
+		require.NoError(t, m.validateAndEnsureCompleteUserState(ctx, mc.client, opts))
+		mc.security.AssertCalled(t, "AccountModify", mock.MatchedBy(func(p *security.AccountModifyParams) bool {
+			o, ok := ParseOwnership(*p.Info.Comment)
+			return ok && o.Seed == "seed-a" && o.ShootNamespace == "shoot--proj--myshoot"
+		}), mock.Anything)
+
+		// accounts of other shoots are never handed over
+		opts.shootNamespace = "shoot--proj--other"
+		require.ErrorIs(t, m.ensureAccountOwnership(ctx, mc.client, "myshoot", new(NewOwnership("seed-b", "shoot--proj--myshoot", "proj-1").Comment()), opts), ErrNotOwned)
+	})
+}
+
+func TestGeneratedPasswordsAreNotLogged(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name        string
+		ontapUsers  []*models.Account
+		setupMocks  func(mc *mockOntapClient)
+		wantPWReset bool
+	}{
+		{
+			name:       "user and secret created",
+			ontapUsers: []*models.Account{},
+			setupMocks: func(mc *mockOntapClient) {
+				mc.security.On("AccountCreate", mock.Anything, mock.Anything).
+					Return(&security.AccountCreateCreated{}, nil)
+			},
+		},
+		{
+			name: "password reset because secret is missing",
+			ontapUsers: []*models.Account{{
+				Name:                      new("myshoot"),
+				Comment:                   new(NewOwnership("", "shoot--proj--myshoot", "proj-1").Comment()),
+				AccountInlineApplications: []*models.AccountApplication{{Application: new("http"), AuthenticationMethods: []*string{new("password")}}},
+			}},
+			setupMocks: func(mc *mockOntapClient) {
+				mc.security.On("AccountPasswordCreate", mock.Anything, mock.Anything).
+					Return(&security.AccountPasswordCreateCreated{}, nil)
+			},
+			wantPWReset: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newMockOntapClient()
+			mc.security.On("AccountCollectionGet", mock.Anything, mock.Anything).
+				Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+					AccountResponseInlineRecords: tt.ontapUsers,
+				}}, nil)
+			tt.setupMocks(mc)
+
+			var logs strings.Builder
+			log := redact.Logger(funcr.New(func(prefix, args string) {
+				logs.WriteString(prefix + args + "\n")
+			}, funcr.Options{Verbosity: 10}))
+
+			k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
+			m := NewSvmManager(log, []*ontapv1.Ontap{mc.client}, k8s, nil)
+			err := m.validateAndEnsureCompleteUserState(ctx, mc.client, userAndSecretOptions{
+				projectID:              "proj-1",
+				shootNamespace:         "shoot--proj--myshoot",
+				svmSeedSecretNamespace: "kube-system",
+				seedClient:             k8s,
+				svmUUID:                "svm-uuid-1",
+			})
+			require.NoError(t, err)
+
+			if tt.wantPWReset {
+				mc.security.AssertCalled(t, "AccountPasswordCreate", mock.Anything, mock.Anything)
+			}
+
+			secret := &corev1.Secret{}
+			require.NoError(t, k8s.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: "proj-1-proj--myshoot-credentials"}, secret))
+			password := secret.StringData["password"]
+			if password == "" {
+				password = string(secret.Data["password"])
+			}
+			require.NotEmpty(t, password)
+			require.NotEmpty(t, logs.String())
+
+			assert.NotContains(t, logs.String(), password)
+		})
+	}
 }
@@ -0,0 +1,142 @@
+package trident
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/metal-stack/ontap-go/api/client/security"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/metal-stack/gardener-extension-ontap/pkg/events"
+)
+
+// ensureSecretUsername updates the username in the seed secret, e.g. after the account was migrated to a new username.
+func (m *SvmManager) ensureSecretUsername(ctx context.Context, secretName, username string, opts userAndSecretOptions) error {
+	secret := &corev1.Secret{}
+	if err := m.seedClient.Get(ctx, client.ObjectKey{Namespace: opts.svmSeedSecretNamespace, Name: secretName}, secret); err != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %w", opts.svmSeedSecretNamespace, secretName, err)
+	}
+	if string(secret.Data["username"]) == username {
+		return nil
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	previous := string(secret.Data["username"])
+	secret.Data["username"] = []byte(username)
+	if err := m.seedClient.Patch(ctx, secret, patch); err != nil {
+		return fmt.Errorf("failed to update username in secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+
+	m.log.Info("Updated username in seed secret", "secretName", secretName, "user", username, "previousUser", previous)
+	return nil
+}
+
+// LegacyAccount returns the name of the account of the shoot which was created by an earlier version of the extension
+// with a truncated username, it is empty if no such account exists. Accounts created for another shoot whose name was
+// truncated to the same username are not returned, they are migrated by that shoot.
+func (m *SvmManager) LegacyAccount(ctx context.Context, opts CreateSVMOptions) (string, error) {
+	username, err := getClusterUsername(opts.ShootNamespace)
+	if err != nil {
+		return "", err
+	}
+	legacy, err := legacyClusterUsername(opts.ShootNamespace)
+	if err != nil || legacy == username {
+		return "", err
+	}
+
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return "", fmt.Errorf("failed to find SVM %s: %w", opts.ProjectID, err)
+	}
+
+	params := security.NewAccountCollectionGetParamsWithContext(ctx)
+	params.SetOwnerUUID(svmUUID)
+	params.SetName(&legacy)
+	params.SetFields([]string{"name", "comment"})
+	result, err := ontapClient.Security.AccountCollectionGet(params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to query ONTAP users: %w", err)
+	}
+	if result.Payload == nil || len(result.Payload.AccountResponseInlineRecords) == 0 {
+		return "", nil
+	}
+
+	account := result.Payload.AccountResponseInlineRecords[0]
+	if account.Comment == nil {
+		return "", nil
+	}
+	ownership, ok := ParseOwnership(*account.Comment)
+	if !ok || ownership.ShootNamespace != opts.ShootNamespace || (ownership.Seed != "" && ownership.Seed != opts.Seed) {
+		return "", nil
+	}
+
+	// another shoot whose name was truncated to the same username may still use the account
+	inUse, err := m.legacyAccountInUse(ctx, opts, legacy)
+	if err != nil || inUse {
+		return "", err
+	}
+	return legacy, nil
+}
+
+// legacyAccountInUse returns whether the seed secret of another shoot of the project still contains the legacy username.
+func (m *SvmManager) legacyAccountInUse(ctx context.Context, opts CreateSVMOptions, legacy string) (bool, error) {
+	secrets := &corev1.SecretList{}
+	if err := m.seedClient.List(ctx, secrets, client.MatchingLabels{
+		"app.kubernetes.io/part-of":       "gardener-extension-ontap",
+		"ontap.metal-stack.io/project-id": opts.ProjectID,
+	}); err != nil {
+		return false, fmt.Errorf("failed to list secrets of SVM %s: %w", opts.ProjectID, err)
+	}
+
+	for _, secret := range secrets.Items {
+		if secret.Namespace == opts.SvmSeedSecretNamespace {
+			continue
+		}
+		if string(secret.Data["username"]) == legacy {
+			m.log.Info("Legacy account is still in use by another shoot", "svm", opts.ProjectID, "user", legacy, "namespace", secret.Namespace)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoveLegacyAccount deletes the account with the legacy username of the shoot, it must only be called after the
+// Trident backend of the shoot switched to the new username.
+func (m *SvmManager) RemoveLegacyAccount(ctx context.Context, opts CreateSVMOptions, legacy string) error {
+	svmUUID, ontapClient, err := m.GetSVMByName(ctx, opts.ProjectID, opts.SVMAliases...)
+	if err != nil {
+		return fmt.Errorf("failed to find SVM %s: %w", opts.ProjectID, err)
+	}
+
+	params := security.NewAccountDeleteParamsWithContext(ctx)
+	params.SetOwnerUUID(*svmUUID)
+	params.SetName(legacy)
+	if _, err := ontapClient.Security.AccountDelete(params, nil); err != nil {
+		return fmt.Errorf("failed to delete legacy account %s of SVM %s: %w", legacy, opts.ProjectID, err)
+	}
+
+	m.log.Info("Removed legacy account", "svm", opts.ProjectID, "user", legacy)
+	m.recorder.Normal(ctx, events.ReasonAccountMigrated, events.ActionDelete, "account %s on SVM %s replaced by a collision-free username", legacy, opts.ProjectID)
+	return nil
+}
+
+// ShootAccountNames returns the current and the legacy name of the SVM account of the shoot in the given namespace,
+// the legacy name is only returned if it differs.
+func ShootAccountNames(shootNamespace string) ([]string, error) {
+	username, err := getClusterUsername(shootNamespace)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := legacyClusterUsername(shootNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if legacy == username {
+		return []string{username}, nil
+	}
+	return []string{username, legacy}, nil
+}
@@ -0,0 +1,142 @@
+package trident
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ontapv1 "github.com/metal-stack/ontap-go/api/client"
+	"github.com/metal-stack/ontap-go/api/client/s_vm"
+	"github.com/metal-stack/ontap-go/api/client/security"
+	"github.com/metal-stack/ontap-go/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetClusterUsername(t *testing.T) {
+	a, err := getClusterUsername("shoot--proj--production-frontend-cluster-a")
+	require.NoError(t, err)
+	b, err := getClusterUsername("shoot--proj--production-frontend-cluster-b")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.LessOrEqual(t, len(a), maxUsernameLength)
+	assert.Regexp(t, `^production-front-[0-9a-f]{8}$`, a)
+
+	again, err := getClusterUsername("shoot--proj--production-frontend-cluster-a")
+	require.NoError(t, err)
+	assert.Equal(t, a, again)
+
+	short, err := getClusterUsername("shoot--proj--myshoot")
+	require.NoError(t, err)
+	assert.Equal(t, "myshoot", short)
+
+	names, err := ShootAccountNames("shoot--proj--production-frontend-cluster-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, "production-frontend-clust"}, names)
+
+	names, err = ShootAccountNames("shoot--proj--myshoot")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"myshoot"}, names)
+}
+
+func TestLegacyAccount(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	const (
+		shootNamespace = "shoot--proj--production-frontend-cluster-a"
+		legacy         = "production-frontend-clust"
+	)
+	opts := CreateSVMOptions{
+		ProjectID:              "proj-1",
+		ShootNamespace:         shootNamespace,
+		SvmSeedSecretNamespace: shootNamespace,
+		Seed:                   "seed-a",
+	}
+	otherSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "shoot--proj--production-frontend-cluster-b",
+			Name:      "proj-1-proj--production-frontend-cluster-b-credentials",
+			Labels: map[string]string{
+				"app.kubernetes.io/part-of":       "gardener-extension-ontap",
+				"ontap.metal-stack.io/project-id": "proj-1",
+			},
+		},
+		Data: map[string][]byte{"username": []byte(legacy)},
+	}
+
+	tests := []struct {
+		name    string
+		comment *string
+		objects []client.Object
+		want    string
+	}{
+		{name: "account of the shoot", comment: new(NewOwnership("seed-a", shootNamespace, "proj-1").Comment()), want: legacy},
+		{name: "account of another shoot", comment: new(NewOwnership("seed-a", "shoot--proj--production-frontend-cluster-b", "proj-1").Comment())},
+		{name: "account of another seed", comment: new(NewOwnership("seed-b", shootNamespace, "proj-1").Comment())},
+		{name: "account without ownership"},
+		{name: "account still used by another shoot", comment: new(NewOwnership("seed-a", shootNamespace, "proj-1").Comment()), objects: []client.Object{otherSecret}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newMockOntapClient()
+			mc.svm.On("SvmCollectionGet", mock.Anything, mock.Anything).
+				Return(&s_vm.SvmCollectionGetOK{Payload: &models.SvmResponse{
+					SvmResponseInlineRecords: []*models.Svm{{Name: new("proj-1"), UUID: new("uuid-1")}},
+				}}, nil)
+			mc.svm.On("SvmGet", mock.Anything, mock.Anything).
+				Return(&s_vm.SvmGetOK{Payload: &models.Svm{State: new("running")}}, nil)
+			mc.security.On("AccountCollectionGet", mock.MatchedBy(func(p *security.AccountCollectionGetParams) bool {
+				return p.Name != nil && *p.Name == legacy
+			}), mock.Anything).
+				Return(&security.AccountCollectionGetOK{Payload: &models.AccountResponse{
+					AccountResponseInlineRecords: []*models.Account{{Name: new(legacy), Comment: tt.comment}},
+				}}, nil)
+
+			k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, k8s, nil)
+			got, err := m.LegacyAccount(ctx, opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("short names have no legacy account", func(t *testing.T) {
+		mc := newMockOntapClient()
+		m := NewSvmManager(logr.Discard(), []*ontapv1.Ontap{mc.client}, nil, nil)
+		got, err := m.LegacyAccount(ctx, CreateSVMOptions{ProjectID: "proj-1", ShootNamespace: "shoot--proj--myshoot"})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		mc.security.AssertNotCalled(t, "AccountCollectionGet", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEnsureSecretUsername(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	key := client.ObjectKey{Namespace: "shoot--proj--myshoot", Name: "proj-1-proj--myshoot-credentials"}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string][]byte{"username": []byte("production-frontend-clust"), "password": []byte("secret")},
+	}).Build()
+
+	m := NewSvmManager(logr.Discard(), nil, k8s, nil)
+	require.NoError(t, m.ensureSecretUsername(ctx, key.Name, "production-front-0123abcd", userAndSecretOptions{svmSeedSecretNamespace: key.Namespace}))
+
+	secret := &corev1.Secret{}
+	require.NoError(t, k8s.Get(ctx, key, secret))
+	assert.Equal(t, "production-front-0123abcd", string(secret.Data["username"]))
+	assert.Equal(t, "secret", string(secret.Data["password"]))
+}